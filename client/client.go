@@ -0,0 +1,369 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultRetries    = 3
+	defaultMinBackoff = 100 * time.Millisecond
+	defaultMaxBackoff = 5 * time.Second
+	defaultTimeout    = 30 * time.Second
+
+	// maxErrorSize bounds the error bodies read.
+	maxErrorSize = 64 << 10
+	// maxLineSize bounds the tasks of filtered lists, which are read a line at a time.
+	maxLineSize = 1 << 20
+)
+
+type Client struct {
+	baseURL    string
+	apiKey     string
+	workspace  string
+	httpClient *http.Client
+	retries    int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+}
+
+type Option func(*Client)
+
+// WithAPIKey authenticates requests with an API key or any other bearer token.
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.apiKey = key
+	}
+}
+
+func WithWorkspace(id string) Option {
+	return func(c *Client) {
+		c.workspace = id
+	}
+}
+
+// WithHTTPClient sends requests with httpClient instead of one with a 30 second timeout.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// Idempotent calls are retried when the server is unreachable, overloaded or rate limits
+// the client, 3 times by default. Zero disables retries.
+func WithRetries(retries int) Option {
+	return func(c *Client) {
+		c.retries = max(retries, 0)
+	}
+}
+
+// WithBackoff sets the range of the wait before a retry, which doubles from min up to
+// max, with jitter. A Retry-After header of the server is honored up to max.
+func WithBackoff(minBackoff, maxBackoff time.Duration) Option {
+	return func(c *Client) {
+		c.minBackoff, c.maxBackoff = minBackoff, max(minBackoff, maxBackoff)
+	}
+}
+
+func New(baseURL string, options ...Option) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("client: %q is not an http or https URL", baseURL)
+	}
+
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: defaultTimeout},
+		retries:    defaultRetries,
+		minBackoff: defaultMinBackoff,
+		maxBackoff: defaultMaxBackoff,
+	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	return c, nil
+}
+
+// Each filter lists accepted values.
+type ListOption func(url.Values)
+
+func WithAssignees(assignees ...string) ListOption {
+	return listFilter("assignee", assignees)
+}
+
+func WithLabels(labels ...string) ListOption {
+	return listFilter("label", labels)
+}
+
+func WithStatuses(statuses ...string) ListOption {
+	return listFilter("status", statuses)
+}
+
+func listFilter(name string, values []string) ListOption {
+	return func(query url.Values) {
+		if len(values) > 0 {
+			query.Set(name, strings.Join(values, ","))
+		}
+	}
+}
+
+// Add isn't retried, which could add the task twice.
+func (c *Client) Add(ctx context.Context, task *Task) error {
+	request := createTaskRequest{
+		Title:       task.Title,
+		Description: task.Description,
+		Status:      task.Status,
+		Assignee:    task.Assignee,
+		DueAt:       task.DueAt,
+		RRule:       task.RRule,
+		TimeZone:    task.TimeZone,
+		Reminders:   task.Reminders,
+		Labels:      task.Labels,
+	}
+
+	return c.call(ctx, http.MethodPost, c.tasksPath(), request, task, false)
+}
+
+func (c *Client) Get(ctx context.Context, id string) (Task, error) {
+	var task Task
+
+	return task, c.call(ctx, http.MethodGet, c.taskPath(id), nil, &task, true)
+}
+
+func (c *Client) GetAll(ctx context.Context, options ...ListOption) ([]Task, error) {
+	tasks := []Task{}
+
+	if len(options) == 0 {
+		return tasks, c.call(ctx, http.MethodGet, c.tasksPath(), nil, &tasks, true)
+	}
+
+	// Only exports filter tasks; their NDJSON is read as it arrives.
+	query := url.Values{"format": {"ndjson"}}
+	for _, option := range options {
+		option(query)
+	}
+
+	resp, err := c.do(ctx, http.MethodGet, c.tasksPath()+"/export?"+query.Encode(), nil, true)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxLineSize)
+
+	for scanner.Scan() {
+		var task Task
+
+		if err := json.Unmarshal(scanner.Bytes(), &task); err != nil {
+			return nil, err
+		}
+
+		tasks = append(tasks, task)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// An empty assignee and nil reminders and labels keep the current ones. Due dates change
+// with Reschedule.
+func (c *Client) Update(ctx context.Context, task *Task) error {
+	request := updateTaskRequest{
+		Title:       task.Title,
+		Description: task.Description,
+		Status:      task.Status,
+		Assignee:    task.Assignee,
+		Reminders:   task.Reminders,
+		Labels:      task.Labels,
+	}
+
+	// Updates replace fields rather than change them, so repeating one is harmless.
+	return c.call(ctx, http.MethodPatch, c.taskPath(task.ID), request, nil, true)
+}
+
+// A retry after a deletion whose response was lost returns ErrNotFound.
+func (c *Client) Delete(ctx context.Context, id string) error {
+	return c.call(ctx, http.MethodDelete, c.taskPath(id), nil, nil, true)
+}
+
+func (c *Client) Reschedule(ctx context.Context, id string, dueAt time.Time) (Task, error) {
+	var task Task
+
+	return task, c.call(ctx, http.MethodPost, c.taskPath(id)+"/reschedule", rescheduleTaskRequest{DueAt: dueAt}, &task, true)
+}
+
+func (c *Client) Skip(ctx context.Context, id string) (Task, error) {
+	var task Task
+
+	return task, c.call(ctx, http.MethodPost, c.taskPath(id)+"/skip", nil, &task, true)
+}
+
+func (c *Client) tasksPath() string {
+	if c.workspace == "" {
+		return "/tasks"
+	}
+
+	return "/workspaces/" + url.PathEscape(c.workspace) + "/tasks"
+}
+
+func (c *Client) taskPath(id string) string {
+	return c.tasksPath() + "/" + url.PathEscape(id)
+}
+
+func (c *Client) call(ctx context.Context, method, path string, body, out any, idempotent bool) error {
+	var data []byte
+
+	if body != nil {
+		var err error
+
+		if data, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	resp, err := c.do(ctx, method, path, data, idempotent)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if out == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body []byte, idempotent bool) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, method, path, body)
+
+		retry := idempotent && attempt < c.retries && ctx.Err() == nil &&
+			(err != nil || retryable(resp.StatusCode))
+
+		if !retry {
+			if err != nil {
+				return nil, err
+			}
+
+			if resp.StatusCode >= http.StatusBadRequest {
+				defer resp.Body.Close()
+				return nil, decodeError(resp)
+			}
+
+			return resp, nil
+		}
+
+		wait := c.backoff(attempt)
+
+		if resp != nil {
+			if after, ok := retryAfter(resp); ok {
+				wait = min(after, c.maxBackoff)
+			}
+
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorSize))
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *Client) send(ctx context.Context, method, path string, body []byte) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	return c.httpClient.Do(req)
+}
+
+// The jitter spreads out clients retrying together.
+func (c *Client) backoff(attempt int) time.Duration {
+	ceiling := c.maxBackoff
+	if attempt < 32 {
+		ceiling = min(c.minBackoff<<attempt, c.maxBackoff)
+	}
+
+	if ceiling <= 0 {
+		return 0
+	}
+
+	return ceiling/2 + rand.N(ceiling/2+1)
+}
+
+func retryable(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryAfter reads a Retry-After header in seconds, the form the server sends.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+
+	return time.Duration(seconds) * time.Second, true
+}
+
+func decodeError(resp *http.Response) error {
+	apiError := &Error{StatusCode: resp.StatusCode}
+
+	var body errorResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxErrorSize)).Decode(&body); err == nil {
+		apiError.Message, apiError.RequestID = body.Error, body.RequestID
+	}
+
+	if apiError.Message == "" {
+		apiError.Message = strings.ToLower(http.StatusText(resp.StatusCode))
+	}
+
+	if apiError.RequestID == "" {
+		apiError.RequestID = resp.Header.Get("X-Request-ID")
+	}
+
+	return apiError
+}
@@ -0,0 +1,244 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"task-tracker/internal/config"
+	"task-tracker/internal/server"
+)
+
+// newTestServer runs the real server in memory mode, behind wrap if it's given.
+func newTestServer(t *testing.T, cfg config.Config, wrap func(http.Handler) http.Handler) *httptest.Server {
+	t.Helper()
+
+	cfg.InMemory, cfg.LogLevel, cfg.ShutdownTimeout = true, "error", time.Second
+
+	srv := server.NewHTTPServer(cfg)
+
+	if err := srv.ConfigureServer(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var handler http.Handler = srv
+	if wrap != nil {
+		handler = wrap(handler)
+	}
+
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+func TestClient_Tasks(t *testing.T) {
+	ts := newTestServer(t, config.Config{}, nil)
+	ctx := context.Background()
+
+	c, err := New(ts.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	due := time.Date(2025, time.April, 14, 7, 0, 0, 0, time.UTC)
+
+	deploy := Task{Title: "Deploy", Description: "Roll out v1.2", Status: StatusTodo, Assignee: "alice",
+		DueAt: &due, Reminders: []Duration{Duration(time.Hour)}, Labels: []string{"ops"}}
+	if err := c.Add(ctx, &deploy); err != nil || deploy.ID == "" || deploy.CreatedAt.IsZero() {
+		t.Fatalf("returned %+v, %v; expected the created task", deploy, err)
+	}
+
+	review := Task{Title: "Review", Description: "Read the diff", Status: StatusDone, Assignee: "bob"}
+	if err := c.Add(ctx, &review); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := c.Get(ctx, deploy.ID)
+	if err != nil || got.Title != "Deploy" || len(got.Reminders) != 1 || got.Reminders[0] != Duration(time.Hour) || !got.DueAt.Equal(due) {
+		t.Fatalf("returned %+v, %v; expected %+v", got, err, deploy)
+	}
+
+	lists := map[string]struct {
+		options  []ListOption
+		expected []string
+	}{
+		"all":        {nil, []string{deploy.ID, review.ID}},
+		"by label":   {[]ListOption{WithLabels("ops", "dev")}, []string{deploy.ID}},
+		"by status":  {[]ListOption{WithStatuses(StatusDone), WithAssignees("bob")}, []string{review.ID}},
+		"none match": {[]ListOption{WithAssignees("carol")}, []string{}},
+	}
+
+	for name, test := range lists {
+		tasks, err := c.GetAll(ctx, test.options...)
+		if err != nil || len(tasks) != len(test.expected) {
+			t.Fatalf("test-case: (%q); returned %+v, %v; expected %v", name, tasks, err, test.expected)
+		}
+
+		ids := make(map[string]bool)
+		for _, task := range tasks {
+			ids[task.ID] = true
+		}
+
+		for _, id := range test.expected {
+			if !ids[id] {
+				t.Fatalf("test-case: (%q); returned %+v; expected %v", name, tasks, test.expected)
+			}
+		}
+	}
+
+	deploy.Status = "in_progress"
+	deploy.Labels = nil
+
+	if err := c.Update(ctx, &deploy); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	moved := due.Add(24 * time.Hour)
+
+	if task, err := c.Reschedule(ctx, deploy.ID, moved); err != nil || task.Status != "in_progress" ||
+		len(task.Labels) != 1 || !task.DueAt.Equal(moved) {
+		t.Fatalf("returned %+v, %v; expected the updated task rescheduled with its labels kept", task, err)
+	}
+
+	if err := c.Delete(ctx, deploy.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = c.Get(ctx, deploy.ID)
+
+	var apiError *Error
+	if !errors.Is(err, ErrNotFound) || !errors.As(err, &apiError) || apiError.Message != "task not found" || apiError.RequestID == "" {
+		t.Fatalf("returned %v; expected %v with a request id", err, ErrNotFound)
+	}
+}
+
+func TestClient_Errors(t *testing.T) {
+	ts := newTestServer(t, config.Config{AuthEnabled: true, TestIssuer: true}, nil)
+	ctx := context.Background()
+
+	unauthenticated, _ := New(ts.URL)
+	badToken, _ := New(ts.URL, WithAPIKey("not-a-token"))
+
+	tests := map[string]struct {
+		call     func() error
+		expected error
+	}{
+		"no credentials": {
+			call:     func() error { _, err := unauthenticated.GetAll(ctx); return err },
+			expected: ErrUnauthorized,
+		},
+		"invalid token": {
+			call:     func() error { _, err := badToken.Get(ctx, "id"); return err },
+			expected: ErrUnauthorized,
+		},
+		"add without credentials": {
+			call:     func() error { return unauthenticated.Add(ctx, &Task{Description: "d", Status: StatusTodo}) },
+			expected: ErrUnauthorized,
+		},
+	}
+
+	for name, test := range tests {
+		if err := test.call(); !errors.Is(err, test.expected) {
+			t.Fatalf("test-case: (%q); returned %v; expected %v", name, err, test.expected)
+		}
+	}
+
+	open := newTestServer(t, config.Config{}, nil)
+	c, _ := New(open.URL)
+
+	if err := c.Add(ctx, &Task{Description: "d", Status: StatusTodo}); !errors.Is(err, ErrBadRequest) {
+		t.Fatalf("returned %v; expected %v", err, ErrBadRequest)
+	}
+
+	c, _ = New(open.URL, WithWorkspace("missing"))
+
+	if _, err := c.Get(ctx, "id"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("returned %v; expected %v", err, ErrNotFound)
+	}
+
+	for _, baseURL := range []string{"", "tasks.example.com", "ftp://tasks.example.com", "http://"} {
+		if _, err := New(baseURL); err == nil {
+			t.Fatalf("test-case: (%q); returned no error; expected an invalid URL", baseURL)
+		}
+	}
+}
+
+func TestClient_Retries(t *testing.T) {
+	var (
+		requests atomic.Int32
+		failures atomic.Int32
+	)
+
+	// The first failures requests are answered with 503s before reaching the server.
+	ts := newTestServer(t, config.Config{}, func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+
+			if failures.Add(-1) >= 0 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusServiceUnavailable)
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	})
+
+	ctx := context.Background()
+	c, _ := New(ts.URL, WithRetries(2), WithBackoff(time.Millisecond, 5*time.Millisecond))
+
+	tests := map[string]struct {
+		failures int32
+		call     func() error
+		requests int32
+		expected error
+	}{
+		"idempotent call retried": {
+			failures: 2,
+			call:     func() error { _, err := c.GetAll(ctx); return err },
+			requests: 3,
+		},
+		"retries exhausted": {
+			failures: 3,
+			call:     func() error { _, err := c.GetAll(ctx); return err },
+			requests: 3,
+			expected: ErrServer,
+		},
+		"add not retried": {
+			failures: 1,
+			call:     func() error { return c.Add(ctx, &Task{Title: "t", Description: "d", Status: StatusTodo}) },
+			requests: 1,
+			expected: ErrServer,
+		},
+		"client errors not retried": {
+			call:     func() error { _, err := c.Get(ctx, "missing"); return err },
+			requests: 1,
+			expected: ErrNotFound,
+		},
+	}
+
+	for name, test := range tests {
+		requests.Store(0)
+		failures.Store(test.failures)
+
+		if err := test.call(); !errors.Is(err, test.expected) || requests.Load() != test.requests {
+			t.Fatalf("test-case: (%q); returned %v after %d requests; expected %v after %d",
+				name, err, requests.Load(), test.expected, test.requests)
+		}
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+
+	failures.Store(1)
+
+	if _, err := c.GetAll(canceled); !errors.Is(err, context.Canceled) {
+		t.Fatalf("returned %v; expected %v", err, context.Canceled)
+	}
+}
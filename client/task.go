@@ -0,0 +1,97 @@
+package client
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Statuses with a meaning to the server. Any other status is accepted as well.
+const (
+	StatusTodo     = "todo"
+	StatusDone     = "done"
+	StatusCanceled = "canceled"
+	StatusSkipped  = "skipped"
+)
+
+// Add and Update only send the fields clients can set.
+type Task struct {
+	ID          string    `json:"id"`
+	WorkspaceID string    `json:"workspace_id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Status      string    `json:"status"`
+	CreatedBy   string    `json:"created_by,omitempty"`
+	Assignee    string    `json:"assignee,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	DueAt        *time.Time `json:"due_at,omitempty"`
+	RRule        string     `json:"rrule,omitempty"`
+	TimeZone     string     `json:"time_zone,omitempty"`
+	SeriesID     string     `json:"series_id,omitempty"`
+	OccurrenceAt *time.Time `json:"occurrence_at,omitempty"`
+
+	// Reminders notify the assignee the given durations before DueAt.
+	Reminders      []Duration `json:"reminders,omitempty"`
+	RemindedAt     *time.Time `json:"reminded_at,omitempty"`
+	NextReminderAt *time.Time `json:"next_reminder_at,omitempty"`
+
+	Labels []string `json:"labels,omitempty"`
+
+	CalendarUID  string `json:"calendar_uid,omitempty"`
+	CalendarName string `json:"calendar_name,omitempty"`
+}
+
+// Duration is a time.Duration written as a Go duration string, such as "1h30m0s".
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value string
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(parsed)
+
+	return nil
+}
+
+type createTaskRequest struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Status      string     `json:"status"`
+	Assignee    string     `json:"assignee"`
+	DueAt       *time.Time `json:"due_at"`
+	RRule       string     `json:"rrule"`
+	TimeZone    string     `json:"time_zone"`
+	Reminders   []Duration `json:"reminders"`
+	Labels      []string   `json:"labels"`
+}
+
+type updateTaskRequest struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Status      string     `json:"status"`
+	Assignee    string     `json:"assignee"`
+	Reminders   []Duration `json:"reminders"`
+	Labels      []string   `json:"labels"`
+}
+
+type rescheduleTaskRequest struct {
+	DueAt time.Time `json:"due_at"`
+}
+
+type errorResponse struct {
+	Error     string `json:"error"`
+	RequestID string `json:"request_id"`
+}
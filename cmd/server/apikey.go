@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"task-tracker/internal/auth"
+	"task-tracker/internal/clock"
+	"task-tracker/internal/config"
+	"task-tracker/internal/models"
+	"task-tracker/internal/repository"
+)
+
+const apiKeyUsage = "usage: main [flags] apikey create -name NAME -scopes read,write,maintain,admin | list | revoke ID"
+
+func runAPIKey(ctx context.Context, cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New(apiKeyUsage)
+	}
+
+	pool, err := repository.CreateDBPool(ctx, cfg.DBConn, nil)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	keys := auth.NewAPIKeyService(repository.NewPostgresAPIKeyRepository(pool), clock.New())
+
+	switch args[0] {
+	case "create":
+		flags := flag.NewFlagSet("apikey create", flag.ContinueOnError)
+		flags.SetOutput(io.Discard)
+
+		name := flags.String("name", "", "human-readable key name")
+		scopes := flags.String("scopes", string(models.ScopeRead), "comma-separated scopes")
+
+		if err := flags.Parse(args[1:]); err != nil {
+			return errors.New(apiKeyUsage)
+		}
+
+		request := models.CreateAPIKeyRequest{Name: *name}
+		for _, scope := range strings.Split(*scopes, ",") {
+			request.Scopes = append(request.Scopes, models.Scope(strings.TrimSpace(scope)))
+		}
+
+		key, plaintext, err := keys.Create(ctx, request)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Created API key %s (%s)\n", key.ID, key.Name)
+		fmt.Printf("Key: %s\n", plaintext)
+		fmt.Println("Store it now, it can't be shown again.")
+
+		return nil
+	case "list":
+		list, err := keys.List(ctx)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tSCOPES\tLAST USED\tREVOKED")
+
+		for _, key := range list {
+			scopes := make([]string, len(key.Scopes))
+			for i, scope := range key.Scopes {
+				scopes[i] = string(scope)
+			}
+
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+				key.ID, key.Name, strings.Join(scopes, ","), formatTime(key.LastUsedAt), formatTime(key.RevokedAt))
+		}
+
+		return w.Flush()
+	case "revoke":
+		if len(args) != 2 {
+			return errors.New(apiKeyUsage)
+		}
+
+		if err := keys.Revoke(ctx, args[1]); err != nil {
+			return err
+		}
+
+		fmt.Printf("Revoked API key %s\n", args[1])
+
+		return nil
+	default:
+		return errors.New(apiKeyUsage)
+	}
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"task-tracker/internal/clock"
+	"task-tracker/internal/config"
+	"task-tracker/internal/models"
+	"task-tracker/internal/repository"
+	"task-tracker/internal/service"
+	"task-tracker/internal/tenant"
+	"task-tracker/internal/trackers"
+)
+
+const importUsage = "usage: main [flags] import -source trello|jira|github [-mapping FILE] [-workspace ID] [-state FILE] [-dry-run] EXPORT"
+
+const importProgressEvery = 100
+
+// Imported items are recorded in a state file and skipped when the import runs again,
+// so an interrupted import resumes where it stopped and failed items are retried.
+func runImport(ctx context.Context, cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+
+	sourceName := flags.String("source", "", "tracker the export comes from: trello, jira or github")
+	mappingPath := flags.String("mapping", "", "YAML file mapping statuses, labels and users")
+	workspace := flags.String("workspace", tenant.DefaultWorkspace, "workspace to import into")
+	statePath := flags.String("state", "", "file recording imported items, EXPORT.WORKSPACE.imported by default")
+	dryRun := flags.Bool("dry-run", false, "validate the items without importing them")
+
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		return errors.New(importUsage)
+	}
+
+	source, err := trackers.ParseSource(*sourceName)
+	if err != nil {
+		return err
+	}
+
+	mapping := trackers.DefaultMapping(source)
+
+	if *mappingPath != "" {
+		if mapping, err = trackers.LoadMapping(*mappingPath, source); err != nil {
+			return err
+		}
+	}
+
+	path := flags.Arg(0)
+
+	items, err := readExport(path, source)
+	if err != nil {
+		return err
+	}
+
+	pool, err := repository.CreateDBPool(ctx, cfg.DBConn, nil)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	clk := clock.New()
+	tasks := service.NewDefaultTaskService(repository.NewPostgresTaskRepository(pool, clk), clk, service.AllowAll{})
+	workspaces := service.NewWorkspaceService(repository.NewPostgresWorkspaceRepository(pool), clk, service.AllowAll{})
+
+	// Entering checks the workspace exists, so its ID is a valid file name part.
+	if ctx, err = workspaces.Enter(ctx, *workspace); err != nil {
+		return err
+	}
+
+	if *statePath == "" {
+		*statePath = defaultImportState(path, tenant.Workspace(ctx))
+	}
+
+	run := trackerImport{
+		source:    source,
+		mapping:   mapping,
+		export:    path,
+		items:     items,
+		statePath: *statePath,
+		dryRun:    *dryRun,
+		stdout:    os.Stdout,
+		stderr:    os.Stderr,
+	}
+
+	return run.run(ctx, tasks)
+}
+
+// Imports into other workspaces create other tasks, so each has its own state file.
+func defaultImportState(export, workspace string) string {
+	return export + "." + workspace + ".imported"
+}
+
+type trackerImport struct {
+	source    trackers.Source
+	mapping   trackers.Mapping
+	export    string
+	items     []trackers.Item
+	statePath string
+	dryRun    bool
+	stdout    io.Writer
+	stderr    io.Writer
+}
+
+func (imp *trackerImport) run(ctx context.Context, tasks service.TaskService) error {
+	workspace := tenant.Workspace(ctx)
+
+	imported, err := readImportState(imp.statePath)
+	if err != nil {
+		return err
+	}
+
+	rows := make([]models.ImportTaskRequest, 0, len(imp.items))
+	keys := make(map[string]string, len(imp.items))
+
+	for _, item := range imp.items {
+		row := imp.mapping.Row(imp.source, workspace, item)
+
+		if !imported[row.ID] {
+			rows = append(rows, row)
+			keys[row.ID] = item.Key
+		}
+	}
+
+	var state *os.File
+
+	if !imp.dryRun {
+		if state, err = os.OpenFile(imp.statePath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644); err != nil {
+			return err
+		}
+		defer state.Close()
+	}
+
+	fmt.Fprintf(imp.stdout, "Importing %d of %d items from %s into workspace %s, %d were imported before\n",
+		len(rows), len(imp.items), imp.export, workspace, len(imp.items)-len(rows))
+
+	var (
+		done     int
+		stateErr error
+	)
+
+	progress := func(row models.ImportTaskRequest, err error) {
+		done++
+
+		switch {
+		case err != nil:
+			fmt.Fprintf(imp.stderr, "%s: %v\n", keys[row.ID], err)
+		case state != nil && stateErr == nil:
+			_, stateErr = fmt.Fprintln(state, row.ID)
+		}
+
+		if done%importProgressEvery == 0 || done == len(rows) {
+			fmt.Fprintf(imp.stdout, "%d/%d\n", done, len(rows))
+		}
+	}
+
+	report, err := service.NewImporter(tasks).ImportWithProgress(ctx, &importRows{rows: rows}, imp.dryRun, progress)
+	if err != nil {
+		return err
+	}
+
+	if stateErr != nil {
+		return fmt.Errorf("recording imported items in %s: %w", imp.statePath, stateErr)
+	}
+
+	verb := "Created"
+	if imp.dryRun {
+		verb = "Would have created"
+	}
+
+	fmt.Fprintf(imp.stdout, "%s %d tasks and updated %d, %d items failed\n", verb, report.Created, report.Updated, report.Failed)
+
+	if report.Failed > 0 {
+		return fmt.Errorf("%d items failed, run the import again to retry them", report.Failed)
+	}
+
+	return nil
+}
+
+func readExport(path string, source trackers.Source) ([]trackers.Item, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return trackers.Read(file, source)
+}
+
+func readImportState(path string) (map[string]bool, error) {
+	imported := make(map[string]bool)
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return imported, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if id := strings.TrimSpace(scanner.Text()); id != "" {
+			imported[id] = true
+		}
+	}
+
+	return imported, scanner.Err()
+}
+
+type importRows struct {
+	rows []models.ImportTaskRequest
+}
+
+func (r *importRows) Next() (models.ImportTaskRequest, error) {
+	if len(r.rows) == 0 {
+		return models.ImportTaskRequest{}, io.EOF
+	}
+
+	row := r.rows[0]
+	r.rows = r.rows[1:]
+
+	return row, nil
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"task-tracker/internal/clock"
+	"task-tracker/internal/repository"
+	"task-tracker/internal/service"
+	"task-tracker/internal/tenant"
+	"task-tracker/internal/trackers"
+)
+
+func TestReadImportState(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := map[string]struct {
+		content  *string
+		expected map[string]bool
+	}{
+		"no state file": {
+			expected: map[string]bool{},
+		},
+		"ids": {
+			content:  ptr("a\n b \n\nc"),
+			expected: map[string]bool{"a": true, "b": true, "c": true},
+		},
+		"empty": {
+			content:  ptr(""),
+			expected: map[string]bool{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+
+			if test.content != nil {
+				if err := os.WriteFile(path, []byte(*test.content), 0o644); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			imported, err := readImportState(path)
+			if err != nil || !reflect.DeepEqual(imported, test.expected) {
+				t.Fatalf("test-case: (%q); returned %v, %v; expected %v", name, imported, err, test.expected)
+			}
+		})
+	}
+}
+
+func TestTrackerImport_Resume(t *testing.T) {
+	dir := t.TempDir()
+	export := filepath.Join(dir, "issues.json")
+
+	clk := clock.New()
+	tasks := service.NewDefaultTaskService(repository.NewMemoryTaskRepository(clk), clk, service.AllowAll{})
+
+	items := []trackers.Item{
+		{Key: "acme/web#1", Title: "First", Status: "open"},
+		{Key: "acme/web#2", Title: "Second", Status: "closed"},
+		{Key: "acme/web#3", Title: "", Status: "open"},
+	}
+
+	run := func(ctx context.Context, items []trackers.Item, dryRun bool) (string, error) {
+		var stdout strings.Builder
+
+		imp := trackerImport{
+			source:    trackers.GitHub,
+			mapping:   trackers.DefaultMapping(trackers.GitHub),
+			export:    export,
+			items:     items,
+			statePath: defaultImportState(export, tenant.Workspace(ctx)),
+			dryRun:    dryRun,
+			stdout:    &stdout,
+			stderr:    io.Discard,
+		}
+
+		err := imp.run(ctx, tasks)
+
+		return stdout.String(), err
+	}
+
+	ctx := tenant.WithWorkspace(context.Background(), tenant.DefaultWorkspace)
+	other := tenant.WithWorkspace(context.Background(), "other")
+
+	steps := []struct {
+		name     string
+		ctx      context.Context
+		items    []trackers.Item
+		dryRun   bool
+		failed   bool
+		expected string
+	}{
+		{"dry run", ctx, items, true, true, "Importing 3 of 3 items"},
+		{"first run", ctx, items, false, true, "Importing 3 of 3 items"},
+		{"fixed item retried", ctx, append(items[:2:2], trackers.Item{Key: "acme/web#3", Title: "Third", Status: "open"}), false, false, "Importing 1 of 3 items"},
+		{"nothing left", ctx, items, false, false, "Importing 0 of 3 items"},
+		{"other workspace", other, items[:2], false, false, "Importing 2 of 2 items"},
+	}
+
+	for _, step := range steps {
+		stdout, err := run(step.ctx, step.items, step.dryRun)
+		if (err != nil) != step.failed || !strings.HasPrefix(stdout, step.expected) {
+			t.Fatalf("test-case: (%q); returned %q, %v; expected %q", step.name, stdout, err, step.expected)
+		}
+	}
+
+	for workspace, expected := range map[string]int{tenant.DefaultWorkspace: 3, "other": 2} {
+		imported, err := readImportState(defaultImportState(export, workspace))
+		if err != nil || len(imported) != expected {
+			t.Fatalf("test-case: (%q); returned %v, %v; expected %d IDs", workspace, imported, err, expected)
+		}
+
+		all, err := tasks.GetAll(tenant.WithWorkspace(context.Background(), workspace))
+		if err != nil || len(all) != expected {
+			t.Fatalf("test-case: (%q); returned %d tasks, %v; expected %d", workspace, len(all), err, expected)
+		}
+	}
+}
+
+func ptr(s string) *string {
+	return &s
+}
@@ -2,25 +2,69 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"task-tracker/internal/config"
 	"task-tracker/internal/server"
 )
 
 func main() {
-	config := config.LoadConfig()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 
-	server := server.NewHTTPServer(*config)
+	err := run(ctx, os.Args[1:])
 
-	ctx := context.Background()
+	stop()
 
-	err := server.ConfigureServer(ctx)
 	if err != nil {
-		panic(err)
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
+}
 
-	err = server.Start(ctx)
+func run(ctx context.Context, args []string) error {
+	config, args, err := config.Load(args)
 	if err != nil {
-		panic(err)
+		return err
 	}
+
+	if len(args) > 0 {
+		return runCommand(ctx, config, args[0], args[1:])
+	}
+
+	server := server.NewHTTPServer(*config)
+
+	if err := server.ConfigureServer(ctx); err != nil {
+		return err
+	}
+
+	return server.Start(ctx)
+}
+
+func runCommand(ctx context.Context, cfg *config.Config, name string, args []string) error {
+	switch name {
+	case "migrate":
+		return runMigrate(ctx, cfg, args)
+	case "apikey":
+		return runAPIKey(ctx, cfg, args)
+	case "import":
+		return runImport(ctx, cfg, args)
+	case "config":
+		return runConfig(cfg, args)
+	default:
+		return fmt.Errorf("unknown command %q", name)
+	}
+}
+
+func runConfig(cfg *config.Config, args []string) error {
+	if len(args) != 1 || args[0] != "print" {
+		return errors.New("usage: main [flags] config print")
+	}
+
+	fmt.Print(cfg.String())
+
+	return nil
 }
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"task-tracker/client"
+)
+
+func (a *app) runList(ctx context.Context, args []string) error {
+	flags := newFlagSet("list")
+
+	assignees := flags.String("assignee", "", "comma-separated assignees")
+	labels := flags.String("label", "", "comma-separated labels, any of which a task has")
+	statuses := flags.String("status", "", "comma-separated statuses")
+	output := flags.String("o", "table", "output format, table or json")
+
+	if err := flags.Parse(args); err != nil || flags.NArg() > 0 || (*output != "table" && *output != "json") {
+		return usageError("list [-assignee A,B] [-label L] [-status S] [-o table|json]")
+	}
+
+	var options []client.ListOption
+
+	if values := splitList(*assignees); len(values) > 0 {
+		options = append(options, client.WithAssignees(values...))
+	}
+
+	if values := splitList(*labels); len(values) > 0 {
+		options = append(options, client.WithLabels(values...))
+	}
+
+	if values := splitList(*statuses); len(values) > 0 {
+		options = append(options, client.WithStatuses(values...))
+	}
+
+	tasks, err := a.client.GetAll(ctx, options...)
+	if err != nil {
+		return err
+	}
+
+	if *output == "json" {
+		return a.printJSON(tasks)
+	}
+
+	w := tabwriter.NewWriter(a.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSTATUS\tDUE\tASSIGNEE\tLABELS\tTITLE")
+
+	for _, task := range tasks {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", task.ID, task.Status, formatDue(task.DueAt),
+			orDash(task.Assignee), orDash(strings.Join(task.Labels, ",")), task.Title)
+	}
+
+	return w.Flush()
+}
+
+func (a *app) runAdd(ctx context.Context, args []string) error {
+	flags := newFlagSet("add")
+
+	description := flags.String("d", "", "description, the title by default")
+	status := flags.String("status", client.StatusTodo, "status")
+	assignee := flags.String("assignee", "", "assignee")
+	due := flags.String("due", "", "due date, as 2006-01-02 or an RFC 3339 time")
+	labels := flags.String("labels", "", "comma-separated labels")
+	output := flags.String("o", "id", "output, the task's id or json")
+
+	if err := flags.Parse(args); err != nil || flags.NArg() == 0 || (*output != "id" && *output != "json") {
+		return usageError("add [-d DESCRIPTION] [-status S] [-assignee A] [-due TIME] [-labels L,M] [-o id|json] TITLE...")
+	}
+
+	task := client.Task{
+		Title:       strings.Join(flags.Args(), " "),
+		Description: *description,
+		Status:      *status,
+		Assignee:    *assignee,
+		Labels:      splitList(*labels),
+	}
+
+	if task.Description == "" {
+		task.Description = task.Title
+	}
+
+	if *due != "" {
+		dueAt, err := parseDue(*due)
+		if err != nil {
+			return err
+		}
+
+		task.DueAt = &dueAt
+	}
+
+	if err := a.client.Add(ctx, &task); err != nil {
+		return err
+	}
+
+	if *output == "json" {
+		return a.printJSON(task)
+	}
+
+	_, err := fmt.Fprintln(a.stdout, task.ID)
+
+	return err
+}
+
+func (a *app) runShow(ctx context.Context, args []string) error {
+	flags := newFlagSet("show")
+
+	output := flags.String("o", "yaml", "output format, yaml or json")
+
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 || (*output != "yaml" && *output != "json") {
+		return usageError("show [-o yaml|json] ID")
+	}
+
+	task, err := a.client.Get(ctx, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	if *output == "json" {
+		return a.printJSON(task)
+	}
+
+	content, err := marshalTaskView(task)
+	if err != nil {
+		return err
+	}
+
+	_, err = a.stdout.Write(content)
+
+	return err
+}
+
+func (a *app) runDone(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return usageError("done ID...")
+	}
+
+	for _, id := range args {
+		task, err := a.client.Get(ctx, id)
+		if err != nil {
+			return fmt.Errorf("%s: %w", id, err)
+		}
+
+		task.Status = client.StatusDone
+
+		if err := a.client.Update(ctx, &task); err != nil {
+			return fmt.Errorf("%s: %w", id, err)
+		}
+	}
+
+	return nil
+}
+
+func (a *app) runRemove(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return usageError("rm ID...")
+	}
+
+	for _, id := range args {
+		if err := a.client.Delete(ctx, id); err != nil {
+			return fmt.Errorf("%s: %w", id, err)
+		}
+	}
+
+	return nil
+}
+
+func (a *app) printJSON(v any) error {
+	encoder := json.NewEncoder(a.stdout)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(v)
+}
+
+func splitList(value string) []string {
+	var values []string
+
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+
+	return values
+}
+
+// parseDue reads a date as midnight UTC, or an RFC 3339 time.
+func parseDue(value string) (time.Time, error) {
+	for _, layout := range []string{time.DateOnly, time.RFC3339} {
+		if dueAt, err := time.Parse(layout, value); err == nil {
+			return dueAt, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("due date %q is neither 2006-01-02 nor an RFC 3339 time", value)
+}
+
+func formatDue(dueAt *time.Time) string {
+	if dueAt == nil {
+		return "-"
+	}
+
+	return dueAt.UTC().Format("2006-01-02 15:04")
+}
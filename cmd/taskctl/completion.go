@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+func (a *app) runCompletion(args []string) error {
+	if len(args) != 1 {
+		return usageError("completion bash|zsh|fish")
+	}
+
+	names := make([]string, len(commands))
+	for i, command := range commands {
+		names[i] = command.name
+	}
+
+	var script string
+
+	switch args[0] {
+	case "bash":
+		script = bashCompletion(names)
+	case "zsh":
+		script = "#compdef taskctl\n# Load with: source <(taskctl completion zsh)\nautoload -U bashcompinit && bashcompinit\n" +
+			bashCompletion(names)
+	case "fish":
+		script = fishCompletion(names)
+	default:
+		return usageError("completion bash|zsh|fish")
+	}
+
+	_, err := fmt.Fprint(a.stdout, script)
+
+	return err
+}
+
+func bashCompletion(names []string) string {
+	var b strings.Builder
+
+	b.WriteString("# Load with: source <(taskctl completion bash)\n_taskctl() {\n")
+	b.WriteString("  local cur=${COMP_WORDS[COMP_CWORD]} i cmd\n")
+	b.WriteString("  for ((i = 1; i < COMP_CWORD; i++)); do\n")
+	b.WriteString("    case ${COMP_WORDS[i]} in -config|-profile|-server|-workspace) ((i++)) ;; -*) ;; *) cmd=${COMP_WORDS[i]}; break ;; esac\n")
+	b.WriteString("  done\n  case $cmd in\n")
+	fmt.Fprintf(&b, "    '') COMPREPLY=($(compgen -W %q -- \"$cur\")) ;;\n", "-config -profile -server -workspace "+strings.Join(names, " "))
+
+	for _, command := range commands {
+		fmt.Fprintf(&b, "    %s) COMPREPLY=($(compgen -W %q -- \"$cur\")) ;;\n", command.name, strings.Join(completions(command), " "))
+	}
+
+	b.WriteString("  esac\n}\ncomplete -F _taskctl taskctl\n")
+
+	return b.String()
+}
+
+func fishCompletion(names []string) string {
+	var b strings.Builder
+
+	b.WriteString("# Load with: taskctl completion fish | source\n")
+	fmt.Fprintf(&b, "complete -c taskctl -f -n __fish_use_subcommand -a %q\n", strings.Join(names, " "))
+
+	for _, flag := range []string{"config", "profile", "server", "workspace"} {
+		fmt.Fprintf(&b, "complete -c taskctl -n __fish_use_subcommand -o %s -r\n", flag)
+	}
+
+	for _, command := range commands {
+		fmt.Fprintf(&b, "complete -c taskctl -f -n '__fish_seen_subcommand_from %s' -a %q\n", command.name, strings.Join(completions(command), " "))
+	}
+
+	return b.String()
+}
+
+func completions(command command) []string {
+	switch command.name {
+	case "config":
+		return []string{"set", "use", "list", "rm", "-server", "-api-key", "-workspace"}
+	case "completion":
+		return []string{"bash", "zsh", "fish"}
+	default:
+		return command.flags
+	}
+}
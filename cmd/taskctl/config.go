@@ -0,0 +1,239 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
+)
+
+const defaultProfile = "default"
+
+type Config struct {
+	Current  string             `yaml:"current,omitempty"`
+	Profiles map[string]Profile `yaml:"profiles,omitempty"`
+}
+
+type Profile struct {
+	Server    string `yaml:"server"`
+	APIKey    string `yaml:"api_key,omitempty"`
+	Workspace string `yaml:"workspace,omitempty"`
+}
+
+// configPath is $TASKCTL_CONFIG or config.yaml in the user's taskctl config directory.
+func configPath() (string, error) {
+	if path := os.Getenv("TASKCTL_CONFIG"); path != "" {
+		return path, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "taskctl", "config.yaml"), nil
+}
+
+func loadConfig(path string) (Config, error) {
+	var config Config
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+
+	if err != nil {
+		return config, err
+	}
+
+	if err := yaml.Unmarshal(content, &config); err != nil {
+		return config, fmt.Errorf("config %s: %w", path, err)
+	}
+
+	return config, nil
+}
+
+// save writes the config readable by the user only, since it holds API keys.
+func (c Config) save(path string) error {
+	content, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, content, 0o600)
+}
+
+// Only a missing profile asked for by name is an error.
+func (c Config) profile(name string) (Profile, error) {
+	explicit := name != ""
+
+	if name == "" {
+		name = c.Current
+	}
+
+	if name == "" {
+		name = defaultProfile
+	}
+
+	profile, ok := c.Profiles[name]
+	if !ok && explicit {
+		return Profile{}, fmt.Errorf("no profile %q, see taskctl config list", name)
+	}
+
+	return profile, nil
+}
+
+const (
+	configArgs  = "set NAME -server URL [-api-key KEY] [-workspace ID] | use NAME | list | rm NAME"
+	configUsage = "config " + configArgs
+)
+
+func (a *app) runConfig(args []string) error {
+	if len(args) == 0 {
+		return usageError(configUsage)
+	}
+
+	config, err := loadConfig(a.configPath)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case args[0] == "set" && len(args) > 1:
+		flags := newFlagSet("config set")
+
+		server := flags.String("server", "", "base URL of the server")
+		apiKey := flags.String("api-key", "", "API key to call the server with, $TASKCTL_API_KEY or prompted for by default")
+		workspace := flags.String("workspace", "", "workspace to work in instead of the default one")
+
+		if err := flags.Parse(args[2:]); err != nil || flags.NArg() > 0 || *server == "" {
+			return usageError(configUsage)
+		}
+
+		if !flagSet(flags, "api-key") {
+			if *apiKey, err = a.readAPIKey(); err != nil {
+				return err
+			}
+		}
+
+		if config.Profiles == nil {
+			config.Profiles = make(map[string]Profile)
+		}
+
+		config.Profiles[args[1]] = Profile{Server: *server, APIKey: *apiKey, Workspace: *workspace}
+
+		if config.Current == "" {
+			config.Current = args[1]
+		}
+
+		return config.save(a.configPath)
+	case args[0] == "use" && len(args) == 2:
+		if _, err := config.profile(args[1]); err != nil {
+			return err
+		}
+
+		config.Current = args[1]
+
+		return config.save(a.configPath)
+	case args[0] == "rm" && len(args) == 2:
+		if _, err := config.profile(args[1]); err != nil {
+			return err
+		}
+
+		delete(config.Profiles, args[1])
+
+		if config.Current == args[1] {
+			config.Current = ""
+		}
+
+		return config.save(a.configPath)
+	case args[0] == "list" && len(args) == 1:
+		names := make([]string, 0, len(config.Profiles))
+		for name := range config.Profiles {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		w := tabwriter.NewWriter(a.stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "CURRENT\tNAME\tSERVER\tWORKSPACE\tAPI KEY")
+
+		for _, name := range names {
+			profile := config.Profiles[name]
+
+			current := ""
+			if name == config.Current || config.Current == "" && name == defaultProfile {
+				current = "*"
+			}
+
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", current, name, profile.Server, orDash(profile.Workspace), maskKey(profile.APIKey))
+		}
+
+		return w.Flush()
+	default:
+		return usageError(configUsage)
+	}
+}
+
+// Reading the key from stdin keeps it out of shell histories. Keys typed on a terminal
+// aren't echoed.
+func (a *app) readAPIKey() (string, error) {
+	if key := os.Getenv("TASKCTL_API_KEY"); key != "" {
+		return key, nil
+	}
+
+	fmt.Fprint(a.stderr, "API key (empty for none): ")
+
+	if file, ok := a.stdin.(*os.File); ok && term.IsTerminal(int(file.Fd())) {
+		key, err := term.ReadPassword(int(file.Fd()))
+		fmt.Fprintln(a.stderr)
+
+		return strings.TrimSpace(string(key)), err
+	}
+
+	line, err := bufio.NewReader(a.stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+
+	return strings.TrimSpace(line), nil
+}
+
+func flagSet(flags *flag.FlagSet, name string) bool {
+	set := false
+
+	flags.Visit(func(f *flag.Flag) {
+		set = set || f.Name == name
+	})
+
+	return set
+}
+
+func maskKey(key string) string {
+	if len(key) <= 8 {
+		return orDash(strings.Repeat("*", len(key)))
+	}
+
+	return key[:8] + "..."
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+
+	return value
+}
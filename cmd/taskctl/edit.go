@@ -0,0 +1,232 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"reflect"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"task-tracker/client"
+)
+
+type taskDocument struct {
+	Title       string     `yaml:"title"`
+	Description string     `yaml:"description"`
+	Status      string     `yaml:"status"`
+	Assignee    string     `yaml:"assignee"`
+	DueAt       *time.Time `yaml:"due_at"`
+	// Reminders are Go durations before the due date, such as 1h30m.
+	Reminders []string `yaml:"reminders"`
+	Labels    []string `yaml:"labels"`
+}
+
+type taskView struct {
+	ID           string `yaml:"id"`
+	Workspace    string `yaml:"workspace"`
+	taskDocument `yaml:",inline"`
+	RRule        string     `yaml:"rrule,omitempty"`
+	TimeZone     string     `yaml:"time_zone,omitempty"`
+	SeriesID     string     `yaml:"series_id,omitempty"`
+	CreatedBy    string     `yaml:"created_by,omitempty"`
+	CreatedAt    time.Time  `yaml:"created_at"`
+	UpdatedAt    time.Time  `yaml:"updated_at"`
+	NextReminder *time.Time `yaml:"next_reminder_at,omitempty"`
+}
+
+func newTaskDocument(task client.Task) taskDocument {
+	document := taskDocument{
+		Title:       task.Title,
+		Description: task.Description,
+		Status:      task.Status,
+		Assignee:    task.Assignee,
+		DueAt:       utc(task.DueAt),
+		Reminders:   []string{},
+		Labels:      task.Labels,
+	}
+
+	for _, before := range task.Reminders {
+		document.Reminders = append(document.Reminders, time.Duration(before).String())
+	}
+
+	if document.Labels == nil {
+		document.Labels = []string{}
+	}
+
+	return document
+}
+
+func marshalTaskView(task client.Task) ([]byte, error) {
+	return yaml.Marshal(taskView{
+		ID:           task.ID,
+		Workspace:    task.WorkspaceID,
+		taskDocument: newTaskDocument(task),
+		RRule:        task.RRule,
+		TimeZone:     task.TimeZone,
+		SeriesID:     task.SeriesID,
+		CreatedBy:    task.CreatedBy,
+		CreatedAt:    task.CreatedAt.UTC(),
+		UpdatedAt:    task.UpdatedAt.UTC(),
+		NextReminder: utc(task.NextReminderAt),
+	})
+}
+
+const editHeader = `# Editing task %s. Save and quit to apply the changes; quit without saving or
+# empty the file to cancel. due_at is an RFC 3339 time, reminders are durations
+# before it such as 1h30m. due_at and assignee can be changed but not removed.
+`
+
+// A changed due date is applied by rescheduling the task, like the API does for
+// occurrences of a series.
+func (a *app) runEdit(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return usageError("edit ID")
+	}
+
+	id := args[0]
+
+	task, err := a.client.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	original := newTaskDocument(task)
+
+	content, err := yaml.Marshal(original)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp("", "taskctl-*.yaml")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := fmt.Fprintf(file, editHeader+"%s", id, content); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	if err := a.edit(ctx, file.Name()); err != nil {
+		return err
+	}
+
+	edited, err := os.ReadFile(file.Name())
+	if err != nil {
+		return err
+	}
+
+	var document taskDocument
+
+	if len(bytes.TrimSpace(stripComments(edited))) > 0 {
+		if err := yaml.Unmarshal(edited, &document); err != nil {
+			return fmt.Errorf("edited task: %w", err)
+		}
+	}
+
+	if reflect.DeepEqual(document, taskDocument{}) || reflect.DeepEqual(document, original) {
+		_, err := fmt.Fprintln(a.stderr, "No changes")
+		return err
+	}
+
+	updated := client.Task{
+		ID:          id,
+		Title:       document.Title,
+		Description: document.Description,
+		Status:      document.Status,
+		Assignee:    document.Assignee,
+		Labels:      document.Labels,
+		Reminders:   []client.Duration{},
+	}
+
+	for _, value := range document.Reminders {
+		before, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("edited task: reminder %q is not a duration", value)
+		}
+
+		updated.Reminders = append(updated.Reminders, client.Duration(before))
+	}
+
+	if updated.Labels == nil {
+		updated.Labels = []string{}
+	}
+
+	// The API keeps the current value of fields left empty.
+	if original.DueAt != nil && document.DueAt == nil {
+		return errors.New("edited task: due_at can't be removed")
+	}
+
+	if original.Assignee != "" && document.Assignee == "" {
+		return errors.New("edited task: assignee can't be removed")
+	}
+
+	if err := a.client.Update(ctx, &updated); err != nil {
+		return err
+	}
+
+	if document.DueAt != nil && (original.DueAt == nil || !document.DueAt.Equal(*original.DueAt)) {
+		if _, err := a.client.Reschedule(ctx, id, *document.DueAt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// stripComments drops the comment lines, to tell an emptied file from an edited one.
+func stripComments(content []byte) []byte {
+	var kept [][]byte
+
+	for _, line := range bytes.Split(content, []byte("\n")) {
+		if !bytes.HasPrefix(bytes.TrimSpace(line), []byte("#")) {
+			kept = append(kept, line)
+		}
+	}
+
+	return bytes.Join(kept, []byte("\n"))
+}
+
+// $VISUAL and $EDITOR may include arguments.
+func openEditor(ctx context.Context, path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+
+	if editor == "" {
+		editor = "vi"
+	}
+
+	fields := strings.Fields(editor)
+
+	cmd := exec.CommandContext(ctx, fields[0], append(fields[1:], path)...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor %s: %w", editor, err)
+	}
+
+	return nil
+}
+
+func utc(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	u := t.UTC()
+
+	return &u
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"task-tracker/client"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	code := run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+
+	stop()
+	os.Exit(code)
+}
+
+// Exit codes. API errors exit with the code of their status.
+const (
+	exitOK          = 0
+	exitFailure     = 1
+	exitUsage       = 2
+	exitInvalid     = 3 // 400 and other 4xx
+	exitDenied      = 4 // 401 and 403
+	exitNotFound    = 5 // 404
+	exitConflict    = 6 // 409 and 412
+	exitRateLimited = 7 // 429
+	exitServerError = 8 // 5xx
+)
+
+type command struct {
+	name    string
+	args    string
+	summary string
+	// flags are completed by the shell.
+	flags []string
+	run   func(a *app, ctx context.Context, args []string) error
+	// local commands don't call the API.
+	local bool
+}
+
+// commands is set in init, since completion refers back to it.
+var commands []command
+
+func init() {
+	commands = []command{
+		{name: "list", args: "[-assignee A,B] [-label L] [-status S] [-o table|json]", summary: "list tasks",
+			flags: []string{"-assignee", "-label", "-status", "-o"}, run: (*app).runList},
+		{name: "add", args: "[-d DESCRIPTION] [-status S] [-assignee A] [-due TIME] [-labels L,M] TITLE...",
+			summary: "add a task, described by its title unless -d is given",
+			flags:   []string{"-d", "-status", "-assignee", "-due", "-labels", "-o"}, run: (*app).runAdd},
+		{name: "show", args: "[-o yaml|json] ID", summary: "show a task", flags: []string{"-o"}, run: (*app).runShow},
+		{name: "edit", args: "ID", summary: "edit a task in $VISUAL or $EDITOR", run: (*app).runEdit},
+		{name: "done", args: "ID...", summary: "mark tasks done", run: (*app).runDone},
+		{name: "rm", args: "ID...", summary: "delete tasks", run: (*app).runRemove},
+		{name: "config", args: configArgs, summary: "manage connection profiles",
+			run: func(a *app, _ context.Context, args []string) error { return a.runConfig(args) }, local: true},
+		{name: "completion", args: "bash|zsh|fish", summary: "print a shell completion script",
+			run: func(a *app, _ context.Context, args []string) error { return a.runCompletion(args) }, local: true},
+	}
+}
+
+type app struct {
+	stdin      io.Reader
+	stdout     io.Writer
+	stderr     io.Writer
+	configPath string
+	client     *client.Client
+	// edit opens a file in the user's editor.
+	edit func(ctx context.Context, path string) error
+}
+
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	a := &app{stdin: stdin, stdout: stdout, stderr: stderr, edit: openEditor}
+
+	err := a.run(ctx, args)
+
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, errUsage):
+		fmt.Fprintln(stderr, err)
+		fmt.Fprint(stderr, usage())
+
+		return exitUsage
+	default:
+		fmt.Fprintln(stderr, "taskctl:", err)
+		return exitCode(err)
+	}
+}
+
+func (a *app) run(ctx context.Context, args []string) error {
+	flags := newFlagSet("taskctl")
+
+	configFile := flags.String("config", "", "config file, $TASKCTL_CONFIG or taskctl/config.yaml in the user config directory by default")
+	profileName := flags.String("profile", os.Getenv("TASKCTL_PROFILE"), "profile to connect with, the current one by default")
+	server := flags.String("server", "", "base URL of the server, overriding the profile's")
+	workspace := flags.String("workspace", "", "workspace to work in, overriding the profile's")
+
+	if err := flags.Parse(args); err != nil || flags.NArg() == 0 {
+		return usageError("missing command")
+	}
+
+	a.configPath = *configFile
+
+	if a.configPath == "" {
+		var err error
+
+		if a.configPath, err = configPath(); err != nil {
+			return err
+		}
+	}
+
+	name := flags.Arg(0)
+
+	for _, command := range commands {
+		if command.name != name {
+			continue
+		}
+
+		if !command.local {
+			config, err := loadConfig(a.configPath)
+			if err != nil {
+				return err
+			}
+
+			profile, err := config.profile(*profileName)
+			if err != nil {
+				return err
+			}
+
+			// The key is read from the environment rather than a flag to keep it out
+			// of shell histories.
+			if key := os.Getenv("TASKCTL_API_KEY"); key != "" {
+				profile.APIKey = key
+			}
+
+			if *server != "" {
+				profile.Server = *server
+			}
+
+			if *workspace != "" {
+				profile.Workspace = *workspace
+			}
+
+			if a.client, err = newClient(profile); err != nil {
+				return err
+			}
+		}
+
+		return command.run(a, ctx, flags.Args()[1:])
+	}
+
+	return usageError("unknown command %q", name)
+}
+
+func usage() string {
+	var b strings.Builder
+
+	b.WriteString("usage: taskctl [-config FILE] [-profile NAME] [-server URL] [-workspace ID] COMMAND\n\ncommands:\n")
+
+	for _, command := range commands {
+		fmt.Fprintf(&b, "  %-10s %s\n  %-10s %s\n", command.name, command.summary, "", command.args)
+	}
+
+	b.WriteString("\nThe API key comes from $TASKCTL_API_KEY or the profile.\n")
+	b.WriteString("Exit codes: 2 usage, 3 invalid request, 4 unauthorized or forbidden, 5 not found,\n")
+	b.WriteString("6 conflict, 7 rate limited, 8 server error, 1 anything else.\n")
+
+	return b.String()
+}
+
+var errUsage = errors.New("usage")
+
+func usageError(format string, args ...any) error {
+	return fmt.Errorf("%w: "+format, append([]any{errUsage}, args...)...)
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+
+	return flags
+}
+
+func newClient(profile Profile) (*client.Client, error) {
+	if profile.Server == "" {
+		return nil, errors.New("no server configured, see taskctl config set")
+	}
+
+	return client.New(profile.Server, client.WithAPIKey(profile.APIKey), client.WithWorkspace(profile.Workspace))
+}
+
+func exitCode(err error) int {
+	var apiError *client.Error
+	if !errors.As(err, &apiError) {
+		return exitFailure
+	}
+
+	switch code := apiError.StatusCode; {
+	case code == http.StatusUnauthorized || code == http.StatusForbidden:
+		return exitDenied
+	case code == http.StatusNotFound:
+		return exitNotFound
+	case code == http.StatusConflict || code == http.StatusPreconditionFailed:
+		return exitConflict
+	case code == http.StatusTooManyRequests:
+		return exitRateLimited
+	case code >= http.StatusInternalServerError:
+		return exitServerError
+	case code >= http.StatusBadRequest:
+		return exitInvalid
+	default:
+		return exitFailure
+	}
+}
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"task-tracker/client"
+	"task-tracker/internal/config"
+	"task-tracker/internal/models"
+	"task-tracker/internal/server"
+)
+
+// startServer serves an in-memory task API until the test ends.
+func startServer(t *testing.T) string {
+	t.Helper()
+
+	srv := server.NewHTTPServer(config.Config{InMemory: true, LogLevel: "error", ShutdownTimeout: time.Second})
+
+	if err := srv.ConfigureServer(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+
+	go func() {
+		served <- srv.Serve(ctx, ln)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		<-served
+	})
+
+	return "http://" + ln.Addr().String()
+}
+
+type harness struct {
+	t          *testing.T
+	configPath string
+	stdin      string
+	edit       func(content string) string
+}
+
+// run runs taskctl and returns its exit code and output.
+func (h *harness) run(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+
+	a := &app{stdin: strings.NewReader(h.stdin), stdout: &stdout, stderr: &stderr, edit: h.editFile}
+
+	err := a.run(context.Background(), append([]string{"-config", h.configPath}, args...))
+
+	code := exitOK
+	if errors.Is(err, errUsage) {
+		code = exitUsage
+	} else if err != nil {
+		code = exitCode(err)
+		stderr.WriteString(err.Error())
+	}
+
+	return code, stdout.String(), stderr.String()
+}
+
+func (h *harness) editFile(_ context.Context, path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, []byte(h.edit(string(content))), 0o600)
+}
+
+func TestTaskctl(t *testing.T) {
+	h := &harness{t: t, configPath: filepath.Join(t.TempDir(), "taskctl", "config.yaml")}
+
+	if code, _, _ := h.run("list"); code != exitFailure {
+		t.Fatalf("returned %v; expected %v without a configured server", code, exitFailure)
+	}
+
+	if code, _, stderr := h.run("config", "set", "local", "-server", startServer(t), "-api-key", "tt_0123456789"); code != exitOK {
+		t.Fatalf("returned %v, %q; expected the profile to be saved", code, stderr)
+	}
+
+	if info, err := os.Stat(h.configPath); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("returned %v, %v; expected a config readable by the user only", info, err)
+	}
+
+	if _, stdout, _ := h.run("config", "list"); !strings.Contains(stdout, "*        local") || !strings.Contains(stdout, "tt_01234...") {
+		t.Fatalf("returned %q; expected the current profile with its key masked", stdout)
+	}
+
+	code, stdout, stderr := h.run("add", "-assignee", "alice", "-labels", "ops,urgent", "-due", "2025-04-14", "Deploy", "the", "release")
+	if code != exitOK {
+		t.Fatalf("returned %v, %q; expected the task to be added", code, stderr)
+	}
+
+	deploy := strings.TrimSpace(stdout)
+
+	if code, _, stderr := h.run("add", "-d", "Read the diff.", "-assignee", "bob", "Review"); code != exitOK {
+		t.Fatalf("returned %v, %q; expected the task to be added", code, stderr)
+	}
+
+	tests := map[string]struct {
+		args     []string
+		code     int
+		expected []string
+		absent   []string
+	}{
+		"list table": {
+			args:     []string{"list"},
+			expected: []string{"ID", deploy + "  todo    2025-04-14 00:00  alice", "ops,urgent  Deploy the release", "Review"},
+		},
+		"list filtered": {
+			args:     []string{"list", "-assignee", "bob"},
+			expected: []string{"Review"},
+			absent:   []string{"Deploy"},
+		},
+		"list json": {
+			args:     []string{"list", "-o", "json", "-label", "ops"},
+			expected: []string{`"title": "Deploy the release"`, `"description": "Deploy the release"`},
+			absent:   []string{"Review"},
+		},
+		"show yaml": {
+			args:     []string{"show", deploy},
+			expected: []string{"id: " + deploy, "title: Deploy the release", "due_at: 2025-04-14T00:00:00Z", "- ops"},
+		},
+		"show unknown":   {args: []string{"show", "0f8e3a6c-8d2b-4b55-9c7e-2b1f0a6d4e11"}, code: exitNotFound},
+		"invalid labels": {args: []string{"add", "-labels", strings.Repeat("l,", 21) + "l", "Too many"}, code: exitInvalid},
+		"bad due date":   {args: []string{"add", "-due", "tomorrow", "Later"}, code: exitFailure},
+		"no title":       {args: []string{"add"}, code: exitUsage},
+		"unknown output": {args: []string{"list", "-o", "csv"}, code: exitUsage},
+		"unknown":        {args: []string{"frobnicate"}, code: exitUsage},
+		"completion":     {args: []string{"completion", "bash"}, expected: []string{"complete -F _taskctl taskctl", "list) COMPREPLY"}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			code, stdout, stderr := h.run(test.args...)
+
+			if code != test.code {
+				t.Fatalf("test-case: (%q); returned %v, %q; expected %v", name, code, stderr, test.code)
+			}
+
+			for _, expected := range test.expected {
+				if !strings.Contains(stdout, expected) {
+					t.Fatalf("test-case: (%q); returned %q; expected it to contain %q", name, stdout, expected)
+				}
+			}
+
+			for _, absent := range test.absent {
+				if strings.Contains(stdout, absent) {
+					t.Fatalf("test-case: (%q); returned %q; expected it not to contain %q", name, stdout, absent)
+				}
+			}
+		})
+	}
+
+	h.edit = func(content string) string {
+		if !strings.HasPrefix(content, "# Editing task "+deploy) {
+			t.Fatalf("returned %q; expected the edit header", content)
+		}
+
+		content = strings.Replace(content, "status: todo", "status: in_progress", 1)
+		content = strings.Replace(content, "2025-04-14T00:00:00Z", "2025-04-15T09:00:00Z", 1)
+
+		return strings.Replace(content, "reminders: []", "reminders:\n    - 1h", 1)
+	}
+
+	if code, _, stderr := h.run("edit", deploy); code != exitOK {
+		t.Fatalf("returned %v, %q; expected the task to be edited", code, stderr)
+	}
+
+	task := h.show(deploy)
+	if task.Status != "in_progress" || task.DueAt == nil || !task.DueAt.Equal(time.Date(2025, time.April, 15, 9, 0, 0, 0, time.UTC)) ||
+		len(task.Reminders) != 1 || task.Labels[1] != "urgent" {
+		t.Fatalf("returned %+v; expected the edits applied", task)
+	}
+
+	h.edit = func(string) string { return "" }
+
+	if code, _, stderr := h.run("edit", deploy); code != exitOK || stderr != "No changes\n" {
+		t.Fatalf("returned %v, %q; expected the edit canceled", code, stderr)
+	}
+
+	h.edit = func(content string) string {
+		return strings.Replace(content, "title: Deploy the release", "title: ''", 1)
+	}
+
+	if code, _, _ := h.run("edit", deploy); code != exitInvalid {
+		t.Fatalf("returned %v; expected %v", code, exitInvalid)
+	}
+
+	h.edit = func(content string) string {
+		return strings.Replace(content, "assignee: alice", "assignee: ''", 1)
+	}
+
+	if code, _, stderr := h.run("edit", deploy); code != exitFailure || !strings.Contains(stderr, "assignee can't be removed") {
+		t.Fatalf("returned %v, %q; expected the removed assignee rejected", code, stderr)
+	}
+
+	if code, _, stderr := h.run("done", deploy); code != exitOK || h.show(deploy).Status != models.StatusDone {
+		t.Fatalf("returned %v, %q; expected the task done", code, stderr)
+	}
+
+	if code, _, stderr := h.run("rm", deploy); code != exitOK {
+		t.Fatalf("returned %v, %q; expected the task deleted", code, stderr)
+	}
+
+	if code, _, _ := h.run("rm", deploy); code != exitNotFound {
+		t.Fatalf("returned %v; expected %v", code, exitNotFound)
+	}
+}
+
+func TestTaskctl_ConfigAPIKey(t *testing.T) {
+	tests := map[string]struct {
+		env      string
+		stdin    string
+		expected string
+	}{
+		"from the environment": {env: "tt_fromenv_secret", stdin: "tt_ignored\n", expected: "tt_fromenv_secret"},
+		"prompted for":         {stdin: "  tt_typed_secret\n", expected: "tt_typed_secret"},
+		"none":                 {expected: ""},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("TASKCTL_API_KEY", test.env)
+
+			h := &harness{t: t, configPath: filepath.Join(t.TempDir(), "config.yaml"), stdin: test.stdin}
+
+			if code, _, stderr := h.run("config", "set", "local", "-server", "http://localhost:8080"); code != exitOK {
+				t.Fatalf("test-case: (%q); returned %v, %q; expected the profile to be saved", name, code, stderr)
+			}
+
+			config, err := loadConfig(h.configPath)
+			if err != nil || config.Profiles["local"].APIKey != test.expected {
+				t.Fatalf("test-case: (%q); returned %+v, %v; expected key %q", name, config, err, test.expected)
+			}
+		})
+	}
+}
+
+func (h *harness) show(id string) models.Task {
+	code, stdout, stderr := h.run("show", "-o", "json", id)
+	if code != exitOK {
+		h.t.Fatalf("returned %v, %q; expected the task", code, stderr)
+	}
+
+	var task models.Task
+	if err := json.Unmarshal([]byte(stdout), &task); err != nil {
+		h.t.Fatalf("unexpected error: %v", err)
+	}
+
+	return task
+}
+
+func TestExitCode(t *testing.T) {
+	tests := map[string]struct {
+		err      error
+		expected int
+	}{
+		"bad request":  {&client.Error{StatusCode: http.StatusBadRequest}, exitInvalid},
+		"unauthorized": {&client.Error{StatusCode: http.StatusUnauthorized}, exitDenied},
+		"forbidden":    {&client.Error{StatusCode: http.StatusForbidden}, exitDenied},
+		"not found":    {&client.Error{StatusCode: http.StatusNotFound}, exitNotFound},
+		"conflict":     {&client.Error{StatusCode: http.StatusConflict}, exitConflict},
+		"precondition": {&client.Error{StatusCode: http.StatusPreconditionFailed}, exitConflict},
+		"rate limited": {&client.Error{StatusCode: http.StatusTooManyRequests}, exitRateLimited},
+		"server error": {&client.Error{StatusCode: http.StatusServiceUnavailable}, exitServerError},
+		"wrapped":      {fmt.Errorf("id: %w", &client.Error{StatusCode: http.StatusNotFound}), exitNotFound},
+		"other":        {errors.New("connection refused"), exitFailure},
+	}
+
+	for name, test := range tests {
+		if code := exitCode(test.err); code != test.expected {
+			t.Fatalf("test-case: (%q); returned %v; expected %v", name, code, test.expected)
+		}
+	}
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"task-tracker/client"
+)
+
+// column holds the tasks with one status, due ones first, soonest first.
+type column struct {
+	status string
+	tasks  []client.Task
+}
+
+// Columns come from the statuses the board is given, followed by any other status a
+// task has.
+type board struct {
+	store    store
+	source   string
+	statuses []string
+	columns  []column
+	// selected is the ID of the task at col and row, which stays selected when a refresh
+	// moves it.
+	col, row int
+	selected string
+	// prompt reads a line of input when set, e.g. the title of a new task.
+	prompt   *prompt
+	message  string
+	loadedAt time.Time
+}
+
+type prompt struct {
+	label  string
+	value  []rune
+	submit func(ctx context.Context, value string) error
+}
+
+func newBoard(s store, source string, statuses []string) *board {
+	b := &board{store: s, source: source, statuses: statuses}
+	b.set(nil)
+
+	return b
+}
+
+func (b *board) refresh(ctx context.Context) error {
+	tasks, err := b.store.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	b.set(tasks)
+	b.loadedAt = time.Now()
+
+	return nil
+}
+
+func (b *board) set(tasks []client.Task) {
+	byStatus := make(map[string][]client.Task)
+	statuses := slices.Clone(b.statuses)
+
+	for _, task := range tasks {
+		if _, ok := byStatus[task.Status]; !ok && !slices.Contains(statuses, task.Status) {
+			statuses = append(statuses, task.Status)
+		}
+
+		byStatus[task.Status] = append(byStatus[task.Status], task)
+	}
+
+	sort.Strings(statuses[len(b.statuses):])
+
+	b.columns = make([]column, len(statuses))
+
+	for i, status := range statuses {
+		column := column{status: status, tasks: byStatus[status]}
+
+		sort.SliceStable(column.tasks, func(i, j int) bool {
+			x, y := column.tasks[i], column.tasks[j]
+
+			switch {
+			case (x.DueAt == nil) != (y.DueAt == nil):
+				return x.DueAt != nil
+			case x.DueAt != nil && !x.DueAt.Equal(*y.DueAt):
+				return x.DueAt.Before(*y.DueAt)
+			case !x.CreatedAt.Equal(y.CreatedAt):
+				return x.CreatedAt.Before(y.CreatedAt)
+			default:
+				return x.ID < y.ID
+			}
+		})
+
+		b.columns[i] = column
+
+		for j, task := range column.tasks {
+			if task.ID == b.selected {
+				b.col, b.row = i, j
+			}
+		}
+	}
+
+	b.selectCard(b.col, b.row)
+}
+
+// The nearest card is selected if there's none at col and row.
+func (b *board) selectCard(col, row int) {
+	b.col = max(0, min(col, len(b.columns)-1))
+	b.row, b.selected = 0, ""
+
+	if len(b.columns) == 0 {
+		return
+	}
+
+	tasks := b.columns[b.col].tasks
+	if len(tasks) == 0 {
+		return
+	}
+
+	b.row = max(0, min(row, len(tasks)-1))
+	b.selected = tasks[b.row].ID
+}
+
+func (b *board) current() *client.Task {
+	if len(b.columns) == 0 || len(b.columns[b.col].tasks) == 0 {
+		return nil
+	}
+
+	task := b.columns[b.col].tasks[b.row]
+
+	return &task
+}
+
+func (b *board) handle(ctx context.Context, k key) bool {
+	if b.prompt != nil {
+		b.handlePrompt(ctx, k)
+		return false
+	}
+
+	b.message = ""
+
+	switch k {
+	case keyCtrlC, "q":
+		return true
+	case keyUp, "k":
+		b.selectCard(b.col, b.row-1)
+	case keyDown, "j":
+		b.selectCard(b.col, b.row+1)
+	case keyLeft, "h":
+		b.selectCard(b.col-1, b.row)
+	case keyRight, "l":
+		b.selectCard(b.col+1, b.row)
+	case keyShiftLeft, "H":
+		b.move(ctx, -1)
+	case keyShiftRight, "L":
+		b.move(ctx, 1)
+	case "n":
+		b.newTask()
+	case keyEnter, "e":
+		b.edit("Title", func(task *client.Task) string { return task.Title },
+			func(task *client.Task, value string) { task.Title = value })
+	case "a":
+		b.edit("Assignee", func(task *client.Task) string { return task.Assignee },
+			func(task *client.Task, value string) { task.Assignee = value })
+	case "#":
+		b.edit("Labels", func(task *client.Task) string { return strings.Join(task.Labels, ", ") },
+			func(task *client.Task, value string) { task.Labels = splitList(value) })
+	case "r":
+		b.apply(ctx, nil)
+	}
+
+	return false
+}
+
+func (b *board) handlePrompt(ctx context.Context, k key) {
+	p := b.prompt
+
+	switch k {
+	case keyEnter:
+		b.prompt = nil
+		b.apply(ctx, func(ctx context.Context) error { return p.submit(ctx, strings.TrimSpace(string(p.value))) })
+	case keyEscape, keyCtrlC:
+		b.prompt = nil
+	case keyBackspace:
+		if len(p.value) > 0 {
+			p.value = p.value[:len(p.value)-1]
+		}
+	case keyCtrlU:
+		p.value = nil
+	default:
+		if r, size := utf8.DecodeRuneInString(string(k)); size == len(k) && unicode.IsPrint(r) {
+			p.value = append(p.value, r)
+		}
+	}
+}
+
+// The board is reloaded after a change, so it shows the tasks as the store has them.
+func (b *board) apply(ctx context.Context, change func(ctx context.Context) error) {
+	if change != nil {
+		if err := change(ctx); err != nil {
+			b.message = err.Error()
+			return
+		}
+	}
+
+	if err := b.refresh(ctx); err != nil {
+		b.message = err.Error()
+	}
+}
+
+func (b *board) move(ctx context.Context, delta int) {
+	task := b.current()
+	if task == nil || b.col+delta < 0 || b.col+delta >= len(b.columns) {
+		return
+	}
+
+	task.Status = b.columns[b.col+delta].status
+
+	b.apply(ctx, func(ctx context.Context) error { return b.store.Update(ctx, task) })
+}
+
+// New tasks are described by their title, like taskctl add does.
+func (b *board) newTask() {
+	if len(b.columns) == 0 {
+		return
+	}
+
+	status := b.columns[b.col].status
+
+	b.prompt = &prompt{label: "New " + status + " task", submit: func(ctx context.Context, title string) error {
+		task := client.Task{Title: title, Description: title, Status: status}
+
+		if err := b.store.Add(ctx, &task); err != nil {
+			return err
+		}
+
+		b.selected = task.ID
+
+		return nil
+	}}
+}
+
+func (b *board) edit(label string, get func(*client.Task) string, set func(*client.Task, string)) {
+	task := b.current()
+	if task == nil {
+		return
+	}
+
+	b.prompt = &prompt{label: label, value: []rune(get(task)), submit: func(ctx context.Context, value string) error {
+		set(task, value)
+		return b.store.Update(ctx, task)
+	}}
+}
+
+func splitList(value string) []string {
+	values := []string{}
+
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+
+	return values
+}
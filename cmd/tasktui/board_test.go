@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"task-tracker/client"
+	"task-tracker/internal/config"
+	"task-tracker/internal/server"
+)
+
+// stores returns an embedded store and the store of a server running in memory.
+func stores(t *testing.T) map[string]store {
+	t.Helper()
+
+	local, err := newLocalStore(context.Background(), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	srv := server.NewHTTPServer(config.Config{InMemory: true, LogLevel: "error", ShutdownTimeout: time.Second})
+
+	if err := srv.ConfigureServer(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+
+	remote, err := client.New(ts.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return map[string]store{"memory": local, "server": remote}
+}
+
+// press handles the keys, each character of text as a key of its own.
+func press(ctx context.Context, b *board, keys ...key) {
+	for _, k := range keys {
+		if len(k) > 1 && !strings.HasPrefix(string(k), "<") {
+			for _, r := range k {
+				b.handle(ctx, key(string(r)))
+			}
+
+			continue
+		}
+
+		b.handle(ctx, k)
+	}
+}
+
+// layout lists the titles of each column, for comparing boards.
+func layout(b *board) string {
+	var columns []string
+
+	for _, column := range b.columns {
+		var titles []string
+		for _, task := range column.tasks {
+			titles = append(titles, task.Title)
+		}
+
+		columns = append(columns, column.status+": "+strings.Join(titles, ", "))
+	}
+
+	return strings.Join(columns, " | ")
+}
+
+func TestBoard(t *testing.T) {
+	ctx := context.Background()
+
+	for name, s := range stores(t) {
+		b := newBoard(s, name, []string{client.StatusTodo, "in_progress", client.StatusDone})
+
+		steps := []struct {
+			keys     []key
+			expected string
+			current  string
+		}{
+			{
+				keys:     []key{"n", "Deploy", keyEnter, "n", "Review", keyEnter},
+				expected: "todo: Deploy, Review | in_progress:  | done: ",
+				current:  "Review",
+			},
+			{
+				keys:     []key{keyUp, "L", "L"},
+				expected: "todo: Review | in_progress:  | done: Deploy",
+				current:  "Deploy",
+			},
+			{
+				keys:     []key{"H", "e", keyCtrlU, "Deploy v2", keyEnter, "a", "alice", keyEnter, "#", "ops, prod", keyEnter},
+				expected: "todo: Review | in_progress: Deploy v2 | done: ",
+				current:  "Deploy v2",
+			},
+			{
+				keys:     []key{keyLeft, "e", keyBackspace, keyBackspace, keyEscape, "n", "Plan", keyEscape, keyRight, keyRight, "n", "Ship", keyEnter},
+				expected: "todo: Review | in_progress: Deploy v2 | done: Ship",
+				current:  "Ship",
+			},
+		}
+
+		for i, step := range steps {
+			press(ctx, b, step.keys...)
+
+			if got := layout(b); got != step.expected || b.current() == nil || b.current().Title != step.current || b.message != "" {
+				t.Fatalf("test-case: (%q, step %d); returned %q selecting %+v, message %q; expected %q selecting %q",
+					name, i, got, b.current(), b.message, step.expected, step.current)
+			}
+		}
+
+		tasks, _ := s.GetAll(ctx)
+		for _, task := range tasks {
+			if task.Title == "Deploy v2" && (task.Assignee != "alice" || strings.Join(task.Labels, ",") != "ops,prod" || task.Description != "Deploy") {
+				t.Fatalf("test-case: (%q); returned %+v; expected the edited task", name, task)
+			}
+		}
+
+		// A task added elsewhere shows up on refresh, in a column of its own status.
+		if err := s.Add(ctx, &client.Task{Title: "Cancel", Description: "d", Status: client.StatusCanceled}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		press(ctx, b, "r")
+
+		if got, expected := layout(b), "todo: Review | in_progress: Deploy v2 | done: Ship | canceled: Cancel"; got != expected || b.current().Title != "Ship" {
+			t.Fatalf("test-case: (%q); returned %q; expected %q", name, got, expected)
+		}
+
+		press(ctx, b, "e", keyCtrlU, keyEnter)
+
+		if b.message == "" || layout(b) != "todo: Review | in_progress: Deploy v2 | done: Ship | canceled: Cancel" {
+			t.Fatalf("test-case: (%q); returned %q, message %q; expected an error for the empty title", name, layout(b), b.message)
+		}
+
+		if !b.handle(ctx, "q") {
+			t.Fatalf("test-case: (%q); returned false; expected q to quit", name)
+		}
+	}
+}
+
+func TestBoard_Render(t *testing.T) {
+	due := time.Date(2025, time.April, 14, 7, 0, 0, 0, time.Local)
+
+	b := newBoard(nil, "memory", []string{client.StatusTodo, client.StatusDone})
+	b.set([]client.Task{
+		{ID: "1", Title: "Deploy the new release to production", Status: client.StatusTodo, Assignee: "alice", DueAt: &due, Labels: []string{"ops"}},
+		{ID: "2", Title: "Review", Status: client.StatusTodo},
+		{ID: "3", Title: "Plan", Status: client.StatusDone},
+	})
+
+	b.handle(context.Background(), keyDown)
+	b.handle(context.Background(), "n")
+
+	lines := b.render(40, 8)
+
+	expected := []string{
+		" memory · 3 tasks · loaded 00:00:00",
+		" TODO (2)            DONE (1)",
+		" Deploy the new rel… Plan",
+		"   @alice · due Apr…",
+		" Review",
+		"",
+		" New todo task: █",
+		" ←↓↑→/hjkl select  H/L move card  n new…",
+	}
+
+	if len(lines) != len(expected) {
+		t.Fatalf("returned %d lines; expected %d", len(lines), len(expected))
+	}
+
+	for i, line := range lines {
+		if got := strings.TrimRight(stripStyles(line), " "); got != expected[i] {
+			t.Fatalf("test-case: (line %d); returned %q; expected %q", i, got, expected[i])
+		}
+
+		// Review is selected, both of its lines.
+		if selected := i == 4 || i == 5; strings.Contains(line, styleReversed) != selected {
+			t.Fatalf("test-case: (line %d); returned %q; expected only the selected card reversed", i, line)
+		}
+	}
+}
+
+func stripStyles(line string) string {
+	for _, style := range []string{styleBold, styleDim, styleReversed, styleReset} {
+		line = strings.ReplaceAll(line, style, "")
+	}
+
+	return line
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// key is a key pressed: the character typed, or the name of a special key in angle
+// brackets.
+type key string
+
+const (
+	keyUp         key = "<up>"
+	keyDown       key = "<down>"
+	keyLeft       key = "<left>"
+	keyRight      key = "<right>"
+	keyShiftLeft  key = "<shift-left>"
+	keyShiftRight key = "<shift-right>"
+	keyEnter      key = "<enter>"
+	keyEscape     key = "<escape>"
+	keyBackspace  key = "<backspace>"
+	keyCtrlC      key = "<ctrl-c>"
+	keyCtrlU      key = "<ctrl-u>"
+)
+
+// escapeKeys are the escape sequences of special keys, as xterm and its descendants
+// send them in normal and application cursor mode.
+var escapeKeys = map[string]key{
+	"[A": keyUp, "[B": keyDown, "[C": keyRight, "[D": keyLeft,
+	"OA": keyUp, "OB": keyDown, "OC": keyRight, "OD": keyLeft,
+	"[1;2C": keyShiftRight, "[1;2D": keyShiftLeft,
+}
+
+// Unknown escape sequences and control characters are dropped. A lone escape is the
+// escape key.
+func parseKeys(input []byte) []key {
+	var keys []key
+
+	for len(input) > 0 {
+		switch c := input[0]; {
+		case c == 0x1b && len(input) == 1:
+			keys = append(keys, keyEscape)
+			input = input[1:]
+		case c == 0x1b:
+			sequence, rest := splitEscape(input[1:])
+			if k, ok := escapeKeys[sequence]; ok {
+				keys = append(keys, k)
+			}
+
+			input = rest
+		case c == '\r' || c == '\n':
+			keys = append(keys, keyEnter)
+			input = input[1:]
+		case c == 0x7f || c == 0x08:
+			keys = append(keys, keyBackspace)
+			input = input[1:]
+		case c == 0x03:
+			keys = append(keys, keyCtrlC)
+			input = input[1:]
+		case c == 0x15:
+			keys = append(keys, keyCtrlU)
+			input = input[1:]
+		case c < 0x20:
+			input = input[1:]
+		default:
+			r, size := utf8.DecodeRune(input)
+			if r != utf8.RuneError {
+				keys = append(keys, key(string(r)))
+			}
+
+			input = input[size:]
+		}
+	}
+
+	return keys
+}
+
+func splitEscape(input []byte) (string, []byte) {
+	if len(input) > 1 && input[0] == '[' {
+		if end := strings.IndexFunc(string(input[1:]), func(r rune) bool { return r >= 0x40 && r <= 0x7e }); end >= 0 {
+			return string(input[:end+2]), input[end+2:]
+		}
+
+		return string(input), nil
+	}
+
+	if len(input) > 1 && input[0] == 'O' {
+		return string(input[:2]), input[2:]
+	}
+
+	return string(input[:1]), input[1:]
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseKeys(t *testing.T) {
+	tests := map[string]struct {
+		input    string
+		expected []key
+	}{
+		"characters":        {"jé#", []key{"j", "é", "#"}},
+		"arrows":            {"\x1b[A\x1b[B\x1bOC\x1b[D", []key{keyUp, keyDown, keyRight, keyLeft}},
+		"shifted arrows":    {"\x1b[1;2C\x1b[1;2D", []key{keyShiftRight, keyShiftLeft}},
+		"controls":          {"\r\x7f\x03\x15", []key{keyEnter, keyBackspace, keyCtrlC, keyCtrlU}},
+		"lone escape":       {"\x1b", []key{keyEscape}},
+		"unknown sequences": {"\x1b[15~a\x1b[5;3Hb\x01", []key{"a", "b"}},
+		"cut sequence":      {"\x1b[1;", nil},
+	}
+
+	for name, test := range tests {
+		if keys := parseKeys([]byte(test.input)); !slices.Equal(keys, test.expected) {
+			t.Fatalf("test-case: (%q); returned %q; expected %q", name, keys, test.expected)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"task-tracker/client"
+)
+
+const usage = `usage: tasktui [-server URL [-workspace ID] | -file PATH] [-columns S,T] [-refresh DURATION]
+
+Without -server, tasks are kept in memory, loaded from and saved to -file if given.
+The file is a JSON export, not a SQLite database, and is written only on exit.
+The API key of the server comes from $TASKTUI_API_KEY.
+
+Keys: arrows or hjkl select a card, H and L (or shift and an arrow) move it to the
+previous or next column, n adds a task to the column, e or enter edits the title, a
+the assignee and # the labels, r reloads the board and q quits.
+`
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	err := run(ctx, os.Args[1:], os.Stdin, os.Stdout)
+
+	stop()
+
+	switch {
+	case errors.Is(err, flag.ErrHelp):
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	case err != nil:
+		fmt.Fprintln(os.Stderr, "tasktui:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string, stdin, stdout *os.File) error {
+	flags := flag.NewFlagSet("tasktui", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+
+	server := flags.String("server", "", "base URL of the server, an embedded store by default")
+	workspace := flags.String("workspace", "", "workspace of the server to show, the default one by default")
+	file := flags.String("file", "", "JSON file the embedded store is loaded from and saved to")
+	columns := flags.String("columns", "todo,in_progress,done", "comma-separated statuses shown first, in order")
+	refresh := flags.Duration("refresh", 5*time.Second, "how often the board is reloaded")
+
+	if err := flags.Parse(args); err != nil || flags.NArg() > 0 || *refresh <= 0 ||
+		(*server != "" && *file != "") || (*server == "" && *workspace != "") {
+		return flag.ErrHelp
+	}
+
+	statuses := splitList(*columns)
+	if len(statuses) == 0 {
+		return flag.ErrHelp
+	}
+
+	if *server != "" {
+		c, err := client.New(*server, client.WithAPIKey(os.Getenv("TASKTUI_API_KEY")), client.WithWorkspace(*workspace))
+		if err != nil {
+			return err
+		}
+
+		return runTerminal(ctx, newBoard(c, *server, statuses), stdin, stdout, *refresh)
+	}
+
+	local, err := newLocalStore(ctx, *file)
+	if err != nil {
+		return err
+	}
+
+	source := *file
+	if source == "" {
+		source = "memory"
+	}
+
+	// Saved even after an interrupt, which ends the board like q does, or a terminal
+	// error, so the changes made before it aren't lost.
+	err = runTerminal(ctx, newBoard(local, source, statuses), stdin, stdout, *refresh)
+	if saveErr := local.Save(context.WithoutCancel(ctx)); err == nil {
+		err = saveErr
+	}
+
+	return err
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRun_SavesOnError(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tasks.json")
+
+	// A plain file isn't a terminal, so the board fails before it's shown.
+	stdin, err := os.Create(filepath.Join(dir, "stdin"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer stdin.Close()
+
+	if err := run(context.Background(), []string{"-file", path}, stdin, stdin); err == nil {
+		t.Fatalf("returned no error; expected the terminal to be rejected")
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("returned %v; expected the store to be saved", err)
+	}
+}
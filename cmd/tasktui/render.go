@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// SGR sequences cards are drawn with.
+const (
+	styleBold     = "\x1b[1m"
+	styleDim      = "\x1b[2m"
+	styleReversed = "\x1b[7m"
+	styleReset    = "\x1b[0m"
+)
+
+const help = "←↓↑→/hjkl select  H/L move card  n new  e title  a assignee  # labels  r refresh  q quit"
+
+// cardHeight is the lines a card takes: its title and a line of details.
+const cardHeight = 2
+
+// The selected column scrolls to keep its selected card in view.
+func (b *board) render(width, height int) []string {
+	lines := make([]string, 0, height)
+
+	count := 0
+	for _, column := range b.columns {
+		count += len(column.tasks)
+	}
+
+	lines = append(lines, styleBold+fit(fmt.Sprintf(" %s · %d tasks · loaded %s", b.source, count, b.loadedAt.Format(time.TimeOnly)), width)+styleReset)
+
+	if len(b.columns) == 0 {
+		return lines
+	}
+
+	columnWidth := max(1, width/len(b.columns))
+	visible := max(1, (height-4)/cardHeight)
+
+	var header strings.Builder
+	for _, column := range b.columns {
+		header.WriteString(fit(fmt.Sprintf(" %s (%d)", strings.ToUpper(column.status), len(column.tasks)), columnWidth))
+	}
+
+	lines = append(lines, styleDim+header.String()+styleReset)
+
+	offset := 0
+	if b.row >= visible {
+		offset = b.row - visible + 1
+	}
+
+	for i := range visible {
+		var title, details strings.Builder
+
+		for col, column := range b.columns {
+			row := i
+			if col == b.col {
+				row += offset
+			}
+
+			if row >= len(column.tasks) {
+				title.WriteString(strings.Repeat(" ", columnWidth))
+				details.WriteString(strings.Repeat(" ", columnWidth))
+
+				continue
+			}
+
+			task := column.tasks[row]
+			style := ""
+
+			if col == b.col && row == b.row {
+				style = styleReversed
+			}
+
+			title.WriteString(style + fit(" "+task.Title, columnWidth) + styleReset)
+			details.WriteString(style + styleDim + fit("   "+cardDetails(task.Assignee, task.DueAt, task.Labels), columnWidth) + styleReset)
+		}
+
+		lines = append(lines, title.String(), details.String())
+	}
+
+	for len(lines) < height-2 {
+		lines = append(lines, "")
+	}
+
+	switch {
+	case b.prompt != nil:
+		lines = append(lines, fit(fmt.Sprintf(" %s: %s█", b.prompt.label, string(b.prompt.value)), width))
+	case b.message != "":
+		lines = append(lines, styleBold+fit(" "+b.message, width)+styleReset)
+	default:
+		lines = append(lines, "")
+	}
+
+	return append(lines, styleDim+fit(" "+help, width)+styleReset)
+}
+
+func cardDetails(assignee string, dueAt *time.Time, labels []string) string {
+	var details []string
+
+	if assignee != "" {
+		details = append(details, "@"+assignee)
+	}
+
+	if dueAt != nil {
+		details = append(details, "due "+dueAt.Local().Format("Jan 2 15:04"))
+	}
+
+	if len(labels) > 0 {
+		details = append(details, "#"+strings.Join(labels, " #"))
+	}
+
+	return strings.Join(details, " · ")
+}
+
+func fit(s string, width int) string {
+	n := utf8.RuneCountInString(s)
+
+	if n <= width {
+		return s + strings.Repeat(" ", width-n)
+	}
+
+	if width <= 1 {
+		return string([]rune(s)[:width])
+	}
+
+	return string([]rune(s)[:width-1]) + "…"
+}
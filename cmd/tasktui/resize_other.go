@@ -0,0 +1,9 @@
+//go:build !unix
+
+package main
+
+import "os"
+
+// notifyResize does nothing without SIGWINCH; the board catches up with the size of
+// the terminal when it's next drawn.
+func notifyResize(chan<- os.Signal) {}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"task-tracker/client"
+	"task-tracker/internal/clock"
+	"task-tracker/internal/models"
+	"task-tracker/internal/repository"
+	"task-tracker/internal/service"
+	"task-tracker/internal/transfer"
+)
+
+// store holds the tasks of the board. *client.Client is the store of a server.
+type store interface {
+	GetAll(ctx context.Context, options ...client.ListOption) ([]client.Task, error)
+	Add(ctx context.Context, task *client.Task) error
+	Update(ctx context.Context, task *client.Task) error
+}
+
+// localStore isn't SQLite, the module has no SQLite driver. The tasks of a -file live in
+// memory while the board runs and are written back only on exit, so a crash loses the
+// changes of the run and two boards on one file overwrite each other.
+type localStore struct {
+	tasks service.TaskService
+	path  string
+}
+
+func newLocalStore(ctx context.Context, path string) (*localStore, error) {
+	clk := clock.New()
+	s := &localStore{
+		tasks: service.NewDefaultTaskService(repository.NewMemoryTaskRepository(clk), clk, service.AllowAll{}),
+		path:  path,
+	}
+
+	if path == "" {
+		return s, nil
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	report, err := service.NewImporter(s.tasks).Import(ctx, transfer.NewDecoder(file, transfer.JSON), false)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(report.Errors) > 0 {
+		return nil, fmt.Errorf("%s: row %d: %s", path, report.Errors[0].Row, report.Errors[0].Error)
+	}
+
+	return s, nil
+}
+
+// GetAll ignores the options, the board lists every task.
+func (s *localStore) GetAll(ctx context.Context, _ ...client.ListOption) ([]client.Task, error) {
+	tasks, err := s.tasks.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	converted := make([]client.Task, len(tasks))
+	for i, task := range tasks {
+		converted[i] = fromModel(task)
+	}
+
+	return converted, nil
+}
+
+// Add and Update validate tasks like the server validates requests.
+func (s *localStore) Add(ctx context.Context, task *client.Task) error {
+	request := models.CreateTaskRequest{
+		Title:       task.Title,
+		Description: task.Description,
+		Status:      task.Status,
+		Assignee:    task.Assignee,
+		DueAt:       task.DueAt,
+		Labels:      task.Labels,
+	}
+
+	if err := request.Validate(); err != nil {
+		return err
+	}
+
+	added := request.ConvertToTask()
+
+	if err := s.tasks.Add(ctx, added); err != nil {
+		return err
+	}
+
+	*task = fromModel(*added)
+
+	return nil
+}
+
+func (s *localStore) Update(ctx context.Context, task *client.Task) error {
+	request := models.UpdateTaskRequest{
+		Title:       task.Title,
+		Description: task.Description,
+		Status:      task.Status,
+		Assignee:    task.Assignee,
+		Labels:      task.Labels,
+	}
+
+	if err := request.Validate(); err != nil {
+		return err
+	}
+
+	return s.tasks.Update(ctx, request.ConvertToTask(task.ID))
+}
+
+// Save replaces the file only once all tasks are written.
+func (s *localStore) Save(ctx context.Context) error {
+	if s.path == "" {
+		return nil
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	encoder := transfer.NewEncoder(file, transfer.JSON)
+
+	err = s.tasks.Stream(ctx, encoder.Encode)
+	if err == nil {
+		err = encoder.Close()
+	}
+
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), s.path)
+}
+
+func fromModel(task models.Task) client.Task {
+	return client.Task{
+		ID:          task.ID,
+		WorkspaceID: task.WorkspaceID,
+		Title:       task.Title,
+		Description: task.Description,
+		Status:      task.Status,
+		CreatedBy:   task.CreatedBy,
+		Assignee:    task.Assignee,
+		CreatedAt:   task.CreatedAt,
+		UpdatedAt:   task.UpdatedAt,
+		DueAt:       task.DueAt,
+		Labels:      task.Labels,
+	}
+}
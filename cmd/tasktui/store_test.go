@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"task-tracker/client"
+)
+
+func TestLocalStore_File(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "tasks.json")
+
+	s, err := newLocalStore(ctx, path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	task := client.Task{Title: "Deploy", Description: "Roll out", Status: client.StatusTodo, Labels: []string{"ops"}}
+	if err := s.Add(ctx, &task); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := s.Save(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reloaded, err := newLocalStore(ctx, path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tasks, err := reloaded.GetAll(ctx)
+	if err != nil || len(tasks) != 1 || tasks[0].ID != task.ID || tasks[0].Description != "Roll out" || len(tasks[0].Labels) != 1 {
+		t.Fatalf("returned %+v, %v; expected %+v", tasks, err, task)
+	}
+
+	if err := os.WriteFile(path, []byte(`[{"title": "No description", "status": "todo"}]`), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := newLocalStore(ctx, path); err == nil {
+		t.Fatalf("returned no error; expected the invalid task to be reported")
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// Escape sequences switching to the alternate screen with a hidden cursor and back.
+const (
+	enterScreen = "\x1b[?1049h\x1b[?25l"
+	leaveScreen = "\x1b[?25h\x1b[?1049l"
+)
+
+// The board is reloaded every refresh interval so changes made elsewhere show up.
+func runTerminal(ctx context.Context, b *board, in, out *os.File, refresh time.Duration) error {
+	fd := int(in.Fd())
+
+	if !term.IsTerminal(fd) || !term.IsTerminal(int(out.Fd())) {
+		return errors.New("tasktui needs a terminal")
+	}
+
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return err
+	}
+	defer term.Restore(fd, state)
+
+	io.WriteString(out, enterScreen)
+	defer io.WriteString(out, leaveScreen)
+
+	// The reader is left blocked in Read when the board quits, which ends the program.
+	keys := make(chan []key)
+
+	go func() {
+		buf := make([]byte, 256)
+
+		for {
+			n, err := in.Read(buf)
+			if err != nil {
+				close(keys)
+				return
+			}
+
+			keys <- parseKeys(buf[:n])
+		}
+	}()
+
+	resized := make(chan os.Signal, 1)
+	notifyResize(resized)
+	defer signal.Stop(resized)
+
+	ticker := time.NewTicker(refresh)
+	defer ticker.Stop()
+
+	b.apply(ctx, nil)
+
+	for {
+		draw(out, b)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-resized:
+		case <-ticker.C:
+			// Typing into a prompt isn't interrupted by the board changing under it.
+			if b.prompt == nil {
+				b.apply(ctx, nil)
+			}
+		case pressed, ok := <-keys:
+			if !ok {
+				return nil
+			}
+
+			for _, k := range pressed {
+				if b.handle(ctx, k) {
+					return nil
+				}
+			}
+		}
+	}
+}
+
+// Clearing what's left of each line rather than the whole screen avoids flicker.
+func draw(out *os.File, b *board) {
+	width, height, err := term.GetSize(int(out.Fd()))
+	if err != nil {
+		width, height = 80, 24
+	}
+
+	var screen strings.Builder
+
+	screen.WriteString("\x1b[H")
+
+	for i, line := range b.render(width, height) {
+		if i > 0 {
+			screen.WriteString("\r\n")
+		}
+
+		screen.WriteString(line + "\x1b[K")
+	}
+
+	screen.WriteString("\x1b[J")
+	io.WriteString(out, screen.String())
+}
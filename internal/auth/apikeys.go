@@ -0,0 +1,186 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"task-tracker/internal/clock"
+	"task-tracker/internal/models"
+	"task-tracker/internal/repository"
+)
+
+const (
+	keyPrefix = "tt_"
+
+	// lastUsedResolution limits how often a busy key's last-used timestamp is written.
+	lastUsedResolution = time.Minute
+)
+
+// Keys look like tt_<id>_<secret>, only a bcrypt hash of the whole key is stored.
+type APIKeyService struct {
+	repo  repository.APIKeyRepository
+	clock clock.Clock
+	cost  int
+
+	// verified skips bcrypt, which is deliberately slow, for keys seen before.
+	mu       sync.Mutex
+	verified map[string][sha256.Size]byte
+}
+
+func NewAPIKeyService(repo repository.APIKeyRepository, clock clock.Clock) *APIKeyService {
+	return &APIKeyService{
+		repo:     repo,
+		clock:    clock,
+		cost:     bcrypt.DefaultCost,
+		verified: make(map[string][sha256.Size]byte),
+	}
+}
+
+func (s *APIKeyService) Create(ctx context.Context, request models.CreateAPIKeyRequest) (models.APIKey, string, error) {
+	if err := request.Validate(); err != nil {
+		return models.APIKey{}, "", err
+	}
+
+	id, err := randomString(8, hex.EncodeToString)
+	if err != nil {
+		return models.APIKey{}, "", err
+	}
+
+	secret, err := randomString(32, base64.RawURLEncoding.EncodeToString)
+	if err != nil {
+		return models.APIKey{}, "", err
+	}
+
+	plaintext := keyPrefix + id + "_" + secret
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(plaintext), s.cost)
+	if err != nil {
+		return models.APIKey{}, "", fmt.Errorf("error hashing api key: %w", err)
+	}
+
+	key := models.APIKey{
+		ID:        id,
+		Name:      request.Name,
+		Hash:      string(hash),
+		Scopes:    request.Scopes,
+		CreatedAt: s.clock.Now(),
+	}
+
+	if err := s.repo.Add(ctx, &key); err != nil {
+		return models.APIKey{}, "", err
+	}
+
+	return key, plaintext, nil
+}
+
+func (s *APIKeyService) List(ctx context.Context) ([]models.APIKey, error) {
+	return s.repo.GetAll(ctx)
+}
+
+func (s *APIKeyService) Revoke(ctx context.Context, id string) error {
+	if err := s.repo.Revoke(ctx, id, s.clock.Now()); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	delete(s.verified, id)
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *APIKeyService) Authenticate(ctx context.Context, plaintext string) (Principal, error) {
+	id, ok := parseKeyID(plaintext)
+	if !ok {
+		return Principal{}, models.ErrUnauthorized
+	}
+
+	key, err := s.repo.Get(ctx, id)
+	if errors.Is(err, models.ErrAPIKeyNotFound) {
+		return Principal{}, models.ErrUnauthorized
+	}
+
+	if err != nil {
+		return Principal{}, err
+	}
+
+	if key.Revoked() || !s.verify(key, plaintext) {
+		return Principal{}, models.ErrUnauthorized
+	}
+
+	now := s.clock.Now()
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedResolution {
+		if err := s.repo.Touch(ctx, key.ID, now); err != nil {
+			return Principal{}, err
+		}
+	}
+
+	return Principal{
+		Subject: "apikey:" + key.ID,
+		Name:    key.Name,
+		Roles:   []string{string(models.RoleForScopes(key.Scopes))},
+		Scopes:  key.Scopes,
+		KeyID:   key.ID,
+	}, nil
+}
+
+func (s *APIKeyService) verify(key models.APIKey, plaintext string) bool {
+	digest := sha256.Sum256([]byte(plaintext))
+
+	s.mu.Lock()
+	cached, found := s.verified[key.ID]
+	s.mu.Unlock()
+
+	if found && cached == digest {
+		return true
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(key.Hash), []byte(plaintext)) != nil {
+		return false
+	}
+
+	s.mu.Lock()
+	s.verified[key.ID] = digest
+	s.mu.Unlock()
+
+	return true
+}
+
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, keyPrefix)
+}
+
+func parseKeyID(plaintext string) (string, bool) {
+	rest, ok := strings.CutPrefix(plaintext, keyPrefix)
+	if !ok {
+		return "", false
+	}
+
+	id, secret, ok := strings.Cut(rest, "_")
+	if !ok || id == "" || secret == "" {
+		return "", false
+	}
+
+	return id, true
+}
+
+func randomString(size int, encode func([]byte) string) (string, error) {
+	b := make([]byte, size)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error generating api key: %w", err)
+	}
+
+	return encode(b), nil
+}
@@ -0,0 +1,136 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"task-tracker/internal/clock"
+	"task-tracker/internal/models"
+	"task-tracker/internal/repository"
+)
+
+func newTestService(repo repository.APIKeyRepository) *APIKeyService {
+	service := NewAPIKeyService(repo, clock.NewFixed(time.Date(2025, time.April, 9, 8, 0, 0, 0, time.UTC)))
+	service.cost = bcrypt.MinCost
+
+	return service
+}
+
+func TestAPIKeyService_Create(t *testing.T) {
+	tests := map[string]struct {
+		request     models.CreateAPIKeyRequest
+		expectedErr error
+	}{
+		"valid key": {
+			request: models.CreateAPIKeyRequest{Name: "ci", Scopes: []models.Scope{models.ScopeRead}},
+		},
+
+		"empty name": {
+			request:     models.CreateAPIKeyRequest{Scopes: []models.Scope{models.ScopeRead}},
+			expectedErr: models.ErrNameIsEmpty,
+		},
+
+		"no scopes": {
+			request:     models.CreateAPIKeyRequest{Name: "ci"},
+			expectedErr: models.ErrScopesAreEmpty,
+		},
+
+		"unknown scope": {
+			request:     models.CreateAPIKeyRequest{Name: "ci", Scopes: []models.Scope{"root"}},
+			expectedErr: models.ErrInvalidScope,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := repository.NewMemoryAPIKeyRepository()
+			service := newTestService(repo)
+
+			key, plaintext, err := service.Create(context.Background(), test.request)
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("test-case: (%q); returned %v; expected %v", name, err, test.expectedErr)
+			}
+
+			if test.expectedErr != nil {
+				return
+			}
+
+			stored, err := repo.Get(context.Background(), key.ID)
+			if err != nil {
+				t.Fatalf("test-case: (%q); unexpected error: %v", name, err)
+			}
+
+			if !strings.HasPrefix(plaintext, keyPrefix+key.ID+"_") || strings.Contains(stored.Hash, plaintext) {
+				t.Fatalf("test-case: (%q); key %q isn't stored hashed under id %q", name, plaintext, key.ID)
+			}
+		})
+	}
+}
+
+func TestAPIKeyService_Authenticate(t *testing.T) {
+	repo := repository.NewMemoryAPIKeyRepository()
+	service := newTestService(repo)
+	ctx := context.Background()
+
+	key, plaintext, err := service.Create(ctx, models.CreateAPIKeyRequest{Name: "ci", Scopes: []models.Scope{models.ScopeWrite}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	principal, err := service.Authenticate(ctx, plaintext)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if principal.KeyID != key.ID || !principal.HasScope(models.ScopeRead) || principal.HasScope(models.ScopeAdmin) {
+		t.Fatalf("returned %+v; expected write access for key %s", principal, key.ID)
+	}
+
+	roles := map[models.Scope]models.Role{
+		models.ScopeRead:     models.RoleViewer,
+		models.ScopeWrite:    models.RoleMember,
+		models.ScopeMaintain: models.RoleMaintainer,
+		models.ScopeAdmin:    models.RoleAdmin,
+	}
+
+	for scope, expected := range roles {
+		_, plaintext, err := service.Create(ctx, models.CreateAPIKeyRequest{Name: string(scope), Scopes: []models.Scope{scope}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if principal, err := service.Authenticate(ctx, plaintext); err != nil || principal.Role() != expected {
+			t.Fatalf("test-case: (%q); returned [%v %v]; expected role %q", scope, principal.Role(), err, expected)
+		}
+	}
+
+	stored, _ := repo.Get(ctx, key.ID)
+	if stored.LastUsedAt == nil || !stored.LastUsedAt.Equal(service.clock.Now()) {
+		t.Fatalf("last used at is %v; expected %v", stored.LastUsedAt, service.clock.Now())
+	}
+
+	for _, invalid := range []string{"", "tt_", plaintext + "x", "tt_unknown_secret", strings.TrimPrefix(plaintext, keyPrefix)} {
+		if _, err := service.Authenticate(ctx, invalid); !errors.Is(err, models.ErrUnauthorized) {
+			t.Fatalf("key %q: returned %v; expected %v", invalid, err, models.ErrUnauthorized)
+		}
+	}
+
+	if err := service.Revoke(ctx, key.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := service.Authenticate(ctx, plaintext); !errors.Is(err, models.ErrUnauthorized) {
+		t.Fatalf("revoked key: returned %v; expected %v", err, models.ErrUnauthorized)
+	}
+
+	if err := service.Revoke(ctx, "missing"); !errors.Is(err, models.ErrAPIKeyNotFound) {
+		t.Fatalf("returned %v; expected %v", err, models.ErrAPIKeyNotFound)
+	}
+}
@@ -0,0 +1,136 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"task-tracker/internal/clock"
+	"task-tracker/internal/models"
+	"task-tracker/internal/repository"
+)
+
+// feedTokenPrefix tells feed tokens apart from API keys, which can't be used in their place.
+const feedTokenPrefix = "tf_"
+
+// Feed tokens are random enough for a SHA-256 hash, which keeps calendar apps polling
+// the feed cheap compared to the bcrypt hash of API keys.
+type FeedTokenService struct {
+	repo  repository.FeedTokenRepository
+	clock clock.Clock
+}
+
+func NewFeedTokenService(repo repository.FeedTokenRepository, clock clock.Clock) *FeedTokenService {
+	return &FeedTokenService{
+		repo:  repo,
+		clock: clock,
+	}
+}
+
+func (s *FeedTokenService) Create(ctx context.Context) (models.FeedToken, string, error) {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return models.FeedToken{}, "", models.ErrUnauthorized
+	}
+
+	id, err := randomString(8, hex.EncodeToString)
+	if err != nil {
+		return models.FeedToken{}, "", err
+	}
+
+	secret, err := randomString(32, base64.RawURLEncoding.EncodeToString)
+	if err != nil {
+		return models.FeedToken{}, "", err
+	}
+
+	plaintext := feedTokenPrefix + id + "_" + secret
+
+	token := models.FeedToken{
+		ID:        id,
+		Subject:   principal.Subject,
+		Hash:      hashFeedToken(plaintext),
+		CreatedAt: s.clock.Now(),
+	}
+
+	if err := s.repo.Add(ctx, &token); err != nil {
+		return models.FeedToken{}, "", err
+	}
+
+	return token, plaintext, nil
+}
+
+func (s *FeedTokenService) List(ctx context.Context) ([]models.FeedToken, error) {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return nil, models.ErrUnauthorized
+	}
+
+	return s.repo.GetAllForSubject(ctx, principal.Subject)
+}
+
+// Other users' tokens are reported as not found.
+func (s *FeedTokenService) Revoke(ctx context.Context, id string) error {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return models.ErrUnauthorized
+	}
+
+	token, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if token.Subject != principal.Subject {
+		return models.ErrFeedTokenNotFound
+	}
+
+	return s.repo.Revoke(ctx, id, s.clock.Now())
+}
+
+func (s *FeedTokenService) Authenticate(ctx context.Context, plaintext string) (Principal, error) {
+	rest, ok := strings.CutPrefix(plaintext, feedTokenPrefix)
+	if !ok {
+		return Principal{}, models.ErrUnauthorized
+	}
+
+	id, secret, ok := strings.Cut(rest, "_")
+	if !ok || id == "" || secret == "" {
+		return Principal{}, models.ErrUnauthorized
+	}
+
+	token, err := s.repo.Get(ctx, id)
+	if errors.Is(err, models.ErrFeedTokenNotFound) {
+		return Principal{}, models.ErrUnauthorized
+	}
+
+	if err != nil {
+		return Principal{}, err
+	}
+
+	if token.Revoked() || subtle.ConstantTimeCompare([]byte(token.Hash), []byte(hashFeedToken(plaintext))) != 1 {
+		return Principal{}, models.ErrUnauthorized
+	}
+
+	now := s.clock.Now()
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= lastUsedResolution {
+		if err := s.repo.Touch(ctx, token.ID, now); err != nil {
+			return Principal{}, err
+		}
+	}
+
+	return Principal{
+		Subject: token.Subject,
+		Roles:   []string{string(models.RoleViewer)},
+		Scopes:  []models.Scope{models.ScopeRead},
+	}, nil
+}
+
+func hashFeedToken(plaintext string) string {
+	digest := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(digest[:])
+}
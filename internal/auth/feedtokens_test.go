@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"task-tracker/internal/clock"
+	"task-tracker/internal/models"
+	"task-tracker/internal/repository"
+)
+
+func TestFeedTokenService_Authenticate(t *testing.T) {
+	alice := WithPrincipal(context.Background(), Principal{Subject: "alice", Roles: []string{string(models.RoleAdmin)}})
+	bob := WithPrincipal(context.Background(), Principal{Subject: "bob"})
+
+	service := NewFeedTokenService(repository.NewMemoryFeedTokenRepository(),
+		clock.NewFixed(time.Date(2025, time.April, 9, 8, 0, 0, 0, time.UTC)))
+
+	token, plaintext, err := service.Create(alice)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	principal, err := service.Authenticate(context.Background(), plaintext)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if principal.Subject != "alice" || principal.Role() != models.RoleViewer || principal.HasScope(models.ScopeWrite) {
+		t.Fatalf("returned %+v; expected a read-only principal of alice", principal)
+	}
+
+	for _, invalid := range []string{"", "tf_" + token.ID + "_wrong", "tt_" + plaintext[3:], plaintext + "x"} {
+		if _, err := service.Authenticate(context.Background(), invalid); !errors.Is(err, models.ErrUnauthorized) {
+			t.Fatalf("test-case: (%q); returned %v; expected %v", invalid, err, models.ErrUnauthorized)
+		}
+	}
+
+	if err := service.Revoke(bob, token.ID); !errors.Is(err, models.ErrFeedTokenNotFound) {
+		t.Fatalf("returned %v; expected other users' tokens to be hidden", err)
+	}
+
+	if err := service.Revoke(alice, token.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := service.Authenticate(context.Background(), plaintext); !errors.Is(err, models.ErrUnauthorized) {
+		t.Fatalf("returned %v; expected revoked tokens to be rejected", err)
+	}
+}
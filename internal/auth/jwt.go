@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+
+	"task-tracker/internal/clock"
+	"task-tracker/internal/models"
+)
+
+const clockSkew = time.Minute
+
+var signatureAlgorithms = []jose.SignatureAlgorithm{
+	jose.RS256, jose.RS384, jose.RS512,
+	jose.PS256, jose.PS384, jose.PS512,
+	jose.ES256, jose.ES384, jose.ES512,
+	jose.EdDSA,
+}
+
+type JWTVerifierConfig struct {
+	Issuer   string
+	Audience string
+	// RolesClaim names the claim holding the user's roles, "roles" if empty.
+	RolesClaim string
+}
+
+type JWTVerifier struct {
+	keys   KeySet
+	config JWTVerifierConfig
+	clock  clock.Clock
+}
+
+func NewJWTVerifier(keys KeySet, config JWTVerifierConfig, clock clock.Clock) *JWTVerifier {
+	if config.RolesClaim == "" {
+		config.RolesClaim = "roles"
+	}
+
+	return &JWTVerifier{
+		keys:   keys,
+		config: config,
+		clock:  clock,
+	}
+}
+
+// Invalid tokens yield models.ErrUnauthorized, other errors mean the keys couldn't be loaded.
+func (v *JWTVerifier) Verify(ctx context.Context, token string) (Principal, error) {
+	parsed, err := jwt.ParseSigned(token, signatureAlgorithms)
+	if err != nil || len(parsed.Headers) != 1 {
+		return Principal{}, models.ErrUnauthorized
+	}
+
+	key, found, err := v.keys.Key(ctx, parsed.Headers[0].KeyID)
+	if err != nil {
+		return Principal{}, err
+	}
+
+	if !found {
+		return Principal{}, models.ErrUnauthorized
+	}
+
+	var (
+		claims jwt.Claims
+		extra  map[string]any
+	)
+
+	if err := parsed.Claims(key.Key, &claims, &extra); err != nil {
+		return Principal{}, models.ErrUnauthorized
+	}
+
+	expected := jwt.Expected{Issuer: v.config.Issuer, Time: v.clock.Now()}
+	if v.config.Audience != "" {
+		expected.AnyAudience = jwt.Audience{v.config.Audience}
+	}
+
+	if err := claims.ValidateWithLeeway(expected, clockSkew); err != nil || claims.Subject == "" || claims.Expiry == nil {
+		return Principal{}, models.ErrUnauthorized
+	}
+
+	roles := stringList(extra[v.config.RolesClaim])
+
+	return Principal{
+		Subject: claims.Subject,
+		Name:    firstString(extra, "name", "preferred_username", "email"),
+		Roles:   roles,
+		Scopes:  tokenScopes(extra["scope"], models.HighestRole(roles)),
+	}, nil
+}
+
+// Tokens without a scope claim act for a user, whose roles decide what they may do.
+func tokenScopes(claim any, role models.Role) []models.Scope {
+	if claim == nil && role == models.RoleAdmin {
+		return []models.Scope{models.ScopeAdmin}
+	}
+
+	if claim == nil {
+		return []models.Scope{models.ScopeWrite}
+	}
+
+	var scopes []models.Scope
+
+	for _, scope := range stringList(claim) {
+		if scope := models.Scope(strings.TrimPrefix(scope, "tasks:")); scope.Valid() {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	return scopes
+}
+
+func firstString(claims map[string]any, names ...string) string {
+	for _, name := range names {
+		if value, ok := claims[name].(string); ok && value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// Identity providers send roles and scopes as a JSON array or a space-separated string.
+func stringList(claim any) []string {
+	switch v := claim.(type) {
+	case string:
+		return strings.Fields(v)
+	case []any:
+		list := make([]string, 0, len(v))
+
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+
+		return list
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,341 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+
+	"task-tracker/internal/clock"
+	"task-tracker/internal/models"
+)
+
+var issuedAt = time.Date(2025, time.April, 9, 8, 0, 0, 0, time.UTC)
+
+func newTestIssuer(t *testing.T, issuer string) *TestIssuer {
+	t.Helper()
+
+	testIssuer, err := NewTestIssuer(issuer, "task-tracker", clock.NewFixed(issuedAt))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return testIssuer
+}
+
+func TestJWTVerifier_Verify(t *testing.T) {
+	issuer := newTestIssuer(t, "https://id.example.com")
+	other := newTestIssuer(t, "https://id.example.com")
+
+	issue := func(issuer *TestIssuer, request TestTokenRequest) string {
+		token, _, err := issuer.Issue(request)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		return token
+	}
+
+	tests := map[string]struct {
+		token    string
+		config   JWTVerifierConfig
+		now      time.Time
+		expected Principal
+		err      error
+	}{
+		"valid token": {
+			token:  issue(issuer, TestTokenRequest{Subject: "alice", Name: "Alice", Roles: []string{"member"}}),
+			config: JWTVerifierConfig{Issuer: "https://id.example.com", Audience: "task-tracker"},
+			now:    issuedAt.Add(time.Minute),
+			expected: Principal{
+				Subject: "alice",
+				Name:    "Alice",
+				Roles:   []string{"member"},
+				Scopes:  []models.Scope{models.ScopeWrite},
+			},
+		},
+
+		"scope claim limits access": {
+			token:    issue(issuer, TestTokenRequest{Subject: "bot", Scope: "openid tasks:read"}),
+			config:   JWTVerifierConfig{Issuer: "https://id.example.com"},
+			now:      issuedAt,
+			expected: Principal{Subject: "bot", Scopes: []models.Scope{models.ScopeRead}},
+		},
+
+		"expired token": {
+			token:  issue(issuer, TestTokenRequest{Subject: "alice", TTL: "5m"}),
+			config: JWTVerifierConfig{Issuer: "https://id.example.com"},
+			now:    issuedAt.Add(10 * time.Minute),
+			err:    models.ErrUnauthorized,
+		},
+
+		"wrong issuer": {
+			token:  issue(issuer, TestTokenRequest{Subject: "alice"}),
+			config: JWTVerifierConfig{Issuer: "https://evil.example.com"},
+			now:    issuedAt,
+			err:    models.ErrUnauthorized,
+		},
+
+		"wrong audience": {
+			token:  issue(issuer, TestTokenRequest{Subject: "alice"}),
+			config: JWTVerifierConfig{Issuer: "https://id.example.com", Audience: "billing"},
+			now:    issuedAt,
+			err:    models.ErrUnauthorized,
+		},
+
+		"signed with an unknown key": {
+			token:  issue(other, TestTokenRequest{Subject: "alice"}),
+			config: JWTVerifierConfig{Issuer: "https://id.example.com"},
+			now:    issuedAt,
+			err:    models.ErrUnauthorized,
+		},
+
+		"tampered token": {
+			token:  issue(issuer, TestTokenRequest{Subject: "alice"}) + "x",
+			config: JWTVerifierConfig{Issuer: "https://id.example.com"},
+			now:    issuedAt,
+			err:    models.ErrUnauthorized,
+		},
+
+		"not a jwt": {
+			token:  "hello",
+			config: JWTVerifierConfig{},
+			now:    issuedAt,
+			err:    models.ErrUnauthorized,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			verifier := NewJWTVerifier(StaticKeySet(issuer.KeySet()), test.config, clock.NewFixed(test.now))
+
+			principal, err := verifier.Verify(context.Background(), test.token)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("test-case: (%q); returned %v; expected %v", name, err, test.err)
+			}
+
+			if principal.Subject != test.expected.Subject || principal.Name != test.expected.Name ||
+				!slices.Equal(principal.Roles, test.expected.Roles) || !slices.Equal(principal.Scopes, test.expected.Scopes) {
+				t.Fatalf("test-case: (%q); returned %+v; expected %+v", name, principal, test.expected)
+			}
+		})
+	}
+}
+
+func TestStringList(t *testing.T) {
+	tests := map[string]struct {
+		claim    any
+		expected []string
+	}{
+		"array":             {claim: []any{"admin", "member", 42}, expected: []string{"admin", "member"}},
+		"space separated":   {claim: "admin member", expected: []string{"admin", "member"}},
+		"missing":           {claim: nil, expected: nil},
+		"unsupported shape": {claim: map[string]any{"admin": true}, expected: nil},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if result := stringList(test.claim); !slices.Equal(result, test.expected) {
+				t.Fatalf("test-case: (%q); returned %v; expected %v", name, result, test.expected)
+			}
+		})
+	}
+}
+
+func TestLoadKeySet(t *testing.T) {
+	issuer := newTestIssuer(t, "https://id.example.com")
+	kid := issuer.KeySet().Keys[0].KeyID
+
+	data, err := json.Marshal(issuer.KeySet())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Run("file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "jwks.json")
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		keys, err := LoadKeySet(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if _, found, err := keys.Key(context.Background(), kid); !found || err != nil {
+			t.Fatalf("returned %v, %v; expected key %s", found, err, kid)
+		}
+	})
+
+	t.Run("url", func(t *testing.T) {
+		var fetches atomic.Int32
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			fetches.Add(1)
+			_, _ = w.Write(data)
+		}))
+		defer ts.Close()
+
+		keys, err := LoadKeySet(ts.URL)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		for _, id := range []string{kid, kid, "unknown", "unknown"} {
+			_, found, err := keys.Key(context.Background(), id)
+			if err != nil || found != (id == kid) {
+				t.Fatalf("key %s: returned %v, %v", id, found, err)
+			}
+		}
+
+		if fetches.Load() != 1 {
+			t.Fatalf("fetched %d times; expected the key set to be cached", fetches.Load())
+		}
+	})
+
+	t.Run("concurrent fetches", func(t *testing.T) {
+		var fetches atomic.Int32
+
+		release := make(chan struct{})
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			fetches.Add(1)
+			<-release
+			_, _ = w.Write(data)
+		}))
+		defer ts.Close()
+
+		keys, err := LoadKeySet(ts.URL)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		errs := make(chan error, 3)
+
+		for range cap(errs) {
+			go func() {
+				_, found, err := keys.Key(context.Background(), kid)
+				if err == nil && !found {
+					err = errors.New("key not found")
+				}
+
+				errs <- err
+			}()
+		}
+
+		for fetches.Load() == 0 {
+			time.Sleep(time.Millisecond)
+		}
+
+		// Callers waiting for the fetch give up with their context.
+		canceled, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		if _, _, err := keys.Key(canceled, kid); !errors.Is(err, context.Canceled) {
+			t.Fatalf("returned %v; expected %v", err, context.Canceled)
+		}
+
+		close(release)
+
+		for range cap(errs) {
+			if err := <-errs; err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		if fetches.Load() != 1 {
+			t.Fatalf("fetched %d times; expected one fetch for concurrent callers", fetches.Load())
+		}
+	})
+
+	t.Run("failed fetches back off", func(t *testing.T) {
+		var fetches atomic.Int32
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			fetches.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer ts.Close()
+
+		keys, err := LoadKeySet(ts.URL)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		for range 3 {
+			if _, _, err := keys.Key(context.Background(), kid); err == nil {
+				t.Fatal("expected an error")
+			}
+		}
+
+		if fetches.Load() != 1 {
+			t.Fatalf("fetched %d times; expected no refetch right after a failure", fetches.Load())
+		}
+	})
+
+	t.Run("fetch outlives its caller", func(t *testing.T) {
+		release := make(chan struct{})
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			<-release
+			_, _ = w.Write(data)
+		}))
+		defer ts.Close()
+
+		keys, err := LoadKeySet(ts.URL)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		errs := make(chan error, 1)
+
+		go func() {
+			_, _, err := keys.Key(ctx, kid)
+			errs <- err
+		}()
+
+		cancel()
+
+		if err := <-errs; !errors.Is(err, context.Canceled) {
+			t.Fatalf("returned %v; expected %v", err, context.Canceled)
+		}
+
+		close(release)
+
+		if _, found, err := keys.Key(context.Background(), kid); !found || err != nil {
+			t.Fatalf("returned %v, %v; expected key %s", found, err, kid)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		if _, err := LoadKeySet(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
+
+func TestFindKey(t *testing.T) {
+	single := jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{KeyID: "a"}}}
+
+	if _, found, _ := findKey(&single, ""); !found {
+		t.Fatal("a token without kid should match the only key")
+	}
+
+	if _, found, _ := findKey(&single, "b"); found {
+		t.Fatal("an unknown kid shouldn't match")
+	}
+}
@@ -0,0 +1,183 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+)
+
+const (
+	jwksRefreshInterval = 15 * time.Minute
+
+	// jwksMinRefreshInterval throttles refetches for unknown key ids and after failed
+	// fetches, so forged tokens can't hammer the identity provider.
+	jwksMinRefreshInterval = time.Minute
+
+	jwksFetchTimeout = 10 * time.Second
+
+	maxJWKSSize = 1 << 20
+)
+
+type KeySet interface {
+	Key(ctx context.Context, kid string) (jose.JSONWebKey, bool, error)
+}
+
+func LoadKeySet(source string) (KeySet, error) {
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		return &remoteKeySet{
+			url:    source,
+			client: &http.Client{},
+		}, nil
+	}
+
+	data, err := os.ReadFile(source)
+	if err != nil {
+		return nil, fmt.Errorf("error reading jwks: %w", err)
+	}
+
+	var set jose.JSONWebKeySet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("error parsing jwks %s: %w", source, err)
+	}
+
+	return StaticKeySet(set), nil
+}
+
+type staticKeySet jose.JSONWebKeySet
+
+func StaticKeySet(set jose.JSONWebKeySet) KeySet {
+	return staticKeySet(set)
+}
+
+func (s staticKeySet) Key(_ context.Context, kid string) (jose.JSONWebKey, bool, error) {
+	set := jose.JSONWebKeySet(s)
+	return findKey(&set, kid)
+}
+
+type remoteKeySet struct {
+	url    string
+	client *http.Client
+
+	mu          sync.Mutex
+	set         *jose.JSONWebKeySet
+	fetchedAt   time.Time
+	attemptedAt time.Time
+	err         error
+	// fetching is closed when the fetch in progress, if any, is done. The lock isn't
+	// held while fetching, so requests with known keys aren't held up by it.
+	fetching chan struct{}
+}
+
+func (s *remoteKeySet) Key(ctx context.Context, kid string) (jose.JSONWebKey, bool, error) {
+	s.mu.Lock()
+
+	if s.set != nil {
+		since := time.Since(s.fetchedAt)
+
+		key, found, _ := findKey(s.set, kid)
+		if (found && since < jwksRefreshInterval) || (!found && since < jwksMinRefreshInterval) {
+			s.mu.Unlock()
+			return key, found, nil
+		}
+	}
+
+	if s.err != nil && time.Since(s.attemptedAt) < jwksMinRefreshInterval {
+		defer s.mu.Unlock()
+		return s.cachedKey(kid)
+	}
+
+	fetching := s.fetching
+	if fetching == nil {
+		fetching = make(chan struct{})
+		s.fetching = fetching
+
+		// The fetch is shared, so it must outlive the request that started it.
+		go s.refresh(context.WithoutCancel(ctx), fetching)
+	}
+
+	s.mu.Unlock()
+
+	select {
+	case <-fetching:
+	case <-ctx.Done():
+		return jose.JSONWebKey{}, false, ctx.Err()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.cachedKey(kid)
+}
+
+// cachedKey keeps using the previous keys while the provider is unavailable.
+func (s *remoteKeySet) cachedKey(kid string) (jose.JSONWebKey, bool, error) {
+	if s.set == nil {
+		return jose.JSONWebKey{}, false, s.err
+	}
+
+	return findKey(s.set, kid)
+}
+
+func (s *remoteKeySet) refresh(ctx context.Context, done chan struct{}) {
+	ctx, cancel := context.WithTimeout(ctx, jwksFetchTimeout)
+	defer cancel()
+
+	set, err := s.fetch(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.attemptedAt, s.err = time.Now(), err
+	if err == nil {
+		s.set, s.fetchedAt = set, s.attemptedAt
+	}
+
+	s.fetching = nil
+	close(done)
+}
+
+func (s *remoteKeySet) fetch(ctx context.Context) (*jose.JSONWebKeySet, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching jwks: %w", err)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching jwks: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error fetching jwks: %s returned %s", s.url, resp.Status)
+	}
+
+	var set jose.JSONWebKeySet
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxJWKSSize)).Decode(&set); err != nil {
+		return nil, fmt.Errorf("error parsing jwks from %s: %w", s.url, err)
+	}
+
+	return &set, nil
+}
+
+// Tokens without a key id match a set holding a single key.
+func findKey(set *jose.JSONWebKeySet, kid string) (jose.JSONWebKey, bool, error) {
+	if kid == "" && len(set.Keys) == 1 {
+		return set.Keys[0], true, nil
+	}
+
+	keys := set.Key(kid)
+	if len(keys) == 0 {
+		return jose.JSONWebKey{}, false, nil
+	}
+
+	return keys[0], true, nil
+}
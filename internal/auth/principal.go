@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"context"
+
+	"task-tracker/internal/models"
+)
+
+type Principal struct {
+	Subject string
+	Name    string
+	Roles   []string
+	Scopes  []models.Scope
+	KeyID   string
+}
+
+func (p Principal) HasScope(scope models.Scope) bool {
+	return models.GrantsScope(p.Scopes, scope)
+}
+
+func (p Principal) Role() models.Role {
+	return models.HighestRole(p.Roles)
+}
+
+type principalKey struct{}
+
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
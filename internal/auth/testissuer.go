@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+
+	"task-tracker/internal/clock"
+	"task-tracker/internal/models"
+)
+
+const (
+	TestIssuerJWKSPath  = "/.well-known/jwks.json"
+	TestIssuerTokenPath = "/auth/test/token"
+
+	defaultTestTokenTTL = time.Hour
+)
+
+// TestIssuer signs tokens for any subject, so it must never be enabled in production.
+type TestIssuer struct {
+	issuer   string
+	audience string
+	key      jose.JSONWebKey
+	signer   jose.Signer
+	clock    clock.Clock
+}
+
+func NewTestIssuer(issuer, audience string, clock clock.Clock) (*TestIssuer, error) {
+	private, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("error generating test issuer key: %w", err)
+	}
+
+	kid, err := randomString(8, func(b []byte) string { return fmt.Sprintf("test-%x", b) })
+	if err != nil {
+		return nil, err
+	}
+
+	key := jose.JSONWebKey{Key: private, KeyID: kid, Algorithm: string(jose.ES256), Use: "sig"}
+
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.ES256, Key: key},
+		(&jose.SignerOptions{}).WithType("JWT"))
+	if err != nil {
+		return nil, fmt.Errorf("error creating test issuer signer: %w", err)
+	}
+
+	return &TestIssuer{
+		issuer:   issuer,
+		audience: audience,
+		key:      key,
+		signer:   signer,
+		clock:    clock,
+	}, nil
+}
+
+func (i *TestIssuer) KeySet() jose.JSONWebKeySet {
+	return jose.JSONWebKeySet{Keys: []jose.JSONWebKey{i.key.Public()}}
+}
+
+type TestTokenRequest struct {
+	Subject string   `json:"sub"`
+	Name    string   `json:"name,omitempty"`
+	Roles   []string `json:"roles,omitempty"`
+	Scope   string   `json:"scope,omitempty"`
+	// TTL is a Go duration such as "15m", one hour if empty.
+	TTL string `json:"ttl,omitempty"`
+}
+
+type TestTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+func (i *TestIssuer) Issue(request TestTokenRequest) (string, time.Duration, error) {
+	if request.Subject == "" {
+		return "", 0, models.NewError("sub field is empty", http.StatusBadRequest)
+	}
+
+	ttl := defaultTestTokenTTL
+
+	if request.TTL != "" {
+		var err error
+		if ttl, err = time.ParseDuration(request.TTL); err != nil || ttl <= 0 {
+			return "", 0, models.NewError("ttl must be a positive duration", http.StatusBadRequest)
+		}
+	}
+
+	now := i.clock.Now()
+
+	claims := jwt.Claims{
+		Issuer:    i.issuer,
+		Subject:   request.Subject,
+		Audience:  jwt.Audience{i.audience},
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+		Expiry:    jwt.NewNumericDate(now.Add(ttl)),
+	}
+
+	extra := map[string]any{}
+
+	if request.Name != "" {
+		extra["name"] = request.Name
+	}
+
+	if request.Roles != nil {
+		extra["roles"] = request.Roles
+	}
+
+	if request.Scope != "" {
+		extra["scope"] = request.Scope
+	}
+
+	token, err := jwt.Signed(i.signer).Claims(claims).Claims(extra).Serialize()
+	if err != nil {
+		return "", 0, fmt.Errorf("error signing test token: %w", err)
+	}
+
+	return token, ttl, nil
+}
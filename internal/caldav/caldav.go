@@ -0,0 +1,558 @@
+package caldav
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"task-tracker/internal/auth"
+	"task-tracker/internal/ical"
+	"task-tracker/internal/models"
+	"task-tracker/internal/service"
+	"task-tracker/internal/tenant"
+)
+
+// HomePath is both the principal and the calendar home of every caller, holding one
+// task collection per workspace they can enter.
+const HomePath = "/dav/"
+
+const (
+	collectionName = "tasks"
+	objectSuffix   = ".ics"
+	maxObjectName  = 255
+
+	davHeader   = "1, 3, calendar-access"
+	allowHeader = "OPTIONS, GET, HEAD, PUT, DELETE, PROPFIND, REPORT"
+
+	calendarContentType = "text/calendar; charset=utf-8"
+	objectContentType   = calendarContentType + "; component=VTODO"
+)
+
+func CollectionPath(workspace string) string {
+	if workspace == tenant.DefaultWorkspace {
+		return HomePath + collectionName + "/"
+	}
+
+	return HomePath + "workspaces/" + workspace + "/" + collectionName + "/"
+}
+
+type Workspaces interface {
+	List(ctx context.Context) ([]models.Workspace, error)
+	Get(ctx context.Context) (models.Workspace, error)
+}
+
+// Handler implements the subset of RFC 4918 and RFC 4791 to-do apps use: PROPFIND
+// discovery, calendar-query and calendar-multiget reports, and GET, PUT and DELETE of
+// single tasks. Collections and objects expect the workspace to be entered already.
+type Handler struct {
+	tasks       service.TaskService
+	workspaces  Workspaces
+	handleError func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+func NewHandler(tasks service.TaskService, workspaces Workspaces, handleError func(http.ResponseWriter, *http.Request, error)) *Handler {
+	return &Handler{
+		tasks:       tasks,
+		workspaces:  workspaces,
+		handleError: handleError,
+	}
+}
+
+func (h *Handler) ServeHome(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodOptions:
+		h.options(w)
+	case "PROPFIND":
+		var request propfindRequest
+		if err := decodeBody(r.Body, &request); err != nil {
+			h.handleError(w, r, models.ErrBadRequest)
+			return
+		}
+
+		responses := []response{selectProps(HomePath, h.homeProps(r.Context()), request.Prop)}
+
+		if r.Header.Get("Depth") != "0" {
+			workspaces, err := h.workspaces.List(r.Context())
+			if err != nil {
+				h.handleError(w, r, err)
+				return
+			}
+
+			for _, workspace := range workspaces {
+				href := CollectionPath(workspace.ID)
+				responses = append(responses, selectProps(href, collectionProps(workspace), request.Prop))
+			}
+		}
+
+		writeMultistatus(w, responses)
+	default:
+		h.handleError(w, r, models.ErrMethodNotAllowed)
+	}
+}
+
+func (h *Handler) ServeCollection(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodOptions:
+		h.options(w)
+	case "PROPFIND":
+		h.propfindCollection(w, r)
+	case "REPORT":
+		h.report(w, r)
+	default:
+		h.handleError(w, r, models.ErrMethodNotAllowed)
+	}
+}
+
+func (h *Handler) ServeObject(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+
+	if !strings.HasSuffix(name, objectSuffix) || len(name) > maxObjectName {
+		h.handleError(w, r, models.ErrInvalidCalendarName)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodOptions:
+		h.options(w)
+	case http.MethodGet, http.MethodHead:
+		h.get(w, r, name)
+	case http.MethodPut:
+		h.put(w, r, name)
+	case http.MethodDelete:
+		h.delete(w, r, name)
+	case "PROPFIND":
+		h.propfindObject(w, r, name)
+	default:
+		h.handleError(w, r, models.ErrMethodNotAllowed)
+	}
+}
+
+func (h *Handler) options(w http.ResponseWriter) {
+	w.Header().Set("DAV", davHeader)
+	w.Header().Set("Allow", allowHeader)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) propfindCollection(w http.ResponseWriter, r *http.Request) {
+	var request propfindRequest
+	if err := decodeBody(r.Body, &request); err != nil {
+		h.handleError(w, r, models.ErrBadRequest)
+		return
+	}
+
+	workspace, err := h.workspaces.Get(r.Context())
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	tasks, err := h.tasks.GetAll(r.Context())
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	collection := CollectionPath(workspace.ID)
+	props := append(collectionProps(workspace), prop{name: propGetCTag, value: text(ctag(tasks))})
+	responses := []response{selectProps(collection, props, request.Prop)}
+
+	if r.Header.Get("Depth") != "0" {
+		for _, task := range tasks {
+			responses = append(responses, selectProps(collection+objectName(task), objectProps(task), request.Prop))
+		}
+	}
+
+	writeMultistatus(w, responses)
+}
+
+func (h *Handler) propfindObject(w http.ResponseWriter, r *http.Request, name string) {
+	var request propfindRequest
+	if err := decodeBody(r.Body, &request); err != nil {
+		h.handleError(w, r, models.ErrBadRequest)
+		return
+	}
+
+	task, err := h.find(r.Context(), name)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	href := CollectionPath(tenant.Workspace(r.Context())) + name
+
+	writeMultistatus(w, []response{selectProps(href, objectProps(task), request.Prop)})
+}
+
+func (h *Handler) report(w http.ResponseWriter, r *http.Request) {
+	var request reportRequest
+	if err := decodeBody(r.Body, &request); err != nil || request.XMLName.Space != nsCalDAV {
+		h.handleError(w, r, models.ErrBadRequest)
+		return
+	}
+
+	collection := CollectionPath(tenant.Workspace(r.Context()))
+
+	var responses []response
+
+	switch request.XMLName.Local {
+	case "calendar-query":
+		if !request.Filter.matchesTodos() {
+			break
+		}
+
+		tasks, err := h.tasks.GetAll(r.Context())
+		if err != nil {
+			h.handleError(w, r, err)
+			return
+		}
+
+		for _, task := range tasks {
+			responses = append(responses, selectProps(collection+objectName(task), objectProps(task), request.Prop))
+		}
+	case "calendar-multiget":
+		for _, href := range request.Hrefs {
+			path := hrefPath(href)
+			res := response{href: path, status: http.StatusNotFound}
+
+			if name, ok := strings.CutPrefix(path, collection); ok {
+				task, err := h.find(r.Context(), name)
+
+				switch {
+				case err == nil:
+					res = selectProps(path, objectProps(task), request.Prop)
+				case !errors.Is(err, models.ErrTaskNotFound):
+					h.handleError(w, r, err)
+					return
+				}
+			}
+
+			responses = append(responses, res)
+		}
+	default:
+		h.handleError(w, r, models.ErrBadRequest)
+		return
+	}
+
+	writeMultistatus(w, responses)
+}
+
+func (h *Handler) get(w http.ResponseWriter, r *http.Request, name string) {
+	task, err := h.find(r.Context(), name)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	data, err := encode(task)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", calendarContentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.Header().Set("ETag", etag(task))
+	w.Header().Set("Last-Modified", task.UpdatedAt.UTC().Format(http.TimeFormat))
+	w.WriteHeader(http.StatusOK)
+
+	if r.Method != http.MethodHead {
+		_, _ = w.Write(data)
+	}
+}
+
+// A missing DUE clears the due date, while labels are only cleared by an empty
+// CATEGORIES. A VTODO without a DESCRIPTION gets its title as the description.
+func (h *Handler) put(w http.ResponseWriter, r *http.Request, name string) {
+	ctx := r.Context()
+
+	t, err := parseTodo(r.Body)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	existing, err := h.find(ctx, name)
+	found := err == nil
+
+	if err != nil && !errors.Is(err, models.ErrTaskNotFound) {
+		h.handleError(w, r, err)
+		return
+	}
+
+	if !preconditionsMet(r, existing, found) {
+		h.handleError(w, r, models.ErrPreconditionFailed)
+		return
+	}
+
+	if err := h.checkUID(ctx, t.uid, existing, found); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	description := t.description
+	if strings.TrimSpace(description) == "" {
+		description = t.title
+	}
+
+	if !found {
+		request := models.CreateTaskRequest{
+			Title:       t.title,
+			Description: description,
+			Status:      t.taskStatus(""),
+			DueAt:       t.due,
+			Labels:      t.labels,
+		}
+
+		if err := request.Validate(); err != nil {
+			h.handleError(w, r, err)
+			return
+		}
+
+		task := request.ConvertToTask()
+		task.CalendarUID = t.uid
+		task.CalendarName = name
+
+		if err := h.tasks.Add(ctx, task); err != nil {
+			h.handleError(w, r, err)
+			return
+		}
+
+		w.Header().Set("ETag", etag(*task))
+		w.WriteHeader(http.StatusCreated)
+
+		return
+	}
+
+	request := models.UpdateTaskRequest{
+		Title:       t.title,
+		Description: description,
+		Status:      t.taskStatus(existing.Status),
+		Labels:      t.labels,
+	}
+
+	if err := request.Validate(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	updated := request.ConvertToTask(existing.ID)
+	updated.DueAt = t.due
+	updated.ClearDueAt = t.due == nil
+
+	if err := h.tasks.Update(ctx, updated); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	task, err := h.tasks.Get(ctx, existing.ID)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("ETag", etag(task))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) checkUID(ctx context.Context, uid string, existing models.Task, found bool) error {
+	other := func(task models.Task) bool {
+		return !found || task.ID != existing.ID
+	}
+
+	tasks, err := h.tasks.FindByCalendar(ctx, "", uid)
+	if err != nil {
+		return err
+	}
+
+	if slices.ContainsFunc(tasks, other) {
+		return models.ErrCalendarUIDExists
+	}
+
+	id, _, _ := strings.Cut(uid, "@")
+	if uuid.Validate(id) != nil || ical.UID(models.Task{ID: id}) != uid {
+		return nil
+	}
+
+	task, err := h.tasks.Get(ctx, id)
+	if errors.Is(err, models.ErrTaskNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if task.CalendarUID == "" && other(task) {
+		return models.ErrCalendarUIDExists
+	}
+
+	return nil
+}
+
+func (h *Handler) delete(w http.ResponseWriter, r *http.Request, name string) {
+	task, err := h.find(r.Context(), name)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	if !preconditionsMet(r, task, true) {
+		h.handleError(w, r, models.ErrPreconditionFailed)
+		return
+	}
+
+	if err := h.tasks.Delete(r.Context(), task.ID); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) find(ctx context.Context, name string) (models.Task, error) {
+	tasks, err := h.tasks.FindByCalendar(ctx, name, "")
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	if len(tasks) > 0 {
+		return tasks[0], nil
+	}
+
+	id := strings.TrimSuffix(name, objectSuffix)
+	if uuid.Validate(id) != nil {
+		return models.Task{}, models.ErrTaskNotFound
+	}
+
+	task, err := h.tasks.Get(ctx, id)
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	if objectName(task) != name {
+		return models.Task{}, models.ErrTaskNotFound
+	}
+
+	return task, nil
+}
+
+func (h *Handler) homeProps(ctx context.Context) []prop {
+	name := "task-tracker"
+	if principal, ok := auth.PrincipalFromContext(ctx); ok {
+		name = principal.Subject
+	}
+
+	return []prop{
+		{name: propResourceType, value: "<d:collection/><d:principal/>"},
+		{name: propDisplayName, value: text(name)},
+		{name: propCurrentUserPrincipal, value: hrefValue(HomePath)},
+		{name: propPrincipalURL, value: hrefValue(HomePath)},
+		{name: propCalendarHomeSet, value: hrefValue(HomePath)},
+	}
+}
+
+func collectionProps(workspace models.Workspace) []prop {
+	return []prop{
+		{name: propResourceType, value: "<d:collection/><c:calendar/>"},
+		{name: propDisplayName, value: text(workspace.Name)},
+		{name: propCurrentUserPrincipal, value: hrefValue(HomePath)},
+		{name: propSupportedComponentSet, value: `<c:comp name="VTODO"/>`},
+		{name: propSupportedReportSet, value: "<d:supported-report><d:report><c:calendar-query/></d:report></d:supported-report>" +
+			"<d:supported-report><d:report><c:calendar-multiget/></d:report></d:supported-report>"},
+	}
+}
+
+func objectProps(task models.Task) []prop {
+	props := []prop{
+		{name: propResourceType},
+		{name: propGetETag, value: text(etag(task))},
+		{name: propGetContentType, value: text(objectContentType)},
+		{name: propGetLastModified, value: text(task.UpdatedAt.UTC().Format(http.TimeFormat))},
+	}
+
+	if data, err := encode(task); err == nil {
+		props = append(props, prop{name: propCalendarData, value: text(string(data)), explicit: true})
+	}
+
+	return props
+}
+
+func encode(task models.Task) ([]byte, error) {
+	var b bytes.Buffer
+
+	if err := ical.Encode(&b, ical.Calendar{Component: ical.ComponentTodo, Tasks: []models.Task{task}}); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+func objectName(task models.Task) string {
+	if task.CalendarName != "" {
+		return task.CalendarName
+	}
+
+	return task.ID + objectSuffix
+}
+
+// Clients can send an href as a path or as an absolute URL.
+func hrefPath(href string) string {
+	u, err := url.Parse(href)
+	if err != nil {
+		return href
+	}
+
+	return u.Path
+}
+
+// etag changes whenever the task is updated. Microseconds are what Postgres keeps.
+func etag(task models.Task) string {
+	return `"` + strconv.FormatInt(task.UpdatedAt.UnixMicro(), 36) + `"`
+}
+
+func ctag(tasks []models.Task) string {
+	hash := sha256.New()
+
+	for _, task := range tasks {
+		fmt.Fprintf(hash, "%s %s\n", objectName(task), etag(task))
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)[:16])
+}
+
+func preconditionsMet(r *http.Request, task models.Task, found bool) bool {
+	if match := r.Header.Get("If-Match"); match != "" {
+		if !found || !matchesETag(match, etag(task)) {
+			return false
+		}
+	}
+
+	if noneMatch := r.Header.Get("If-None-Match"); noneMatch != "" {
+		if found && matchesETag(noneMatch, etag(task)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func matchesETag(header, current string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == current {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,126 @@
+package caldav
+
+import (
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"task-tracker/internal/ical"
+	"task-tracker/internal/models"
+)
+
+const statusInProgress = "in_progress"
+
+// Everything else a client stores in a VTODO, such as alarms or priorities, is dropped.
+type todo struct {
+	uid         string
+	title       string
+	description string
+	status      string
+	due         *time.Time
+	labels      []string
+}
+
+// Overrides of recurrence instances are ignored, as tasks recur through the tracker's
+// own rules.
+func parseTodo(r io.Reader) (todo, error) {
+	calendar, err := ical.Parse(r)
+	if err != nil || calendar.Name != "VCALENDAR" {
+		return todo{}, models.ErrInvalidCalendarData
+	}
+
+	var components []ical.Component
+
+	for _, component := range calendar.Children(ical.ComponentTodo) {
+		if _, override := component.Property("RECURRENCE-ID"); !override {
+			components = append(components, component)
+		}
+	}
+
+	if len(components) != 1 {
+		return todo{}, models.ErrInvalidCalendarData
+	}
+
+	component := components[0]
+
+	var t todo
+
+	if uid, ok := component.Property("UID"); ok {
+		t.uid = strings.TrimSpace(uid.Text())
+	}
+
+	if t.uid == "" {
+		return todo{}, models.ErrInvalidCalendarData
+	}
+
+	if summary, ok := component.Property("SUMMARY"); ok {
+		t.title = strings.TrimSpace(summary.Text())
+	}
+
+	if t.title == "" {
+		return todo{}, models.ErrTitleIsEmpty
+	}
+
+	if description, ok := component.Property("DESCRIPTION"); ok {
+		t.description = description.Text()
+	}
+
+	t.status = "NEEDS-ACTION"
+
+	if status, ok := component.Property("STATUS"); ok {
+		t.status = strings.ToUpper(status.Value)
+	} else if _, completed := component.Property("COMPLETED"); completed {
+		t.status = "COMPLETED"
+	}
+
+	if due, ok := component.Property("DUE"); ok {
+		at, err := due.Time()
+		if err != nil {
+			return todo{}, models.ErrInvalidCalendarData
+		}
+
+		at = at.UTC()
+		t.due = &at
+	}
+
+	// Without CATEGORIES the labels stay nil, which keeps them on update.
+	for _, categories := range component.All("CATEGORIES") {
+		if t.labels == nil {
+			t.labels = []string{}
+		}
+
+		for _, label := range categories.List() {
+			label = strings.TrimSpace(label)
+
+			if label != "" && !slices.Contains(t.labels, label) {
+				t.labels = append(t.labels, label)
+			}
+		}
+	}
+
+	if err := models.ValidateLabels(t.labels); err != nil {
+		return todo{}, err
+	}
+
+	return t, nil
+}
+
+// A task keeps its current status if it maps to the same VTODO status, so statuses
+// without a counterpart survive a round trip through the client.
+func (t todo) taskStatus(current string) string {
+	if current != "" && ical.TodoStatus(current) == t.status {
+		return current
+	}
+
+	switch t.status {
+	case "COMPLETED":
+		return models.StatusDone
+	case "CANCELLED":
+		return models.StatusCanceled
+	case "IN-PROCESS":
+		return statusInProgress
+	default:
+		return models.StatusTodo
+	}
+}
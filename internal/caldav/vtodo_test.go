@@ -0,0 +1,95 @@
+package caldav
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"task-tracker/internal/models"
+)
+
+func TestParseTodo(t *testing.T) {
+	calendar := func(lines ...string) string {
+		return "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" + strings.Join(lines, "\r\n") + "\r\nEND:VCALENDAR\r\n"
+	}
+
+	due := time.Date(2025, time.April, 14, 0, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		data     string
+		expected todo
+		err      error
+	}{
+		"minimal": {
+			data:     calendar("BEGIN:VTODO", "UID:a", "SUMMARY:a", "END:VTODO"),
+			expected: todo{uid: "a", title: "a", status: "NEEDS-ACTION"},
+		},
+		"completed without status": {
+			data:     calendar("BEGIN:VTODO", "UID:a", "SUMMARY:a", "COMPLETED:20250411T163000Z", "DUE;VALUE=DATE:20250414", "END:VTODO"),
+			expected: todo{uid: "a", title: "a", status: "COMPLETED", due: &due},
+		},
+		"categories are trimmed and deduplicated": {
+			data:     calendar("BEGIN:VTODO", "UID:a", "SUMMARY:a", "CATEGORIES:ops, dev", "CATEGORIES:ops,,", "END:VTODO"),
+			expected: todo{uid: "a", title: "a", status: "NEEDS-ACTION", labels: []string{"ops", "dev"}},
+		},
+		"empty categories": {
+			data:     calendar("BEGIN:VTODO", "UID:a", "SUMMARY:a", "CATEGORIES:", "END:VTODO"),
+			expected: todo{uid: "a", title: "a", status: "NEEDS-ACTION", labels: []string{}},
+		},
+		"recurrence overrides are ignored": {
+			data: calendar("BEGIN:VTODO", "UID:a", "SUMMARY:a", "END:VTODO",
+				"BEGIN:VTODO", "UID:a", "SUMMARY:b", "RECURRENCE-ID:20250414T070000Z", "END:VTODO"),
+			expected: todo{uid: "a", title: "a", status: "NEEDS-ACTION"},
+		},
+		"missing uid":      {data: calendar("BEGIN:VTODO", "SUMMARY:a", "END:VTODO"), err: models.ErrInvalidCalendarData},
+		"missing summary":  {data: calendar("BEGIN:VTODO", "UID:a", "END:VTODO"), err: models.ErrTitleIsEmpty},
+		"two todos":        {data: calendar("BEGIN:VTODO", "UID:a", "END:VTODO", "BEGIN:VTODO", "UID:b", "END:VTODO"), err: models.ErrInvalidCalendarData},
+		"event":            {data: calendar("BEGIN:VEVENT", "UID:a", "SUMMARY:a", "END:VEVENT"), err: models.ErrInvalidCalendarData},
+		"invalid due":      {data: calendar("BEGIN:VTODO", "UID:a", "SUMMARY:a", "DUE:soon", "END:VTODO"), err: models.ErrInvalidCalendarData},
+		"label with comma": {data: calendar("BEGIN:VTODO", "UID:a", "SUMMARY:a", `CATEGORIES:a\,b`, "END:VTODO"), err: models.ErrInvalidLabel},
+		"not a calendar":   {data: "BEGIN:VCARD\r\nEND:VCARD\r\n", err: models.ErrInvalidCalendarData},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			returned, err := parseTodo(strings.NewReader(test.data))
+			if !errors.Is(err, test.err) {
+				t.Fatalf("test-case: (%q); returned %v; expected %v", name, err, test.err)
+			}
+
+			if test.err == nil && !reflect.DeepEqual(returned, test.expected) {
+				t.Fatalf("test-case: (%q); returned %+v; expected %+v", name, returned, test.expected)
+			}
+		})
+	}
+}
+
+func TestTodo_TaskStatus(t *testing.T) {
+	tests := map[string]struct {
+		status   string
+		current  string
+		expected string
+	}{
+		"new needs action":           {"NEEDS-ACTION", "", models.StatusTodo},
+		"new in process":             {"IN-PROCESS", "", statusInProgress},
+		"completed":                  {"COMPLETED", "in_review", models.StatusDone},
+		"cancelled":                  {"CANCELLED", models.StatusTodo, models.StatusCanceled},
+		"skipped stays skipped":      {"CANCELLED", models.StatusSkipped, models.StatusSkipped},
+		"custom status stays":        {"IN-PROCESS", "in_review", "in_review"},
+		"unknown status is reopened": {"X-WAITING", models.StatusDone, models.StatusTodo},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if returned := (todo{status: test.status}).taskStatus(test.current); returned != test.expected {
+				t.Fatalf("test-case: (%q); returned %v; expected %v", name, returned, test.expected)
+			}
+		})
+	}
+}
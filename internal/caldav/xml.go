@@ -0,0 +1,251 @@
+package caldav
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// XML namespaces, written with the prefixes of the multistatus root element.
+const (
+	nsDAV          = "DAV:"
+	nsCalDAV       = "urn:ietf:params:xml:ns:caldav"
+	nsCalendarServ = "http://calendarserver.org/ns/"
+)
+
+var prefixes = map[string]string{
+	nsDAV:          "d",
+	nsCalDAV:       "c",
+	nsCalendarServ: "cs",
+}
+
+// Properties the handler knows.
+var (
+	propResourceType          = xml.Name{Space: nsDAV, Local: "resourcetype"}
+	propDisplayName           = xml.Name{Space: nsDAV, Local: "displayname"}
+	propCurrentUserPrincipal  = xml.Name{Space: nsDAV, Local: "current-user-principal"}
+	propPrincipalURL          = xml.Name{Space: nsDAV, Local: "principal-URL"}
+	propSupportedReportSet    = xml.Name{Space: nsDAV, Local: "supported-report-set"}
+	propGetETag               = xml.Name{Space: nsDAV, Local: "getetag"}
+	propGetContentType        = xml.Name{Space: nsDAV, Local: "getcontenttype"}
+	propGetLastModified       = xml.Name{Space: nsDAV, Local: "getlastmodified"}
+	propCalendarHomeSet       = xml.Name{Space: nsCalDAV, Local: "calendar-home-set"}
+	propSupportedComponentSet = xml.Name{Space: nsCalDAV, Local: "supported-calendar-component-set"}
+	propCalendarData          = xml.Name{Space: nsCalDAV, Local: "calendar-data"}
+	propGetCTag               = xml.Name{Space: nsCalendarServ, Local: "getctag"}
+)
+
+// Explicit properties are left out of allprop responses, like calendar-data, which
+// RFC 4791 excludes.
+type prop struct {
+	name     xml.Name
+	value    string
+	explicit bool
+}
+
+type response struct {
+	href    string
+	found   []prop
+	missing []xml.Name
+	status  int
+}
+
+type propNames []xml.Name
+
+func (p *propNames) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			*p = append(*p, t.Name)
+
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+type propfindRequest struct {
+	XMLName xml.Name   `xml:"DAV: propfind"`
+	Prop    *propNames `xml:"DAV: prop"`
+}
+
+type reportRequest struct {
+	XMLName xml.Name
+	Prop    *propNames   `xml:"DAV: prop"`
+	Hrefs   []string     `xml:"DAV: href"`
+	Filter  *queryFilter `xml:"urn:ietf:params:xml:ns:caldav filter"`
+}
+
+type queryFilter struct {
+	CompFilter compFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+}
+
+type compFilter struct {
+	Name    string       `xml:"name,attr"`
+	Filters []compFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+}
+
+// Filters on VTODO properties aren't evaluated, so a query can return more than it
+// asked for.
+func (f *queryFilter) matchesTodos() bool {
+	if f == nil {
+		return true
+	}
+
+	if f.CompFilter.Name != "VCALENDAR" {
+		return false
+	}
+
+	for _, filter := range f.CompFilter.Filters {
+		if filter.Name != "VTODO" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// An empty body leaves v as it is.
+func decodeBody(body io.Reader, v any) error {
+	err := xml.NewDecoder(body).Decode(v)
+	if err == io.EOF {
+		return nil
+	}
+
+	return err
+}
+
+// A nil request selects all non-explicit properties.
+func selectProps(href string, available []prop, requested *propNames) response {
+	res := response{href: href}
+
+	if requested == nil {
+		for _, p := range available {
+			if !p.explicit {
+				res.found = append(res.found, p)
+			}
+		}
+
+		return res
+	}
+
+	for _, name := range *requested {
+		found := false
+
+		for _, p := range available {
+			if p.name == name {
+				res.found = append(res.found, p)
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			res.missing = append(res.missing, name)
+		}
+	}
+
+	return res
+}
+
+func writeMultistatus(w http.ResponseWriter, responses []response) {
+	var b bytes.Buffer
+
+	b.WriteString(xml.Header)
+	fmt.Fprintf(&b, `<d:multistatus xmlns:d="%s" xmlns:c="%s" xmlns:cs="%s">`, nsDAV, nsCalDAV, nsCalendarServ)
+
+	for _, res := range responses {
+		b.WriteString("<d:response><d:href>")
+		writeText(&b, (&url.URL{Path: res.href}).EscapedPath())
+		b.WriteString("</d:href>")
+
+		if res.status != 0 {
+			writeStatus(&b, res.status)
+		}
+
+		if len(res.found) > 0 {
+			b.WriteString("<d:propstat><d:prop>")
+
+			for _, p := range res.found {
+				writeElement(&b, p.name, p.value)
+			}
+
+			b.WriteString("</d:prop>")
+			writeStatus(&b, http.StatusOK)
+			b.WriteString("</d:propstat>")
+		}
+
+		if len(res.missing) > 0 {
+			b.WriteString("<d:propstat><d:prop>")
+
+			for _, name := range res.missing {
+				writeElement(&b, name, "")
+			}
+
+			b.WriteString("</d:prop>")
+			writeStatus(&b, http.StatusNotFound)
+			b.WriteString("</d:propstat>")
+		}
+
+		b.WriteString("</d:response>")
+	}
+
+	b.WriteString("</d:multistatus>")
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.Header().Set("Content-Length", strconv.Itoa(b.Len()))
+	w.WriteHeader(http.StatusMultiStatus)
+	_, _ = w.Write(b.Bytes())
+}
+
+// writeElement writes an element with inner XML, declaring namespaces the root
+// element doesn't on the element itself.
+func writeElement(b *bytes.Buffer, name xml.Name, inner string) {
+	tag, declaration := name.Local, ""
+
+	if prefix, ok := prefixes[name.Space]; ok {
+		tag = prefix + ":" + name.Local
+	} else {
+		declaration = ` xmlns="` + text(name.Space) + `"`
+	}
+
+	if inner == "" {
+		fmt.Fprintf(b, "<%s%s/>", tag, declaration)
+		return
+	}
+
+	fmt.Fprintf(b, "<%s%s>%s</%s>", tag, declaration, inner, tag)
+}
+
+func writeStatus(b *bytes.Buffer, status int) {
+	fmt.Fprintf(b, "<d:status>HTTP/1.1 %d %s</d:status>", status, http.StatusText(status))
+}
+
+func writeText(b *bytes.Buffer, text string) {
+	_ = xml.EscapeText(b, []byte(text))
+}
+
+func text(s string) string {
+	var b bytes.Buffer
+
+	writeText(&b, s)
+
+	return b.String()
+}
+
+func hrefValue(path string) string {
+	return "<d:href>" + text((&url.URL{Path: path}).EscapedPath()) + "</d:href>"
+}
@@ -0,0 +1,32 @@
+package clock
+
+import (
+	"time"
+)
+
+// Clock implementations return times in UTC.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func New() Clock {
+	return systemClock{}
+}
+
+func (systemClock) Now() time.Time {
+	return time.Now().UTC()
+}
+
+type fixedClock struct {
+	now time.Time
+}
+
+func NewFixed(now time.Time) Clock {
+	return fixedClock{now: now.UTC()}
+}
+
+func (c fixedClock) Now() time.Time {
+	return c.now
+}
@@ -1,15 +1,285 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
+	"net"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
 )
 
+const redacted = "REDACTED"
+
 type Config struct {
-	ServerPort string
-	DBConn     string
-	InMemory   string
+	ServerPort      int
+	DBConn          string
+	InMemory        bool
+	AutoMigrate     bool
+	LogLevel        string
+	ShutdownTimeout time.Duration
+	ShutdownDelay   time.Duration
+
+	TracingExporter string
+	OTLPEndpoint    string
+
+	CacheEnabled bool
+	CacheSize    int
+	CacheTTL     time.Duration
+
+	AuthEnabled   bool
+	JWKSSource    string
+	JWTIssuer     string
+	JWTAudience   string
+	JWTRolesClaim string
+	TestIssuer    bool
+
+	RateLimitEnabled bool
+	RateLimitBackend string
+	RateLimitReads   int
+	RateLimitWrites  int
+	TrustedProxies   string
+
+	SchedulerInterval time.Duration
+
+	Notifier      string
+	SMTPAddr      string
+	SMTPFrom      string
+	SMTPUsername  string
+	SMTPPassword  string
+	SMTPDomain    string
+	WebhookURL    string
+	WebhookSecret string
+	DigestEnabled bool
+	DigestHour    int
+}
+
+func Default() Config {
+	return Config{
+		ServerPort:      8080,
+		DBConn:          "user=postgres password=secret host=localhost port=5432 dbname=tasktracker",
+		LogLevel:        "info",
+		ShutdownTimeout: 10 * time.Second,
+		TracingExporter: "none",
+		CacheSize:       1000,
+		CacheTTL:        30 * time.Second,
+		JWTRolesClaim:   "roles",
+
+		RateLimitBackend: "memory",
+		RateLimitReads:   600,
+		RateLimitWrites:  120,
+
+		SchedulerInterval: time.Minute,
+
+		Notifier:   "log",
+		DigestHour: 8,
+	}
+}
+
+func (c *Config) Validate() error {
+	var errs []error
+
+	if c.ServerPort < 0 || c.ServerPort > 65535 {
+		errs = append(errs, fmt.Errorf("port: %d is out of range 0-65535", c.ServerPort))
+	}
+
+	if !c.InMemory && c.DBConn == "" {
+		errs = append(errs, errors.New("db_conn: required unless in_memory is set"))
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.LogLevel)); err != nil {
+		errs = append(errs, fmt.Errorf("log_level: %q is not one of debug, info, warn, error", c.LogLevel))
+	}
+
+	if c.ShutdownTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("shutdown_timeout: must be positive, got %s", c.ShutdownTimeout))
+	}
+
+	if c.ShutdownDelay < 0 {
+		errs = append(errs, fmt.Errorf("shutdown_delay: must not be negative, got %s", c.ShutdownDelay))
+	}
+
+	if c.TracingExporter != "none" && c.TracingExporter != "otlp" {
+		errs = append(errs, fmt.Errorf("tracing_exporter: %q is not one of none, otlp", c.TracingExporter))
+	}
+
+	if c.OTLPEndpoint != "" {
+		if u, err := url.Parse(c.OTLPEndpoint); err != nil || u.Scheme == "" || u.Host == "" {
+			errs = append(errs, fmt.Errorf("otlp_endpoint: %q is not an absolute URL", c.OTLPEndpoint))
+		}
+	}
+
+	if c.CacheEnabled && c.CacheSize <= 0 {
+		errs = append(errs, fmt.Errorf("cache_size: must be positive, got %d", c.CacheSize))
+	}
+
+	if c.CacheEnabled && c.CacheTTL <= 0 {
+		errs = append(errs, fmt.Errorf("cache_ttl: must be positive, got %s", c.CacheTTL))
+	}
+
+	if c.AuthEnabled && c.InMemory && c.JWKSSource == "" && !c.TestIssuer {
+		errs = append(errs, errors.New("auth_enabled: API keys are stored in Postgres, in-memory mode needs jwks_source or test_issuer"))
+	}
+
+	if c.JWKSSource != "" && c.TestIssuer {
+		errs = append(errs, errors.New("test_issuer: can't be combined with jwks_source"))
+	}
+
+	// Identity providers share keys between clients, so tokens must be issued for this API.
+	if c.JWKSSource != "" && (c.JWTIssuer == "" || c.JWTAudience == "") {
+		errs = append(errs, errors.New("jwt_issuer, jwt_audience: required by jwks_source"))
+	}
+
+	if (c.JWKSSource != "" || c.TestIssuer) && !c.AuthEnabled {
+		errs = append(errs, errors.New("auth_enabled: required by jwks_source and test_issuer"))
+	}
+
+	if c.SchedulerInterval <= 0 {
+		errs = append(errs, fmt.Errorf("scheduler_interval: must be positive, got %s", c.SchedulerInterval))
+	}
+
+	if c.RateLimitEnabled {
+		errs = append(errs, c.validateRateLimit()...)
+	}
+
+	if _, err := ParseTrustedProxies(c.TrustedProxies); err != nil {
+		errs = append(errs, fmt.Errorf("trusted_proxies: %w", err))
+	}
+
+	errs = append(errs, c.validateNotifier()...)
+
+	return errors.Join(errs...)
+}
+
+func (c *Config) validateRateLimit() []error {
+	var errs []error
+
+	switch c.RateLimitBackend {
+	case "memory":
+	case "postgres":
+		if c.InMemory {
+			errs = append(errs, errors.New("rate_limit_backend: postgres can't be used with in_memory"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("rate_limit_backend: %q is not one of memory, postgres", c.RateLimitBackend))
+	}
+
+	if c.RateLimitReads <= 0 {
+		errs = append(errs, fmt.Errorf("rate_limit_reads: must be positive, got %d", c.RateLimitReads))
+	}
+
+	if c.RateLimitWrites <= 0 {
+		errs = append(errs, fmt.Errorf("rate_limit_writes: must be positive, got %d", c.RateLimitWrites))
+	}
+
+	return errs
+}
+
+func (c *Config) validateNotifier() []error {
+	var errs []error
+
+	switch c.Notifier {
+	case "log":
+	case "smtp":
+		if c.SMTPAddr == "" {
+			errs = append(errs, errors.New("smtp_addr: required by the smtp notifier"))
+		} else if _, _, err := net.SplitHostPort(c.SMTPAddr); err != nil {
+			errs = append(errs, fmt.Errorf("smtp_addr: %q is not host:port", c.SMTPAddr))
+		}
+
+		if _, err := mail.ParseAddress(c.SMTPFrom); err != nil {
+			errs = append(errs, fmt.Errorf("smtp_from: %q is not an email address", c.SMTPFrom))
+		}
+	case "webhook":
+		if u, err := url.Parse(c.WebhookURL); err != nil || u.Scheme == "" || u.Host == "" {
+			errs = append(errs, fmt.Errorf("webhook_url: %q is not an absolute URL", c.WebhookURL))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("notifier: %q is not one of log, smtp, webhook", c.Notifier))
+	}
+
+	if c.DigestHour < 0 || c.DigestHour > 23 {
+		errs = append(errs, fmt.Errorf("digest_hour: %d is out of range 0-23", c.DigestHour))
+	}
+
+	return errs
+}
+
+func ParseTrustedProxies(value string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		if addr, err := netip.ParseAddr(item); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(item)
+		if err != nil {
+			return nil, fmt.Errorf("%q is neither an IP address nor a CIDR range", item)
+		}
+
+		prefixes = append(prefixes, prefix.Masked())
+	}
+
+	return prefixes, nil
 }
 
 func (c *Config) String() string {
-	return fmt.Sprintf("Port: %s, DBConn: %s", c.ServerPort, c.DBConn)
+	var b strings.Builder
+
+	for _, f := range fields {
+		value := f.get(c)
+
+		if f.redact != nil {
+			value = f.redact(value)
+		}
+
+		if value == "" {
+			value = `""`
+		}
+
+		fmt.Fprintf(&b, "%s: %s\n", f.name, value)
+	}
+
+	return b.String()
+}
+
+var keywordPassword = regexp.MustCompile(`(?i)(password\s*=\s*)('(?:[^'\\]|\\.)*'|\S+)`)
+
+// redactSecret hides a value entirely, leaving an empty one visible as unset.
+func redactSecret(secret string) string {
+	if secret == "" {
+		return ""
+	}
+
+	return redacted
+}
+
+func redactConnString(conn string) string {
+	if u, err := url.Parse(conn); err == nil && u.Scheme != "" && u.User != nil {
+		if _, hasPassword := u.User.Password(); hasPassword {
+			u.User = url.UserPassword(u.User.Username(), redacted)
+		}
+
+		query := u.Query()
+		if query.Has("password") {
+			query.Set("password", redacted)
+			u.RawQuery = query.Encode()
+		}
+
+		return u.String()
+	}
+
+	return keywordPassword.ReplaceAllString(conn, "${1}"+redacted)
 }
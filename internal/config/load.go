@@ -0,0 +1,280 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// The key cache_ttl is read from the CACHE_TTL variable and the -cache-ttl flag.
+type field struct {
+	name   string
+	env    string
+	usage  string
+	ptr    func(c *Config) any
+	redact func(value string) string
+}
+
+var fields = []field{
+	{name: "port", env: "PORT", usage: "HTTP listen port, 0 picks a random one", ptr: func(c *Config) any { return &c.ServerPort }},
+	{name: "db_conn", env: "DB_CONN", usage: "Postgres connection string", ptr: func(c *Config) any { return &c.DBConn },
+		redact: redactConnString},
+	{name: "in_memory", env: "IN_MEMORY", usage: "keep tasks in memory instead of Postgres", ptr: func(c *Config) any { return &c.InMemory }},
+	{name: "auto_migrate", env: "AUTO_MIGRATE", usage: "apply pending migrations at startup", ptr: func(c *Config) any { return &c.AutoMigrate }},
+	{name: "log_level", env: "LOG_LEVEL", usage: "debug, info, warn or error", ptr: func(c *Config) any { return &c.LogLevel }},
+	{name: "shutdown_timeout", env: "SHUTDOWN_TIMEOUT", usage: "time allowed for graceful shutdown",
+		ptr: func(c *Config) any { return &c.ShutdownTimeout }},
+	{name: "shutdown_delay", env: "SHUTDOWN_DELAY", usage: "time /readyz reports the shutdown before connections are drained",
+		ptr: func(c *Config) any { return &c.ShutdownDelay }},
+	{name: "tracing_exporter", env: "TRACING_EXPORTER", usage: "none or otlp", ptr: func(c *Config) any { return &c.TracingExporter }},
+	{name: "otlp_endpoint", env: "OTLP_ENDPOINT", usage: "OTLP/HTTP traces endpoint URL", ptr: func(c *Config) any { return &c.OTLPEndpoint }},
+	{name: "cache_enabled", env: "CACHE_ENABLED", usage: "cache repository reads", ptr: func(c *Config) any { return &c.CacheEnabled }},
+	{name: "cache_size", env: "CACHE_SIZE", usage: "maximum number of cached entries", ptr: func(c *Config) any { return &c.CacheSize }},
+	{name: "cache_ttl", env: "CACHE_TTL", usage: "lifetime of cached entries", ptr: func(c *Config) any { return &c.CacheTTL }},
+	{name: "auth_enabled", env: "AUTH_ENABLED", usage: "require an API key on task and admin endpoints",
+		ptr: func(c *Config) any { return &c.AuthEnabled }},
+	{name: "jwks_source", env: "JWKS_SOURCE", usage: "file path or URL of the JWKS that JWTs are verified with",
+		ptr: func(c *Config) any { return &c.JWKSSource }},
+	{name: "jwt_issuer", env: "JWT_ISSUER", usage: "required iss claim of JWTs, must be set with jwks_source", ptr: func(c *Config) any { return &c.JWTIssuer }},
+	{name: "jwt_audience", env: "JWT_AUDIENCE", usage: "required aud claim of JWTs, must be set with jwks_source", ptr: func(c *Config) any { return &c.JWTAudience }},
+	{name: "jwt_roles_claim", env: "JWT_ROLES_CLAIM", usage: "JWT claim holding the user's roles",
+		ptr: func(c *Config) any { return &c.JWTRolesClaim }},
+	{name: "test_issuer", env: "TEST_ISSUER", usage: "issue unverified test JWTs at /auth/test/token, development only",
+		ptr: func(c *Config) any { return &c.TestIssuer }},
+	{name: "rate_limit_enabled", env: "RATE_LIMIT_ENABLED", usage: "limit requests per API key or client IP",
+		ptr: func(c *Config) any { return &c.RateLimitEnabled }},
+	{name: "rate_limit_backend", env: "RATE_LIMIT_BACKEND", usage: "memory, or postgres to share limits between instances",
+		ptr: func(c *Config) any { return &c.RateLimitBackend }},
+	{name: "rate_limit_reads", env: "RATE_LIMIT_READS", usage: "read requests allowed per client per minute",
+		ptr: func(c *Config) any { return &c.RateLimitReads }},
+	{name: "rate_limit_writes", env: "RATE_LIMIT_WRITES", usage: "write requests allowed per client per minute",
+		ptr: func(c *Config) any { return &c.RateLimitWrites }},
+	{name: "trusted_proxies", env: "TRUSTED_PROXIES", usage: "comma-separated proxy IPs and CIDRs whose X-Forwarded-For is trusted",
+		ptr: func(c *Config) any { return &c.TrustedProxies }},
+	{name: "scheduler_interval", env: "SCHEDULER_INTERVAL", usage: "how often recurring tasks are advanced and reminders are sent",
+		ptr: func(c *Config) any { return &c.SchedulerInterval }},
+	{name: "notifier", env: "NOTIFIER", usage: "log, smtp or webhook, where reminders and digests are sent",
+		ptr: func(c *Config) any { return &c.Notifier }},
+	{name: "smtp_addr", env: "SMTP_ADDR", usage: "host:port of the SMTP server", ptr: func(c *Config) any { return &c.SMTPAddr }},
+	{name: "smtp_from", env: "SMTP_FROM", usage: "sender address of notification emails", ptr: func(c *Config) any { return &c.SMTPFrom }},
+	{name: "smtp_username", env: "SMTP_USERNAME", usage: "SMTP username, empty to skip authentication",
+		ptr: func(c *Config) any { return &c.SMTPUsername }},
+	{name: "smtp_password", env: "SMTP_PASSWORD", usage: "SMTP password", ptr: func(c *Config) any { return &c.SMTPPassword },
+		redact: redactSecret},
+	{name: "smtp_domain", env: "SMTP_DOMAIN", usage: "domain appended to users that aren't email addresses",
+		ptr: func(c *Config) any { return &c.SMTPDomain }},
+	{name: "webhook_url", env: "WEBHOOK_URL", usage: "URL notifications are posted to", ptr: func(c *Config) any { return &c.WebhookURL }},
+	{name: "webhook_secret", env: "WEBHOOK_SECRET", usage: "key of the HMAC signature of webhook requests",
+		ptr: func(c *Config) any { return &c.WebhookSecret }, redact: redactSecret},
+	{name: "digest_enabled", env: "DIGEST_ENABLED", usage: "send assignees a daily digest of their overdue tasks",
+		ptr: func(c *Config) any { return &c.DigestEnabled }},
+	{name: "digest_hour", env: "DIGEST_HOUR", usage: "hour of the day, UTC, the digest is sent at",
+		ptr: func(c *Config) any { return &c.DigestHour }},
+}
+
+func (f field) flagName() string {
+	return strings.ReplaceAll(f.name, "_", "-")
+}
+
+func (f field) get(c *Config) string {
+	switch v := f.ptr(c).(type) {
+	case *string:
+		return *v
+	case *int:
+		return strconv.Itoa(*v)
+	case *bool:
+		return strconv.FormatBool(*v)
+	case *time.Duration:
+		return v.String()
+	default:
+		panic(fmt.Sprintf("config: unsupported type %T of %s", v, f.name))
+	}
+}
+
+func (f field) set(c *Config, value string) error {
+	var err error
+
+	switch v := f.ptr(c).(type) {
+	case *string:
+		*v = value
+	case *int:
+		*v, err = strconv.Atoi(value)
+	case *bool:
+		*v, err = strconv.ParseBool(value)
+	case *time.Duration:
+		*v, err = time.ParseDuration(value)
+	default:
+		panic(fmt.Sprintf("config: unsupported type %T of %s", v, f.name))
+	}
+
+	if err != nil {
+		return fmt.Errorf("invalid value %q", value)
+	}
+
+	return nil
+}
+
+type flagValue struct {
+	name   string
+	isBool bool
+	set    *[][2]string
+}
+
+func (v flagValue) IsBoolFlag() bool {
+	return v.isBool
+}
+
+func (v flagValue) String() string {
+	return ""
+}
+
+func (v flagValue) Set(value string) error {
+	*v.set = append(*v.set, [2]string{v.name, value})
+	return nil
+}
+
+// Load applies, in increasing order of precedence, defaults, the file named by -config
+// or CONFIG_FILE, the environment and flags.
+func Load(args []string) (*Config, []string, error) {
+	flags := flag.NewFlagSet("task-tracker", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+
+	configFile := flags.String("config", "", "path to a YAML or JSON configuration file")
+
+	var flagValues [][2]string
+
+	for _, f := range fields {
+		_, isBool := f.ptr(&Config{}).(*bool)
+		flags.Var(flagValue{name: f.name, isBool: isBool, set: &flagValues}, f.flagName(), f.usage)
+	}
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil, nil, errors.New(Usage())
+		}
+
+		return nil, nil, err
+	}
+
+	// A missing .env file is normal outside of docker-compose.
+	_ = godotenv.Load()
+
+	if *configFile == "" {
+		*configFile = os.Getenv("CONFIG_FILE")
+	}
+
+	cfg := Default()
+
+	var errs []error
+
+	if *configFile != "" {
+		errs = append(errs, loadFile(&cfg, *configFile)...)
+	}
+
+	for _, f := range fields {
+		if value, exists := os.LookupEnv(f.env); exists {
+			if err := f.set(&cfg, value); err != nil {
+				errs = append(errs, fmt.Errorf("env %s: %w", f.env, err))
+			}
+		}
+	}
+
+	byName := make(map[string]field, len(fields))
+	for _, f := range fields {
+		byName[f.name] = f
+	}
+
+	for _, flagValue := range flagValues {
+		f := byName[flagValue[0]]
+
+		if err := f.set(&cfg, flagValue[1]); err != nil {
+			errs = append(errs, fmt.Errorf("flag -%s: %w", f.flagName(), err))
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) > 0 {
+		return nil, nil, fmt.Errorf("invalid configuration:\n%w", errors.Join(errs...))
+	}
+
+	return &cfg, flags.Args(), nil
+}
+
+func loadFile(cfg *Config, path string) []error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return []error{fmt.Errorf("config file: %w", err)}
+	}
+
+	// YAML is a superset of JSON, so one decoder handles both formats.
+	var values map[string]yaml.Node
+
+	if err := yaml.Unmarshal(content, &values); err != nil {
+		return []error{fmt.Errorf("config file %s: %w", path, err)}
+	}
+
+	var errs []error
+
+	for _, f := range fields {
+		node, found := values[f.name]
+		if !found {
+			continue
+		}
+
+		delete(values, f.name)
+
+		if node.Kind != yaml.ScalarNode {
+			errs = append(errs, fmt.Errorf("config file %s: %s: expected a scalar value", path, f.name))
+			continue
+		}
+
+		if err := f.set(cfg, node.Value); err != nil {
+			errs = append(errs, fmt.Errorf("config file %s: %s: %w", path, f.name, err))
+		}
+	}
+
+	unknown := make([]string, 0, len(values))
+	for key := range values {
+		unknown = append(unknown, key)
+	}
+
+	sort.Strings(unknown)
+
+	for _, key := range unknown {
+		errs = append(errs, fmt.Errorf("config file %s: unknown key %q", path, key))
+	}
+
+	return errs
+}
+
+func Usage() string {
+	var b strings.Builder
+
+	b.WriteString("Flags:\n  -config string\n\tpath to a YAML or JSON configuration file (env CONFIG_FILE)\n")
+
+	defaults := Default()
+
+	for _, f := range fields {
+		value := f.get(&defaults)
+
+		if f.redact != nil {
+			value = f.redact(value)
+		}
+
+		fmt.Fprintf(&b, "  -%s\n\t%s (env %s, file key %s, default %q)\n", f.flagName(), f.usage, f.env, f.name, value)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,69 @@
+package events
+
+import (
+	"sync"
+)
+
+type Type string
+
+const (
+	TaskCreated Type = "created"
+	TaskUpdated Type = "updated"
+	TaskDeleted Type = "deleted"
+)
+
+type TaskEvent struct {
+	Type        Type   `json:"type"`
+	WorkspaceID string `json:"workspace_id"`
+	TaskID      string `json:"task_id"`
+}
+
+// Publish never blocks, events are dropped for subscribers whose buffer is full.
+type Bus struct {
+	subscribers map[int]chan TaskEvent
+	nextID      int
+	mu          sync.RWMutex
+}
+
+func NewBus() *Bus {
+	return &Bus{
+		subscribers: make(map[int]chan TaskEvent),
+	}
+}
+
+func (b *Bus) Subscribe(buffer int) (<-chan TaskEvent, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+
+	ch := make(chan TaskEvent, buffer)
+	b.subscribers[id] = ch
+
+	var once sync.Once
+
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+
+			delete(b.subscribers, id)
+			close(ch)
+		})
+	}
+
+	return ch, unsubscribe
+}
+
+func (b *Bus) Publish(event TaskEvent) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package events
+
+import (
+	"testing"
+)
+
+func TestBus_Publish(t *testing.T) {
+	tests := map[string]struct {
+		subscribers int
+		events      []TaskEvent
+	}{
+		"single subscriber receives event": {
+			subscribers: 1,
+			events:      []TaskEvent{{Type: TaskCreated, TaskID: "task1"}},
+		},
+
+		"every subscriber receives every event": {
+			subscribers: 3,
+			events: []TaskEvent{
+				{Type: TaskCreated, TaskID: "task1"},
+				{Type: TaskUpdated, TaskID: "task1"},
+				{Type: TaskDeleted, TaskID: "task1"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			bus := NewBus()
+			channels := make([]<-chan TaskEvent, test.subscribers)
+
+			for i := range channels {
+				ch, unsubscribe := bus.Subscribe(len(test.events))
+				defer unsubscribe()
+
+				channels[i] = ch
+			}
+
+			for _, event := range test.events {
+				bus.Publish(event)
+			}
+
+			for _, ch := range channels {
+				for _, expected := range test.events {
+					if got := <-ch; got != expected {
+						t.Fatalf("test-case: (%q); returned %v; expected %v", name, got, expected)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestBus_PublishDropsWhenFull(t *testing.T) {
+	bus := NewBus()
+
+	ch, unsubscribe := bus.Subscribe(1)
+	defer unsubscribe()
+
+	bus.Publish(TaskEvent{Type: TaskCreated, TaskID: "task1"})
+	bus.Publish(TaskEvent{Type: TaskCreated, TaskID: "task2"})
+
+	if got := <-ch; got.TaskID != "task1" {
+		t.Fatalf("returned %v; expected first event to be kept", got)
+	}
+
+	select {
+	case got := <-ch:
+		t.Fatalf("returned %v; expected second event to be dropped", got)
+	default:
+	}
+}
+
+func TestBus_Unsubscribe(t *testing.T) {
+	bus := NewBus()
+
+	ch, unsubscribe := bus.Subscribe(1)
+	unsubscribe()
+	unsubscribe()
+
+	bus.Publish(TaskEvent{Type: TaskDeleted, TaskID: "task1"})
+
+	if _, ok := <-ch; ok {
+		t.Fatalf("expected channel to be closed after unsubscribe")
+	}
+}
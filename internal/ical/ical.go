@@ -0,0 +1,185 @@
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"task-tracker/internal/models"
+)
+
+const (
+	// ComponentEvent places tasks at their due date, which every calendar app shows.
+	ComponentEvent = "VEVENT"
+	// ComponentTodo keeps them tasks with a due date, for apps with task lists.
+	ComponentTodo = "VTODO"
+)
+
+const (
+	productID = "-//task-tracker//task-tracker//EN"
+
+	// uidDomain makes UIDs globally unique as RFC 5545 recommends.
+	uidDomain = "task-tracker"
+
+	dateTimeFormat = "20060102T150405Z"
+
+	// maxLineLength is the limit of content lines in octets, without the CRLF.
+	maxLineLength = 75
+)
+
+// Tasks without a due date are left out of events, which need a start.
+type Calendar struct {
+	Name      string
+	Component string
+	Tasks     []models.Task
+}
+
+// UID is the one a CalDAV client created the task with, or derived from the task ID, so
+// it stays the same however the task changes.
+func UID(task models.Task) string {
+	if task.CalendarUID != "" {
+		return task.CalendarUID
+	}
+
+	return task.ID + "@" + uidDomain
+}
+
+func Encode(w io.Writer, calendar Calendar) error {
+	e := &encoder{w: bufio.NewWriter(w)}
+
+	e.line("BEGIN", "VCALENDAR")
+	e.line("VERSION", "2.0")
+	e.line("PRODID", productID)
+	e.line("CALSCALE", "GREGORIAN")
+
+	if calendar.Name != "" {
+		e.line("X-WR-CALNAME", escape(calendar.Name))
+	}
+
+	for _, task := range calendar.Tasks {
+		if calendar.Component == ComponentTodo {
+			e.todo(task)
+		} else if task.DueAt != nil {
+			e.event(task)
+		}
+	}
+
+	e.line("END", "VCALENDAR")
+
+	if e.err != nil {
+		return e.err
+	}
+
+	return e.w.Flush()
+}
+
+type encoder struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (e *encoder) event(task models.Task) {
+	e.line("BEGIN", ComponentEvent)
+	e.common(task)
+	e.line("DTSTART", formatTime(*task.DueAt))
+	e.line("STATUS", eventStatus(task.Status))
+	e.line("TRANSP", "TRANSPARENT")
+	e.line("END", ComponentEvent)
+}
+
+func (e *encoder) todo(task models.Task) {
+	e.line("BEGIN", ComponentTodo)
+	e.common(task)
+
+	if task.DueAt != nil {
+		e.line("DUE", formatTime(*task.DueAt))
+	}
+
+	e.line("STATUS", TodoStatus(task.Status))
+	e.line("END", ComponentTodo)
+}
+
+func (e *encoder) common(task models.Task) {
+	e.line("UID", escape(UID(task)))
+	e.line("DTSTAMP", formatTime(task.UpdatedAt))
+	e.line("CREATED", formatTime(task.CreatedAt))
+	e.line("LAST-MODIFIED", formatTime(task.UpdatedAt))
+	e.line("SUMMARY", escape(task.Title))
+
+	if task.Description != "" {
+		e.line("DESCRIPTION", escape(task.Description))
+	}
+
+	if len(task.Labels) > 0 {
+		categories := make([]string, len(task.Labels))
+		for i, label := range task.Labels {
+			categories[i] = escape(label)
+		}
+
+		e.line("CATEGORIES", strings.Join(categories, ","))
+	}
+}
+
+// line writes "name:value", folding it after every 75 octets without splitting
+// UTF-8 sequences.
+func (e *encoder) line(name, value string) {
+	if e.err != nil {
+		return
+	}
+
+	line := name + ":" + value
+
+	var b strings.Builder
+
+	width := 0
+
+	for _, r := range line {
+		size := utf8.RuneLen(r)
+
+		if width+size > maxLineLength {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+
+		b.WriteRune(r)
+		width += size
+	}
+
+	b.WriteString("\r\n")
+
+	_, e.err = e.w.WriteString(b.String())
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+func escape(text string) string {
+	return textEscaper.Replace(text)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(dateTimeFormat)
+}
+
+// Statuses without a VTODO counterpart count as being worked on.
+func TodoStatus(status string) string {
+	switch status {
+	case models.StatusTodo:
+		return "NEEDS-ACTION"
+	case models.StatusDone:
+		return "COMPLETED"
+	case models.StatusCanceled, models.StatusSkipped:
+		return "CANCELLED"
+	default:
+		return "IN-PROCESS"
+	}
+}
+
+func eventStatus(status string) string {
+	if status == models.StatusCanceled || status == models.StatusSkipped {
+		return "CANCELLED"
+	}
+
+	return "CONFIRMED"
+}
@@ -0,0 +1,119 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"task-tracker/internal/models"
+)
+
+func TestEncode(t *testing.T) {
+	created := time.Date(2025, time.April, 9, 8, 0, 0, 0, time.UTC)
+	due := time.Date(2025, time.April, 14, 9, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+
+	task := models.Task{
+		ID:          "7d1c",
+		Title:       "Prepare release; notes, changelog",
+		Description: "Line one\nLine two",
+		Status:      "in_progress",
+		CreatedAt:   created,
+		UpdatedAt:   created.Add(time.Hour),
+		DueAt:       &due,
+		Labels:      []string{"release", "docs"},
+	}
+
+	undated := models.Task{ID: "9a2b", Title: "Someday", Status: models.StatusTodo, CreatedAt: created, UpdatedAt: created}
+
+	tests := map[string]struct {
+		calendar Calendar
+		expected []string
+		absent   []string
+	}{
+		"event": {
+			calendar: Calendar{Name: "Tasks", Component: ComponentEvent, Tasks: []models.Task{task, undated}},
+			expected: []string{
+				"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+				"X-WR-CALNAME:Tasks\r\n",
+				"BEGIN:VEVENT\r\nUID:7d1c@task-tracker\r\nDTSTAMP:20250409T090000Z\r\n",
+				`SUMMARY:Prepare release\; notes\, changelog` + "\r\n",
+				`DESCRIPTION:Line one\nLine two` + "\r\n",
+				"CATEGORIES:release,docs\r\n",
+				"DTSTART:20250414T073000Z\r\nSTATUS:CONFIRMED\r\n",
+				"END:VEVENT\r\nEND:VCALENDAR\r\n",
+			},
+			absent: []string{"9a2b"},
+		},
+
+		"todo": {
+			calendar: Calendar{Component: ComponentTodo, Tasks: []models.Task{task, undated}},
+			expected: []string{
+				"BEGIN:VTODO\r\nUID:7d1c@task-tracker\r\n",
+				"DUE:20250414T073000Z\r\nSTATUS:IN-PROCESS\r\nEND:VTODO\r\n",
+				"UID:9a2b@task-tracker\r\n",
+				"SUMMARY:Someday\r\nSTATUS:NEEDS-ACTION\r\n",
+			},
+			absent: []string{"X-WR-CALNAME", "VEVENT"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var b strings.Builder
+
+			if err := Encode(&b, test.calendar); err != nil {
+				t.Fatalf("test-case: (%q); unexpected error: %v", name, err)
+			}
+
+			for _, expected := range test.expected {
+				if !strings.Contains(b.String(), expected) {
+					t.Fatalf("test-case: (%q); returned %q; expected to contain %q", name, b.String(), expected)
+				}
+			}
+
+			for _, absent := range test.absent {
+				if strings.Contains(b.String(), absent) {
+					t.Fatalf("test-case: (%q); returned %q; expected not to contain %q", name, b.String(), absent)
+				}
+			}
+		})
+	}
+}
+
+func TestEncode_FoldsLongLines(t *testing.T) {
+	task := models.Task{ID: "1", Title: strings.Repeat("Überprüfung ", 20), Status: models.StatusTodo}
+
+	var b strings.Builder
+
+	if err := Encode(&b, Calendar{Component: ComponentTodo, Tasks: []models.Task{task}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var summary strings.Builder
+
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n")
+
+	for i, line := range lines {
+		if len(line) > maxLineLength {
+			t.Fatalf("returned line %q of %d octets; expected at most %d", line, len(line), maxLineLength)
+		}
+
+		if strings.HasPrefix(line, "SUMMARY:") {
+			summary.WriteString(line)
+
+			for _, next := range lines[i+1:] {
+				if !strings.HasPrefix(next, " ") {
+					break
+				}
+
+				summary.WriteString(next[1:])
+			}
+		}
+	}
+
+	if summary.String() != "SUMMARY:"+task.Title {
+		t.Fatalf("returned %q; expected the unfolded summary %q", summary.String(), "SUMMARY:"+task.Title)
+	}
+}
@@ -0,0 +1,300 @@
+package ical
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	dateFormat          = "20060102"
+	floatingTimeFormat  = "20060102T150405"
+	maxCalendarDataSize = 1 << 20
+)
+
+var ErrMalformed = errors.New("malformed iCalendar data")
+
+type Component struct {
+	Name       string
+	Properties []Property
+	Components []Component
+}
+
+// Value is kept raw, see Text, List and Time for decoding it.
+type Property struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+func (c Component) Property(name string) (Property, bool) {
+	for _, property := range c.Properties {
+		if property.Name == name {
+			return property, true
+		}
+	}
+
+	return Property{}, false
+}
+
+func (c Component) All(name string) []Property {
+	var properties []Property
+
+	for _, property := range c.Properties {
+		if property.Name == name {
+			properties = append(properties, property)
+		}
+	}
+
+	return properties
+}
+
+func (c Component) Children(name string) []Component {
+	var components []Component
+
+	for _, component := range c.Components {
+		if component.Name == name {
+			components = append(components, component)
+		}
+	}
+
+	return components
+}
+
+func (p Property) Text() string {
+	return unescape(p.Value)
+}
+
+func (p Property) List() []string {
+	var (
+		values  []string
+		current strings.Builder
+		escaped bool
+	)
+
+	for _, r := range p.Value {
+		switch {
+		case escaped:
+			current.WriteRune('\\')
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == ',':
+			values = append(values, unescape(current.String()))
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+
+	return append(values, unescape(current.String()))
+}
+
+// Dates are midnight and floating times are read in the TZID location if there is one,
+// else in UTC.
+func (p Property) Time() (time.Time, error) {
+	location := time.UTC
+
+	if tzid := p.Params["TZID"]; tzid != "" {
+		loaded, err := time.LoadLocation(strings.TrimPrefix(tzid, "/"))
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%w: unknown TZID %q", ErrMalformed, tzid)
+		}
+
+		location = loaded
+	}
+
+	layout := floatingTimeFormat
+
+	switch {
+	case strings.EqualFold(p.Params["VALUE"], "DATE") || len(p.Value) == len(dateFormat):
+		layout = dateFormat
+	case strings.HasSuffix(p.Value, "Z"):
+		layout = dateTimeFormat
+		location = time.UTC
+	}
+
+	t, err := time.ParseInLocation(layout, p.Value, location)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: invalid %s %q", ErrMalformed, p.Name, p.Value)
+	}
+
+	return t, nil
+}
+
+// Parse reads at most 1 MiB. Names are upper-cased and lines are unfolded, everything
+// else is left to the caller.
+func Parse(r io.Reader) (Component, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxCalendarDataSize+1))
+	if err != nil {
+		return Component{}, err
+	}
+
+	if len(data) > maxCalendarDataSize {
+		return Component{}, fmt.Errorf("%w: larger than %d bytes", ErrMalformed, maxCalendarDataSize)
+	}
+
+	var (
+		root  *Component
+		stack []*Component
+	)
+
+	for number, line := range unfold(string(data)) {
+		if line == "" {
+			continue
+		}
+
+		property, err := parseLine(line)
+		if err != nil {
+			return Component{}, fmt.Errorf("%w: content line %d", err, number+1)
+		}
+
+		switch property.Name {
+		case "BEGIN":
+			if root != nil && len(stack) == 0 {
+				return Component{}, fmt.Errorf("%w: content after END:%s", ErrMalformed, root.Name)
+			}
+
+			component := &Component{Name: strings.ToUpper(property.Value)}
+
+			if len(stack) == 0 {
+				root = component
+			}
+
+			stack = append(stack, component)
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1].Name != strings.ToUpper(property.Value) {
+				return Component{}, fmt.Errorf("%w: unexpected END:%s", ErrMalformed, property.Value)
+			}
+
+			ended := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.Components = append(parent.Components, *ended)
+			}
+		default:
+			if len(stack) == 0 {
+				return Component{}, fmt.Errorf("%w: property %s outside of a component", ErrMalformed, property.Name)
+			}
+
+			current := stack[len(stack)-1]
+			current.Properties = append(current.Properties, property)
+		}
+	}
+
+	if root == nil || len(stack) > 0 {
+		return Component{}, fmt.Errorf("%w: missing BEGIN or END", ErrMalformed)
+	}
+
+	return *root, nil
+}
+
+// Bare LF line endings are accepted as well.
+func unfold(data string) []string {
+	var lines []string
+
+	for _, line := range strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n") {
+		if len(lines) > 0 && line != "" && (line[0] == ' ' || line[0] == '\t') {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// parseLine splits `NAME;PARAM=value;PARAM="quoted:value":value` into a property.
+func parseLine(line string) (Property, error) {
+	var (
+		fields []string
+		start  int
+		quoted bool
+		value  = -1
+	)
+
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		}
+
+		if quoted {
+			continue
+		}
+
+		if r == ';' {
+			fields = append(fields, line[start:i])
+			start = i + 1
+		}
+
+		if r == ':' {
+			fields = append(fields, line[start:i])
+			value = i + 1
+
+			break
+		}
+	}
+
+	if value < 0 || fields[0] == "" {
+		return Property{}, ErrMalformed
+	}
+
+	property := Property{
+		Name:  strings.ToUpper(fields[0]),
+		Value: line[value:],
+	}
+
+	for _, field := range fields[1:] {
+		name, paramValue, ok := strings.Cut(field, "=")
+		if !ok || name == "" {
+			return Property{}, ErrMalformed
+		}
+
+		if property.Params == nil {
+			property.Params = make(map[string]string)
+		}
+
+		property.Params[strings.ToUpper(name)] = strings.Trim(paramValue, `"`)
+	}
+
+	return property, nil
+}
+
+func unescape(text string) string {
+	if !strings.Contains(text, `\`) {
+		return text
+	}
+
+	var b strings.Builder
+
+	escaped := false
+
+	for _, r := range text {
+		if !escaped {
+			if r == '\\' {
+				escaped = true
+			} else {
+				b.WriteRune(r)
+			}
+
+			continue
+		}
+
+		switch r {
+		case 'n', 'N':
+			b.WriteRune('\n')
+		default:
+			b.WriteRune(r)
+		}
+
+		escaped = false
+	}
+
+	return b.String()
+}
@@ -0,0 +1,142 @@
+package ical
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"task-tracker/internal/models"
+)
+
+func TestParse(t *testing.T) {
+	data := "BEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"BEGIN:VTODO\r\n" +
+		"UID:a1\r\n" +
+		"SUMMARY:Call the bank\\, then\r\n" +
+		"  the notary\r\n" +
+		"X-NOTE;LANGUAGE=en;ALTREP=\"cid:part1;x:y\":note\r\n" +
+		"CATEGORIES:home,errands\\,misc\r\n" +
+		"CATEGORIES:urgent\r\n" +
+		"END:VTODO\r\n" +
+		"END:VCALENDAR\r\n"
+
+	calendar, err := Parse(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	todos := calendar.Children(ComponentTodo)
+	if calendar.Name != "VCALENDAR" || len(todos) != 1 {
+		t.Fatalf("returned %+v; expected a VCALENDAR with one VTODO", calendar)
+	}
+
+	summary, _ := todos[0].Property("SUMMARY")
+	if summary.Text() != "Call the bank, then the notary" {
+		t.Fatalf("returned %q; expected the unfolded and unescaped summary", summary.Text())
+	}
+
+	note, _ := todos[0].Property("X-NOTE")
+	expectedParams := map[string]string{"LANGUAGE": "en", "ALTREP": "cid:part1;x:y"}
+
+	if note.Value != "note" || !reflect.DeepEqual(note.Params, expectedParams) {
+		t.Fatalf("returned %+v; expected value note with params %v", note, expectedParams)
+	}
+
+	var categories []string
+	for _, property := range todos[0].All("CATEGORIES") {
+		categories = append(categories, property.List()...)
+	}
+
+	if expected := []string{"home", "errands,misc", "urgent"}; !reflect.DeepEqual(categories, expected) {
+		t.Fatalf("returned %q; expected %q", categories, expected)
+	}
+}
+
+func TestParse_Malformed(t *testing.T) {
+	tests := map[string]string{
+		"empty":                 "",
+		"no colon":              "BEGIN:VCALENDAR\r\nSUMMARY\r\nEND:VCALENDAR\r\n",
+		"unclosed component":    "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nEND:VCALENDAR\r\n",
+		"property outside":      "SUMMARY:x\r\n",
+		"two root components":   "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\nBEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n",
+		"parameter without '='": "BEGIN:VCALENDAR\r\nDUE;TZID:20250101\r\nEND:VCALENDAR\r\n",
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := Parse(strings.NewReader(data)); !errors.Is(err, ErrMalformed) {
+				t.Fatalf("test-case: (%q); returned %v; expected %v", name, err, ErrMalformed)
+			}
+		})
+	}
+}
+
+func TestProperty_Time(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+
+	tests := map[string]struct {
+		property Property
+		expected time.Time
+		err      bool
+	}{
+		"utc":      {Property{Name: "DUE", Value: "20250414T070000Z"}, time.Date(2025, time.April, 14, 7, 0, 0, 0, time.UTC), false},
+		"tzid":     {Property{Name: "DUE", Value: "20250414T090000", Params: map[string]string{"TZID": "Europe/Berlin"}}, time.Date(2025, time.April, 14, 9, 0, 0, 0, berlin), false},
+		"floating": {Property{Name: "DUE", Value: "20250414T090000"}, time.Date(2025, time.April, 14, 9, 0, 0, 0, time.UTC), false},
+		"date":     {Property{Name: "DUE", Value: "20250414", Params: map[string]string{"VALUE": "DATE"}}, time.Date(2025, time.April, 14, 0, 0, 0, 0, time.UTC), false},
+		"unknown tzid": {
+			Property{Name: "DUE", Value: "20250414T090000", Params: map[string]string{"TZID": "W. Europe Standard Time"}}, time.Time{}, true,
+		},
+		"invalid": {Property{Name: "DUE", Value: "tomorrow"}, time.Time{}, true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			returned, err := test.property.Time()
+			if (err != nil) != test.err || !returned.Equal(test.expected) {
+				t.Fatalf("test-case: (%q); returned %v, %v; expected %v", name, returned, err, test.expected)
+			}
+		})
+	}
+}
+
+func TestEncode_RoundTrip(t *testing.T) {
+	created := time.Date(2025, time.April, 9, 8, 0, 0, 0, time.UTC)
+	task := models.Task{
+		ID:          "7d1c",
+		Title:       strings.Repeat("Long title; with, escapes ", 5),
+		Description: "Line one\nLine two",
+		Status:      models.StatusTodo,
+		CreatedAt:   created,
+		UpdatedAt:   created,
+		CalendarUID: "client-uid",
+	}
+
+	var b strings.Builder
+
+	if err := Encode(&b, Calendar{Component: ComponentTodo, Tasks: []models.Task{task}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	calendar, err := Parse(strings.NewReader(b.String()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	todo := calendar.Children(ComponentTodo)[0]
+
+	for name, expected := range map[string]string{"UID": "client-uid", "SUMMARY": task.Title, "DESCRIPTION": task.Description} {
+		if property, _ := todo.Property(name); property.Text() != expected {
+			t.Fatalf("test-case: (%q); returned %q; expected %q", name, property.Text(), expected)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"task-tracker/internal/repository"
+)
+
+const taskCountTimeout = 5 * time.Second
+
+type poolCollector struct {
+	pool *pgxpool.Pool
+
+	acquiredConns      *prometheus.Desc
+	idleConns          *prometheus.Desc
+	totalConns         *prometheus.Desc
+	maxConns           *prometheus.Desc
+	acquireCount       *prometheus.Desc
+	acquireDuration    *prometheus.Desc
+	emptyAcquireCount  *prometheus.Desc
+	canceledAcquires   *prometheus.Desc
+	newConnsCount      *prometheus.Desc
+	maxLifetimeDestroy *prometheus.Desc
+	maxIdleDestroy     *prometheus.Desc
+}
+
+// NewPoolCollector exposes pgxpool connection statistics, read on every scrape.
+func NewPoolCollector(pool *pgxpool.Pool) prometheus.Collector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "db_pool", name), help, nil, nil)
+	}
+
+	return &poolCollector{
+		pool:               pool,
+		acquiredConns:      desc("acquired_connections", "Number of currently acquired connections."),
+		idleConns:          desc("idle_connections", "Number of currently idle connections."),
+		totalConns:         desc("total_connections", "Total number of connections in the pool."),
+		maxConns:           desc("max_connections", "Maximum size of the pool."),
+		acquireCount:       desc("acquires_total", "Number of successful connection acquires."),
+		acquireDuration:    desc("acquire_duration_seconds_total", "Total time spent acquiring connections."),
+		emptyAcquireCount:  desc("empty_acquires_total", "Number of acquires that had to wait for a connection."),
+		canceledAcquires:   desc("canceled_acquires_total", "Number of acquires canceled by their context."),
+		newConnsCount:      desc("new_connections_total", "Number of connections opened."),
+		maxLifetimeDestroy: desc("max_lifetime_destroys_total", "Number of connections closed because of max lifetime."),
+		maxIdleDestroy:     desc("max_idle_destroys_total", "Number of connections closed because of max idle time."),
+	}
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	prometheus.DescribeByCollect(c, ch)
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.pool.Stat()
+
+	ch <- prometheus.MustNewConstMetric(c.acquiredConns, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(c.maxConns, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquireCount, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.acquireDuration, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquireCount, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.canceledAcquires, prometheus.CounterValue, float64(stat.CanceledAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.newConnsCount, prometheus.CounterValue, float64(stat.NewConnsCount()))
+	ch <- prometheus.MustNewConstMetric(c.maxLifetimeDestroy, prometheus.CounterValue, float64(stat.MaxLifetimeDestroyCount()))
+	ch <- prometheus.MustNewConstMetric(c.maxIdleDestroy, prometheus.CounterValue, float64(stat.MaxIdleDestroyCount()))
+}
+
+type taskCollector struct {
+	repo     repository.TaskRepository
+	tasks    *prometheus.Desc
+	scrapeUp *prometheus.Desc
+}
+
+// NewTaskCollector exposes the number of tasks per status, counted on every scrape.
+func NewTaskCollector(repo repository.TaskRepository) prometheus.Collector {
+	return &taskCollector{
+		repo: repo,
+		tasks: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "tasks"),
+			"Number of tasks by status.",
+			[]string{"status"}, nil,
+		),
+		scrapeUp: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "tasks_scrape_success"),
+			"Whether counting tasks for this scrape succeeded.",
+			nil, nil,
+		),
+	}
+}
+
+func (c *taskCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.tasks
+	ch <- c.scrapeUp
+}
+
+func (c *taskCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), taskCountTimeout)
+	defer cancel()
+
+	counts, err := c.repo.CountByStatus(ctx)
+	if err != nil {
+		ch <- prometheus.MustNewConstMetric(c.scrapeUp, prometheus.GaugeValue, 0)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.scrapeUp, prometheus.GaugeValue, 1)
+
+	for status, count := range counts {
+		ch <- prometheus.MustNewConstMetric(c.tasks, prometheus.GaugeValue, float64(count), status)
+	}
+}
+
+// NewCacheCollector exposes the hit and miss counters of the caching repository.
+func NewCacheCollector(cache *repository.CachedTaskRepository) prometheus.Collector {
+	return cacheCollector{cache: cache}
+}
+
+type cacheCollector struct {
+	cache *repository.CachedTaskRepository
+}
+
+var (
+	cacheHitsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "cache", "hits_total"), "Number of repository reads served from cache.", nil, nil)
+	cacheMissesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "cache", "misses_total"), "Number of repository reads that missed the cache.", nil, nil)
+)
+
+func (c cacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- cacheHitsDesc
+	ch <- cacheMissesDesc
+}
+
+func (c cacheCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.cache.Stats()
+
+	ch <- prometheus.MustNewConstMetric(cacheHitsDesc, prometheus.CounterValue, float64(stats.Hits))
+	ch <- prometheus.MustNewConstMetric(cacheMissesDesc, prometheus.CounterValue, float64(stats.Misses))
+}
@@ -0,0 +1,103 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"task-tracker/internal/clock"
+	"task-tracker/internal/models"
+	"task-tracker/internal/repository"
+)
+
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", http.NoBody))
+
+	body, err := io.ReadAll(w.Body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return string(body)
+}
+
+func TestMetrics_Middleware(t *testing.T) {
+	tests := map[string]struct {
+		path     string
+		expected string
+	}{
+		"matched route is labeled with its pattern": {
+			path:     "/tasks/task1",
+			expected: `tasktracker_http_requests_total{method="GET",route="/tasks/{id}",status="404"} 1`,
+		},
+
+		"unmatched route is labeled as unmatched": {
+			path:     "/unknown",
+			expected: `tasktracker_http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+		},
+
+		"implicit status is recorded as ok": {
+			path:     "/tasks",
+			expected: `tasktracker_http_requests_total{method="GET",route="/tasks",status="200"} 1`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			m := New()
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("/tasks", func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte("[]"))
+			})
+			mux.HandleFunc("/tasks/{id}", func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			})
+
+			m.Middleware(mux).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, test.path, http.NoBody))
+
+			if body := scrape(t, m); !strings.Contains(body, test.expected) {
+				t.Fatalf("test-case: (%q); metrics don't contain %q:\n%s", name, test.expected, body)
+			}
+		})
+	}
+}
+
+func TestMetrics_Repository(t *testing.T) {
+	m := New()
+	repo := NewInstrumentedTaskRepository(repository.NewMemoryTaskRepository(clock.New()), "memory", m)
+	m.Register(NewTaskCollector(repo))
+
+	ctx := context.Background()
+
+	for _, task := range []*models.Task{
+		{ID: "task1", Status: "todo"},
+		{ID: "task2", Status: "todo"},
+		{ID: "task3", Status: "done"},
+	} {
+		if err := repo.Add(ctx, task); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	body := scrape(t, m)
+
+	for _, expected := range []string{
+		`tasktracker_repository_operation_duration_seconds_count{backend="memory",method="Add"} 3`,
+		`tasktracker_tasks{status="todo"} 2`,
+		`tasktracker_tasks{status="done"} 1`,
+		`tasktracker_tasks_scrape_success 1`,
+	} {
+		if !strings.Contains(body, expected) {
+			t.Fatalf("metrics don't contain %q:\n%s", expected, body)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"task-tracker/internal/models"
+	"task-tracker/internal/repository"
+)
+
+// InstrumentedTaskRepository records the latency and failures of every call made to the
+// wrapped repository, labeled with the backend name.
+type InstrumentedTaskRepository struct {
+	next    repository.TaskRepository
+	backend string
+	metrics *Metrics
+}
+
+func NewInstrumentedTaskRepository(next repository.TaskRepository, backend string, metrics *Metrics) *InstrumentedTaskRepository {
+	return &InstrumentedTaskRepository{
+		next:    next,
+		backend: backend,
+		metrics: metrics,
+	}
+}
+
+func (repo *InstrumentedTaskRepository) Add(ctx context.Context, task *models.Task) (err error) {
+	defer repo.observe("Add", time.Now(), &err)
+
+	return repo.next.Add(ctx, task)
+}
+
+func (repo *InstrumentedTaskRepository) Delete(ctx context.Context, id string) (err error) {
+	defer repo.observe("Delete", time.Now(), &err)
+
+	return repo.next.Delete(ctx, id)
+}
+
+func (repo *InstrumentedTaskRepository) Exists(ctx context.Context, id string) (_ bool, err error) {
+	defer repo.observe("Exists", time.Now(), &err)
+
+	return repo.next.Exists(ctx, id)
+}
+
+func (repo *InstrumentedTaskRepository) IDTaken(ctx context.Context, id string) (_ bool, err error) {
+	defer repo.observe("IDTaken", time.Now(), &err)
+
+	return repo.next.IDTaken(ctx, id)
+}
+
+func (repo *InstrumentedTaskRepository) Get(ctx context.Context, id string) (_ models.Task, err error) {
+	defer repo.observe("Get", time.Now(), &err)
+
+	return repo.next.Get(ctx, id)
+}
+
+func (repo *InstrumentedTaskRepository) GetAll(ctx context.Context) (_ []models.Task, err error) {
+	defer repo.observe("GetAll", time.Now(), &err)
+
+	return repo.next.GetAll(ctx)
+}
+
+func (repo *InstrumentedTaskRepository) FindByCalendar(ctx context.Context, name, uid string) (_ []models.Task, err error) {
+	defer repo.observe("FindByCalendar", time.Now(), &err)
+
+	return repo.next.FindByCalendar(ctx, name, uid)
+}
+
+func (repo *InstrumentedTaskRepository) Stream(ctx context.Context, fn func(models.Task) error) (err error) {
+	defer repo.observe("Stream", time.Now(), &err)
+
+	return repo.next.Stream(ctx, fn)
+}
+
+func (repo *InstrumentedTaskRepository) Update(ctx context.Context, updatedTask *models.Task) (err error) {
+	defer repo.observe("Update", time.Now(), &err)
+
+	return repo.next.Update(ctx, updatedTask)
+}
+
+func (repo *InstrumentedTaskRepository) CountByStatus(ctx context.Context) (_ map[string]int, err error) {
+	defer repo.observe("CountByStatus", time.Now(), &err)
+
+	return repo.next.CountByStatus(ctx)
+}
+
+func (repo *InstrumentedTaskRepository) Ping(ctx context.Context) (err error) {
+	defer repo.observe("Ping", time.Now(), &err)
+
+	return repo.next.Ping(ctx)
+}
+
+func (repo *InstrumentedTaskRepository) GetSeriesHeads(ctx context.Context) (_ []models.Task, err error) {
+	defer repo.observe("GetSeriesHeads", time.Now(), &err)
+
+	return repo.next.GetSeriesHeads(ctx)
+}
+
+func (repo *InstrumentedTaskRepository) AddNextOccurrence(ctx context.Context, currentID string, next *models.Task) (err error) {
+	defer repo.observe("AddNextOccurrence", time.Now(), &err)
+
+	return repo.next.AddNextOccurrence(ctx, currentID, next)
+}
+
+func (repo *InstrumentedTaskRepository) GetDueReminders(ctx context.Context, now time.Time) (_ []models.Task, err error) {
+	defer repo.observe("GetDueReminders", time.Now(), &err)
+
+	return repo.next.GetDueReminders(ctx, now)
+}
+
+func (repo *InstrumentedTaskRepository) AdvanceReminder(ctx context.Context, id string, due, sentAt time.Time) (err error) {
+	defer repo.observe("AdvanceReminder", time.Now(), &err)
+
+	return repo.next.AdvanceReminder(ctx, id, due, sentAt)
+}
+
+func (repo *InstrumentedTaskRepository) GetOverdue(ctx context.Context, now time.Time) (_ []models.Task, err error) {
+	defer repo.observe("GetOverdue", time.Now(), &err)
+
+	return repo.next.GetOverdue(ctx, now)
+}
+
+func (repo *InstrumentedTaskRepository) observe(method string, start time.Time, err *error) {
+	repo.metrics.observeRepository(repo.backend, method, start, *err)
+}
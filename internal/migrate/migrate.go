@@ -0,0 +1,390 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// lockID keeps instances starting at once from applying the same migration.
+const lockID int64 = 7_326_117_026
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+var ErrNoDownMigration = errors.New("no down migration")
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type Status struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+func (s Status) Applied() bool {
+	return s.AppliedAt != nil
+}
+
+type Runner struct {
+	db         *pgxpool.Pool
+	migrations []Migration
+}
+
+func NewRunner(db *pgxpool.Pool, source fs.FS) (*Runner, error) {
+	migrations, err := LoadMigrations(source)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Runner{
+		db:         db,
+		migrations: migrations,
+	}, nil
+}
+
+func LoadMigrations(source fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(source, ".")
+	if err != nil {
+		return nil, fmt.Errorf("error reading migrations: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+
+	for _, entry := range entries {
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing version of %s: %w", entry.Name(), err)
+		}
+
+		content, err := fs.ReadFile(source, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", entry.Name(), err)
+		}
+
+		migration, found := byVersion[version]
+		if !found {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has conflicting names %q and %q", version, migration.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", migration.Version, migration.Name)
+		}
+
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+func (r *Runner) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+
+	err := r.withLock(ctx, func(conn *pgxpool.Conn) error {
+		versions, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range r.migrations {
+			if _, done := versions[migration.Version]; done {
+				continue
+			}
+
+			err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+				if _, err := tx.Exec(ctx, migration.Up); err != nil {
+					return err
+				}
+
+				_, err := tx.Exec(ctx, `INSERT INTO schema_versions (version, name) VALUES ($1, $2)`, migration.Version, migration.Name)
+
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("error applying migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+
+			applied = append(applied, migration)
+		}
+
+		return nil
+	})
+
+	return applied, err
+}
+
+func (r *Runner) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var reverted []Migration
+
+	err := r.withLock(ctx, func(conn *pgxpool.Conn) error {
+		versions, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(r.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := r.migrations[i]
+
+			if _, done := versions[migration.Version]; !done {
+				continue
+			}
+
+			if migration.Down == "" {
+				return fmt.Errorf("error reverting migration %d_%s: %w", migration.Version, migration.Name, ErrNoDownMigration)
+			}
+
+			err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+				if _, err := tx.Exec(ctx, migration.Down); err != nil {
+					return err
+				}
+
+				_, err := tx.Exec(ctx, `DELETE FROM schema_versions WHERE version=$1`, migration.Version)
+
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("error reverting migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+
+			reverted = append(reverted, migration)
+		}
+
+		return nil
+	})
+
+	return reverted, err
+}
+
+func (r *Runner) Status(ctx context.Context) ([]Status, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error acquiring connection: %w", err)
+	}
+	defer conn.Release()
+
+	if err := r.ensureSchemaTable(ctx, conn); err != nil {
+		return nil, err
+	}
+
+	versions, err := appliedVersions(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(r.migrations))
+
+	for _, migration := range r.migrations {
+		status := Status{Version: migration.Version, Name: migration.Name}
+
+		if appliedAt, done := versions[migration.Version]; done {
+			status.AppliedAt = &appliedAt
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// Pending, unlike Status, never writes to the database, so health checks can call it.
+func (r *Runner) Pending(ctx context.Context) (int, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error acquiring connection: %w", err)
+	}
+	defer conn.Release()
+
+	var exists bool
+
+	if err := conn.QueryRow(ctx, `SELECT to_regclass('schema_versions') IS NOT NULL`).Scan(&exists); err != nil {
+		return 0, fmt.Errorf("error checking for schema_versions table: %w", err)
+	}
+
+	var versions map[int64]time.Time
+
+	if exists {
+		versions, err = appliedVersions(ctx, conn)
+	} else {
+		versions, err = r.legacyVersions(ctx, conn)
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	pending := 0
+
+	for _, migration := range r.migrations {
+		if _, done := versions[migration.Version]; !done {
+			pending++
+		}
+	}
+
+	return pending, nil
+}
+
+func (r *Runner) legacyVersions(ctx context.Context, conn *pgxpool.Conn) (map[int64]time.Time, error) {
+	versions := make(map[int64]time.Time)
+
+	var exists bool
+
+	if err := conn.QueryRow(ctx, `SELECT to_regclass('schema_migrations') IS NOT NULL`).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("error checking for legacy schema_migrations table: %w", err)
+	}
+
+	if !exists {
+		return versions, nil
+	}
+
+	var legacyVersion int64
+
+	err := conn.QueryRow(ctx, `SELECT version FROM schema_migrations WHERE NOT dirty LIMIT 1`).Scan(&legacyVersion)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return versions, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error reading legacy schema_migrations table: %w", err)
+	}
+
+	for _, migration := range r.migrations {
+		if migration.Version <= legacyVersion {
+			versions[migration.Version] = time.Time{}
+		}
+	}
+
+	return versions, nil
+}
+
+func (r *Runner) withLock(ctx context.Context, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("error acquiring connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, lockID); err != nil {
+		return fmt.Errorf("error acquiring migration lock: %w", err)
+	}
+
+	defer func() {
+		_, _ = conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, lockID)
+	}()
+
+	if err := r.ensureSchemaTable(ctx, conn); err != nil {
+		return err
+	}
+
+	return fn(conn)
+}
+
+// Databases migrated by the golang-migrate CLI are adopted by marking every version up
+// to its recorded one as applied.
+func (r *Runner) ensureSchemaTable(ctx context.Context, conn *pgxpool.Conn) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS schema_versions (
+			version BIGINT PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		)`
+
+	if _, err := conn.Exec(ctx, query); err != nil {
+		return fmt.Errorf("error creating schema_versions table: %w", err)
+	}
+
+	var adopt bool
+
+	query = `SELECT to_regclass('schema_migrations') IS NOT NULL AND NOT EXISTS (SELECT 1 FROM schema_versions)`
+
+	if err := conn.QueryRow(ctx, query).Scan(&adopt); err != nil {
+		return fmt.Errorf("error checking for legacy schema_migrations table: %w", err)
+	}
+
+	if !adopt {
+		return nil
+	}
+
+	versions, err := r.legacyVersions(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range r.migrations {
+		if _, done := versions[migration.Version]; !done {
+			continue
+		}
+
+		query = `INSERT INTO schema_versions (version, name) VALUES ($1, $2)`
+
+		if _, err := conn.Exec(ctx, query, migration.Version, migration.Name); err != nil {
+			return fmt.Errorf("error adopting legacy schema_migrations table: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func appliedVersions(ctx context.Context, conn *pgxpool.Conn) (map[int64]time.Time, error) {
+	rows, err := conn.Query(ctx, `SELECT version, applied_at FROM schema_versions`)
+	if err != nil {
+		return nil, fmt.Errorf("error reading applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	versions := make(map[int64]time.Time)
+
+	for rows.Next() {
+		var (
+			version   int64
+			appliedAt time.Time
+		)
+
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("error scanning applied migration: %w", err)
+		}
+
+		versions[version] = appliedAt.UTC()
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating applied migrations: %w", err)
+	}
+
+	return versions, nil
+}
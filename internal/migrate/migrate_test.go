@@ -0,0 +1,85 @@
+package migrate
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"task-tracker/migrations"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	tests := map[string]struct {
+		files    fstest.MapFS
+		versions []int64
+		isError  bool
+	}{
+		"migrations are ordered by version": {
+			files: fstest.MapFS{
+				"000002_second.up.sql":   {Data: []byte("SELECT 2")},
+				"000002_second.down.sql": {Data: []byte("SELECT -2")},
+				"000001_first.up.sql":    {Data: []byte("SELECT 1")},
+				"000010_tenth.up.sql":    {Data: []byte("SELECT 10")},
+			},
+			versions: []int64{1, 2, 10},
+		},
+
+		"unrelated files are ignored": {
+			files: fstest.MapFS{
+				"000001_first.up.sql": {Data: []byte("SELECT 1")},
+				"migrations.go":       {Data: []byte("package migrations")},
+				"README.md":           {Data: []byte("docs")},
+			},
+			versions: []int64{1},
+		},
+
+		"down without up is rejected": {
+			files: fstest.MapFS{
+				"000001_first.down.sql": {Data: []byte("SELECT 1")},
+			},
+			isError: true,
+		},
+
+		"conflicting names are rejected": {
+			files: fstest.MapFS{
+				"000001_first.up.sql":   {Data: []byte("SELECT 1")},
+				"000001_other.down.sql": {Data: []byte("SELECT 1")},
+			},
+			isError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			loaded, err := LoadMigrations(test.files)
+
+			if (err != nil) != test.isError {
+				t.Fatalf("test-case: (%q); returned error %v; expected error: %v", name, err, test.isError)
+			}
+
+			if len(loaded) != len(test.versions) {
+				t.Fatalf("test-case: (%q); returned %d migrations; expected %d", name, len(loaded), len(test.versions))
+			}
+
+			for i, migration := range loaded {
+				if migration.Version != test.versions[i] {
+					t.Fatalf("test-case: (%q); migration %d has version %d; expected %d", name, i, migration.Version, test.versions[i])
+				}
+			}
+		})
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	loaded, err := LoadMigrations(migrations.FS)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, migration := range loaded {
+		if migration.Down == "" {
+			t.Fatalf("migration %d_%s has no down file", migration.Version, migration.Name)
+		}
+	}
+}
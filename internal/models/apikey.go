@@ -0,0 +1,73 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+type Scope string
+
+const (
+	ScopeRead  Scope = "read"
+	ScopeWrite Scope = "write"
+	// ScopeMaintain writes like a maintainer, to any task rather than the key's own.
+	ScopeMaintain Scope = "maintain"
+	ScopeAdmin    Scope = "admin"
+)
+
+func (s Scope) Valid() bool {
+	return s == ScopeRead || s == ScopeWrite || s == ScopeMaintain || s == ScopeAdmin
+}
+
+// Admin includes maintain, which includes write, which includes read.
+func (s Scope) Includes(other Scope) bool {
+	rank := map[Scope]int{ScopeRead: 1, ScopeWrite: 2, ScopeMaintain: 3, ScopeAdmin: 4}
+
+	return rank[s] >= rank[other] && rank[other] > 0
+}
+
+type APIKey struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Hash       string     `json:"-"`
+	Scopes     []Scope    `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+func (k *APIKey) Revoked() bool {
+	return k.RevokedAt != nil
+}
+
+func GrantsScope(scopes []Scope, want Scope) bool {
+	return slices.ContainsFunc(scopes, func(s Scope) bool { return s.Includes(want) })
+}
+
+type CreateAPIKeyRequest struct {
+	Name   string  `json:"name"`
+	Scopes []Scope `json:"scopes"`
+}
+
+type CreateAPIKeyResponse struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+func (r *CreateAPIKeyRequest) Validate() error {
+	if r.Name == "" {
+		return ErrNameIsEmpty
+	}
+
+	if len(r.Scopes) == 0 {
+		return ErrScopesAreEmpty
+	}
+
+	for _, scope := range r.Scopes {
+		if !scope.Valid() {
+			return ErrInvalidScope
+		}
+	}
+
+	return nil
+}
@@ -10,6 +10,11 @@ type Error struct {
 	StatusCode int
 }
 
+type ErrorResponse struct {
+	Error     string `json:"error"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
 func (e Error) Error() string {
 	return e.Err.Error()
 }
@@ -29,6 +34,43 @@ var (
 	ErrTitleIsEmpty       = NewError("title field is empty", http.StatusBadRequest)
 	ErrDescriptionIsEmpty = NewError("description field is empty", http.StatusBadRequest)
 	ErrStatusIsEmpty      = NewError("status field is empty", http.StatusBadRequest)
+	ErrDueAtIsEmpty       = NewError("due_at field is empty", http.StatusBadRequest)
+	ErrInvalidRRule       = NewError("rrule must be an hourly or less frequent RFC 5545 recurrence rule without DTSTART", http.StatusBadRequest)
+	ErrInvalidTimeZone    = NewError("time_zone must be an IANA time zone name", http.StatusBadRequest)
+	ErrInvalidReminder    = NewError("reminders must be at most 10 non-negative durations before due_at, e.g. \"1h30m\"", http.StatusBadRequest)
+	ErrInvalidLabel       = NewError("labels must be at most 20 non-empty strings of up to 50 bytes without commas or surrounding spaces", http.StatusBadRequest)
+	ErrInvalidTaskID      = NewError("id must be a UUID", http.StatusBadRequest)
+	ErrTaskNotRecurring   = NewError("task isn't an occurrence of a recurring series", http.StatusBadRequest)
+	ErrSeriesAdvanced     = NewError("next occurrence of the series already exists", http.StatusConflict)
+	ErrReminderSent       = NewError("reminder was already sent", http.StatusConflict)
+
+	ErrNameIsEmpty    = NewError("name field is empty", http.StatusBadRequest)
+	ErrScopesAreEmpty = NewError("scopes field is empty", http.StatusBadRequest)
+	ErrInvalidScope   = NewError("scope must be one of read, write, maintain, admin", http.StatusBadRequest)
+
+	ErrWorkspaceExists    = NewError("workspace already exists", http.StatusConflict)
+	ErrWorkspaceNotFound  = NewError("workspace not found", http.StatusNotFound)
+	ErrInvalidWorkspaceID = NewError("workspace id must be 1-63 lowercase letters, digits or dashes", http.StatusBadRequest)
+	ErrInvalidRole        = NewError("role must be one of viewer, member, maintainer, admin", http.StatusBadRequest)
+	ErrMemberNotFound     = NewError("workspace member not found", http.StatusNotFound)
+
+	// Authentication errors.
+	ErrUnauthorized   = NewError("missing or invalid credentials", http.StatusUnauthorized)
+	ErrForbidden      = NewError("insufficient permissions", http.StatusForbidden)
+	ErrNotAMember     = NewError("not a member of this workspace", http.StatusForbidden)
+	ErrAPIKeyNotFound = NewError("api key not found", http.StatusNotFound)
+
+	ErrFeedTokenNotFound = NewError("feed token not found", http.StatusNotFound)
+	ErrInvalidComponent  = NewError("component must be one of event, todo", http.StatusBadRequest)
+
+	ErrInvalidCalendarData = NewError("body must be an iCalendar object with one VTODO and its UID", http.StatusBadRequest)
+	ErrInvalidCalendarName = NewError("calendar object names must end in .ics and be at most 255 bytes", http.StatusBadRequest)
+	ErrCalendarUIDExists   = NewError("another task has the same calendar UID", http.StatusConflict)
+	ErrPreconditionFailed  = NewError("resource doesn't match If-Match or If-None-Match", http.StatusPreconditionFailed)
+
+	ErrInvalidFormat = NewError("format must be one of csv, json, ndjson, todotxt", http.StatusBadRequest)
+
+	ErrTooManyRequests = NewError("rate limit exceeded", http.StatusTooManyRequests)
 
 	ErrMethodNotAllowed  = NewError("method not allowed", http.StatusBadRequest)
 	ErrBadRequest        = NewError("invalid request body", http.StatusBadRequest)
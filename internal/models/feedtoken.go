@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+type FeedToken struct {
+	ID         string     `json:"id"`
+	Subject    string     `json:"subject"`
+	Hash       string     `json:"-"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+func (t *FeedToken) Revoked() bool {
+	return t.RevokedAt != nil
+}
+
+type CreateFeedTokenResponse struct {
+	FeedToken
+	Token string `json:"token"`
+}
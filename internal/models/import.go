@@ -0,0 +1,37 @@
+package models
+
+import "github.com/google/uuid"
+
+// Exports can be imported again, their read-only fields such as created_at are ignored.
+type ImportTaskRequest struct {
+	ID string `json:"id"`
+	CreateTaskRequest
+}
+
+func (r *ImportTaskRequest) Validate() error {
+	if r.ID != "" {
+		if _, err := uuid.Parse(r.ID); err != nil {
+			return ErrInvalidTaskID
+		}
+	}
+
+	return r.CreateTaskRequest.Validate()
+}
+
+// In a dry run, Created and Updated count what the import would have done.
+type ImportReport struct {
+	DryRun  bool          `json:"dry_run"`
+	Rows    int           `json:"rows"`
+	Created int           `json:"created"`
+	Updated int           `json:"updated"`
+	Failed  int           `json:"failed"`
+	Errors  []ImportError `json:"errors,omitempty"`
+}
+
+// Rows are counted from 1 without the CSV header. Row 0 means the input itself was
+// unreadable past the rows before.
+type ImportError struct {
+	Row   int    `json:"row"`
+	ID    string `json:"id,omitempty"`
+	Error string `json:"error"`
+}
@@ -0,0 +1,47 @@
+package models
+
+type Role string
+
+const (
+	RoleViewer     Role = "viewer"
+	RoleMember     Role = "member"
+	RoleMaintainer Role = "maintainer"
+	RoleAdmin      Role = "admin"
+)
+
+var roleRanks = map[Role]int{RoleViewer: 1, RoleMember: 2, RoleMaintainer: 3, RoleAdmin: 4}
+
+func (r Role) Valid() bool {
+	return roleRanks[r] > 0
+}
+
+func (r Role) AtLeast(other Role) bool {
+	return roleRanks[r] >= roleRanks[other] && r.Valid()
+}
+
+// HighestRole is RoleViewer if none of the roles is known.
+func HighestRole(roles []string) Role {
+	highest := RoleViewer
+
+	for _, name := range roles {
+		if role := Role(name); role.Valid() && role.AtLeast(highest) {
+			highest = role
+		}
+	}
+
+	return highest
+}
+
+// Writing to tasks others created takes the maintain scope.
+func RoleForScopes(scopes []Scope) Role {
+	switch {
+	case GrantsScope(scopes, ScopeAdmin):
+		return RoleAdmin
+	case GrantsScope(scopes, ScopeMaintain):
+		return RoleMaintainer
+	case GrantsScope(scopes, ScopeWrite):
+		return RoleMember
+	default:
+		return RoleViewer
+	}
+}
@@ -1,24 +1,142 @@
 package models
 
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Statuses with a meaning to the server. Any other status is accepted as well.
+const (
+	StatusTodo     = "todo"
+	StatusDone     = "done"
+	StatusCanceled = "canceled"
+	StatusSkipped  = "skipped"
+)
+
+// Finishing a task lets a recurring series move on to its next occurrence and silences
+// reminders.
+func TerminalStatuses() []string {
+	return []string{StatusDone, StatusCanceled, StatusSkipped}
+}
+
+func TerminalStatus(status string) bool {
+	return slices.Contains(TerminalStatuses(), status)
+}
+
 type Task struct {
-	ID          string `json:"id"`
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	Status      string `json:"status"`
-	CreatedAt   string `json:"created_at"`
-	UpdatedAt   string `json:"updated_at"`
+	ID          string    `json:"id"`
+	WorkspaceID string    `json:"workspace_id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Status      string    `json:"status"`
+	CreatedBy   string    `json:"created_by,omitempty"`
+	Assignee    string    `json:"assignee,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	DueAt *time.Time `json:"due_at,omitempty"`
+	// ClearDueAt makes an update remove DueAt when it's nil instead of keeping it.
+	ClearDueAt bool `json:"-"`
+	// RRule and TimeZone make the task an occurrence of a recurring series, see
+	// recurrence.Parse. Every occurrence of a series shares its SeriesID.
+	RRule    string `json:"rrule,omitempty"`
+	TimeZone string `json:"time_zone,omitempty"`
+	SeriesID string `json:"series_id,omitempty"`
+	// OccurrenceAt is the time the rule scheduled this occurrence for, which stays
+	// put when its due date is rescheduled.
+	OccurrenceAt *time.Time `json:"occurrence_at,omitempty"`
+	// Advanced is set once the next occurrence of the series has been added.
+	Advanced bool `json:"-"`
+
+	// Reminders notify the assignee, or the creator of an unassigned task, the given
+	// durations before DueAt. Occurrences of a series inherit them.
+	Reminders []Duration `json:"reminders,omitempty"`
+	// RemindedAt is when the last reminder was sent. Only reminders due later fire,
+	// so moving the due date later brings the passed ones back.
+	RemindedAt     *time.Time `json:"reminded_at,omitempty"`
+	NextReminderAt *time.Time `json:"next_reminder_at,omitempty"`
+
+	Labels []string `json:"labels,omitempty"`
+
+	// CalendarUID and CalendarName are the UID and resource name a CalDAV client
+	// created the task with, kept so the client finds it again. Other tasks use
+	// their ID for both.
+	CalendarUID  string `json:"calendar_uid,omitempty"`
+	CalendarName string `json:"calendar_name,omitempty"`
+}
+
+func (t *Task) Recurring() bool {
+	return t.SeriesID != ""
+}
+
+// Finished tasks and tasks without a due date get no reminder.
+func (t *Task) ScheduleReminders() {
+	t.NextReminderAt = nil
+
+	if t.DueAt == nil || TerminalStatus(t.Status) {
+		return
+	}
+
+	for _, before := range t.Reminders {
+		at := t.DueAt.Add(-time.Duration(before)).UTC()
+
+		if t.RemindedAt != nil && !at.After(*t.RemindedAt) {
+			continue
+		}
+
+		if t.NextReminderAt == nil || at.Before(*t.NextReminderAt) {
+			t.NextReminderAt = &at
+		}
+	}
+}
+
+// MarshalJSON always renders timestamps in UTC, whatever location they were loaded in.
+func (t Task) MarshalJSON() ([]byte, error) {
+	type task Task
+
+	utc := task(t)
+	utc.CreatedAt = t.CreatedAt.UTC()
+	utc.UpdatedAt = t.UpdatedAt.UTC()
+	utc.DueAt = utcPtr(t.DueAt)
+	utc.OccurrenceAt = utcPtr(t.OccurrenceAt)
+	utc.RemindedAt = utcPtr(t.RemindedAt)
+	utc.NextReminderAt = utcPtr(t.NextReminderAt)
+
+	return json.Marshal(utc)
+}
+
+func utcPtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	utc := t.UTC()
+
+	return &utc
 }
 
 type CreateTaskRequest struct {
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	Status      string `json:"status"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Status      string     `json:"status"`
+	Assignee    string     `json:"assignee"`
+	DueAt       *time.Time `json:"due_at"`
+	RRule       string     `json:"rrule"`
+	TimeZone    string     `json:"time_zone"`
+	Reminders   []Duration `json:"reminders"`
+	Labels      []string   `json:"labels"`
 }
 
 type UpdateTaskRequest struct {
 	Title       string `json:"title"`
 	Description string `json:"description"`
 	Status      string `json:"status"`
+	Assignee    string `json:"assignee"`
+	// Reminders and Labels replace the current ones, which are kept when omitted.
+	Reminders []Duration `json:"reminders"`
+	Labels    []string   `json:"labels"`
 }
 
 func (r *CreateTaskRequest) Validate() error {
@@ -34,7 +152,15 @@ func (r *CreateTaskRequest) Validate() error {
 		return ErrStatusIsEmpty
 	}
 
-	return nil
+	if (r.RRule != "" || len(r.Reminders) > 0) && r.DueAt == nil {
+		return ErrDueAtIsEmpty
+	}
+
+	if err := validateReminders(r.Reminders); err != nil {
+		return err
+	}
+
+	return ValidateLabels(r.Labels)
 }
 
 func (r *CreateTaskRequest) ConvertToTask() *Task {
@@ -42,6 +168,12 @@ func (r *CreateTaskRequest) ConvertToTask() *Task {
 		Title:       r.Title,
 		Description: r.Description,
 		Status:      r.Status,
+		Assignee:    r.Assignee,
+		DueAt:       r.DueAt,
+		RRule:       r.RRule,
+		TimeZone:    r.TimeZone,
+		Reminders:   r.Reminders,
+		Labels:      r.Labels,
 	}
 }
 
@@ -58,7 +190,11 @@ func (r *UpdateTaskRequest) Validate() error {
 		return ErrStatusIsEmpty
 	}
 
-	return nil
+	if err := validateReminders(r.Reminders); err != nil {
+		return err
+	}
+
+	return ValidateLabels(r.Labels)
 }
 
 func (r *UpdateTaskRequest) ConvertToTask(id string) *Task {
@@ -67,5 +203,76 @@ func (r *UpdateTaskRequest) ConvertToTask(id string) *Task {
 		Title:       r.Title,
 		Description: r.Description,
 		Status:      r.Status,
+		Assignee:    r.Assignee,
+		Reminders:   r.Reminders,
+		Labels:      r.Labels,
+	}
+}
+
+const maxReminders = 10
+
+func validateReminders(reminders []Duration) error {
+	if len(reminders) > maxReminders {
+		return ErrInvalidReminder
 	}
+
+	for _, before := range reminders {
+		if before < 0 {
+			return ErrInvalidReminder
+		}
+	}
+
+	return nil
+}
+
+const (
+	maxLabels      = 20
+	maxLabelLength = 50
+)
+
+// ValidateLabels rejects labels with surrounding spaces or commas, which separate
+// labels in filters and calendar categories.
+func ValidateLabels(labels []string) error {
+	if len(labels) > maxLabels {
+		return ErrInvalidLabel
+	}
+
+	for _, label := range labels {
+		if label == "" || label != strings.TrimSpace(label) || len(label) > maxLabelLength || strings.Contains(label, ",") {
+			return ErrInvalidLabel
+		}
+	}
+
+	return nil
+}
+
+type RescheduleTaskRequest struct {
+	DueAt *time.Time `json:"due_at"`
+}
+
+func (r *RescheduleTaskRequest) Validate() error {
+	if r.DueAt == nil {
+		return ErrDueAtIsEmpty
+	}
+
+	return nil
+}
+
+// An empty TaskFilter field accepts every task.
+type TaskFilter struct {
+	Assignees []string
+	Labels    []string
+	Statuses  []string
+}
+
+func (f TaskFilter) Match(task Task) bool {
+	if len(f.Assignees) > 0 && !slices.Contains(f.Assignees, task.Assignee) {
+		return false
+	}
+
+	if len(f.Labels) > 0 && !slices.ContainsFunc(task.Labels, func(label string) bool { return slices.Contains(f.Labels, label) }) {
+		return false
+	}
+
+	return len(f.Statuses) == 0 || slices.Contains(f.Statuses, task.Status)
 }
@@ -0,0 +1,161 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTask_MarshalJSON(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+
+	tests := map[string]struct {
+		task     Task
+		expected string
+	}{
+		"utc timestamps are kept": {
+			task: Task{
+				CreatedAt: time.Date(2025, time.April, 9, 8, 21, 41, 935898000, time.UTC),
+				UpdatedAt: time.Date(2025, time.April, 9, 8, 21, 41, 935898000, time.UTC),
+			},
+			expected: `"created_at":"2025-04-09T08:21:41.935898Z","updated_at":"2025-04-09T08:21:41.935898Z"`,
+		},
+
+		"local timestamps are converted to utc": {
+			task: Task{
+				CreatedAt: time.Date(2025, time.April, 9, 11, 21, 41, 0, moscow),
+				UpdatedAt: time.Date(2025, time.April, 9, 12, 0, 0, 0, moscow),
+			},
+			expected: `"created_at":"2025-04-09T08:21:41Z","updated_at":"2025-04-09T09:00:00Z"`,
+		},
+
+		"reminders are duration strings": {
+			task: Task{
+				DueAt:     ptr(time.Date(2025, time.April, 9, 15, 0, 0, 0, moscow)),
+				Reminders: []Duration{Duration(90 * time.Minute), Duration(24 * time.Hour)},
+			},
+			expected: `"due_at":"2025-04-09T12:00:00Z","reminders":["1h30m0s","24h0m0s"]`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := json.Marshal(test.task)
+			if err != nil {
+				t.Fatalf("test-case: (%q); unexpected error: %v", name, err)
+			}
+
+			if !strings.Contains(string(data), test.expected) {
+				t.Fatalf("test-case: (%q); returned %s; expected to contain %s", name, data, test.expected)
+			}
+		})
+	}
+}
+
+func TestTask_ScheduleReminders(t *testing.T) {
+	due := time.Date(2025, time.April, 9, 12, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		task     Task
+		expected *time.Time
+	}{
+		"earliest reminder": {
+			task:     Task{DueAt: &due, Reminders: []Duration{Duration(time.Hour), Duration(24 * time.Hour)}},
+			expected: ptr(due.Add(-24 * time.Hour)),
+		},
+
+		"after the last sent": {
+			task: Task{DueAt: &due, Reminders: []Duration{Duration(time.Hour), Duration(24 * time.Hour), 0},
+				RemindedAt: ptr(due.Add(-2 * time.Hour))},
+			expected: ptr(due.Add(-time.Hour)),
+		},
+
+		"all sent": {
+			task: Task{DueAt: &due, Reminders: []Duration{Duration(time.Hour)}, RemindedAt: ptr(due.Add(-time.Hour))},
+		},
+
+		"no due date": {
+			task: Task{Reminders: []Duration{Duration(time.Hour)}},
+		},
+
+		"done": {
+			task: Task{DueAt: &due, Status: StatusDone, Reminders: []Duration{Duration(time.Hour)}},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			test.task.ScheduleReminders()
+
+			next := test.task.NextReminderAt
+			if (next == nil) != (test.expected == nil) || next != nil && !next.Equal(*test.expected) {
+				t.Fatalf("test-case: (%q); returned %v; expected %v", name, next, test.expected)
+			}
+		})
+	}
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
+
+func TestTaskFilter_Match(t *testing.T) {
+	task := Task{Assignee: "alice", Status: StatusTodo, Labels: []string{"ops", "urgent"}}
+
+	tests := map[string]struct {
+		filter   TaskFilter
+		expected bool
+	}{
+		"empty":             {TaskFilter{}, true},
+		"assignee":          {TaskFilter{Assignees: []string{"bob", "alice"}}, true},
+		"other assignee":    {TaskFilter{Assignees: []string{"bob"}}, false},
+		"any label":         {TaskFilter{Labels: []string{"dev", "urgent"}}, true},
+		"no label":          {TaskFilter{Labels: []string{"dev"}}, false},
+		"status":            {TaskFilter{Statuses: []string{StatusTodo}}, true},
+		"every field":       {TaskFilter{Assignees: []string{"alice"}, Labels: []string{"ops"}, Statuses: []string{StatusTodo}}, true},
+		"one field differs": {TaskFilter{Assignees: []string{"alice"}, Labels: []string{"ops"}, Statuses: []string{StatusDone}}, false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if matched := test.filter.Match(task); matched != test.expected {
+				t.Fatalf("test-case: (%q); returned %v; expected %v", name, matched, test.expected)
+			}
+		})
+	}
+}
+
+func TestCreateTaskRequest_ValidateLabels(t *testing.T) {
+	tests := map[string]struct {
+		labels   []string
+		expected error
+	}{
+		"none":     {nil, nil},
+		"valid":    {[]string{"ops", "needs review"}, nil},
+		"empty":    {[]string{""}, ErrInvalidLabel},
+		"padded":   {[]string{" ops"}, ErrInvalidLabel},
+		"comma":    {[]string{"a,b"}, ErrInvalidLabel},
+		"too long": {[]string{strings.Repeat("a", maxLabelLength+1)}, ErrInvalidLabel},
+		"too many": {make([]string, maxLabels+1), ErrInvalidLabel},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			request := CreateTaskRequest{Title: "title", Description: "description", Status: StatusTodo, Labels: test.labels}
+
+			if err := request.Validate(); !errors.Is(err, test.expected) {
+				t.Fatalf("test-case: (%q); returned %v; expected %v", name, err, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"regexp"
+	"time"
+)
+
+var workspaceIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}$`)
+
+type Workspace struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type WorkspaceMember struct {
+	WorkspaceID string `json:"workspace_id"`
+	Subject     string `json:"subject"`
+	Role        Role   `json:"role"`
+}
+
+type CreateWorkspaceRequest struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type SetMemberRequest struct {
+	Role Role `json:"role"`
+}
+
+func (r *CreateWorkspaceRequest) Validate() error {
+	if !workspaceIDPattern.MatchString(r.ID) {
+		return ErrInvalidWorkspaceID
+	}
+
+	if r.Name == "" {
+		return ErrNameIsEmpty
+	}
+
+	return nil
+}
+
+func (r *SetMemberRequest) Validate() error {
+	if !r.Role.Valid() {
+		return ErrInvalidRole
+	}
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package notify
+
+import (
+	"context"
+	"log/slog"
+)
+
+type LogNotifier struct {
+	logger *slog.Logger
+}
+
+func NewLogNotifier(logger *slog.Logger) *LogNotifier {
+	return &LogNotifier{
+		logger: logger,
+	}
+}
+
+func (n *LogNotifier) Notify(ctx context.Context, notification Notification) error {
+	n.logger.LogAttrs(ctx, slog.LevelInfo, "Notification",
+		slog.String("kind", notification.Kind),
+		slog.String("recipient", notification.Recipient),
+		slog.String("subject", notification.Subject),
+		slog.Int("tasks", len(notification.Tasks)),
+	)
+
+	return nil
+}
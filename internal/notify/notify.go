@@ -0,0 +1,28 @@
+package notify
+
+import (
+	"context"
+	"time"
+
+	"task-tracker/internal/models"
+)
+
+// Kinds of notifications.
+const (
+	KindReminder = "reminder"
+	KindDigest   = "digest"
+)
+
+type Notification struct {
+	Kind string `json:"kind"`
+	// Recipient is the subject of a user as in auth.Principal.
+	Recipient string        `json:"recipient"`
+	Subject   string        `json:"subject"`
+	Body      string        `json:"body"`
+	Tasks     []models.Task `json:"tasks"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, notification Notification) error
+}
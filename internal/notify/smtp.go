@@ -0,0 +1,177 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+type SMTPConfig struct {
+	// Addr is the host:port of the mail server. STARTTLS is used when it offers it.
+	Addr     string
+	From     string
+	Username string
+	Password string
+	// Domain completes recipients that aren't email addresses, e.g. "alice" becomes
+	// "alice@<Domain>". Without it, such recipients can't be reached.
+	Domain string
+}
+
+const smtpTimeout = 30 * time.Second
+
+type SMTPNotifier struct {
+	config SMTPConfig
+	auth   smtp.Auth
+}
+
+func NewSMTPNotifier(config SMTPConfig) *SMTPNotifier {
+	n := &SMTPNotifier{
+		config: config,
+	}
+
+	if config.Username != "" {
+		host, _, _ := net.SplitHostPort(config.Addr)
+		n.auth = smtp.PlainAuth("", config.Username, config.Password, host)
+	}
+
+	return n
+}
+
+func (n *SMTPNotifier) Notify(ctx context.Context, notification Notification) error {
+	to, err := n.address(notification.Recipient)
+	if err != nil {
+		return err
+	}
+
+	from, err := mail.ParseAddress(n.config.From)
+	if err != nil {
+		return fmt.Errorf("invalid sender %q: %w", n.config.From, err)
+	}
+
+	msg := message(from, to, notification)
+
+	if err := n.deliver(ctx, from.Address, to.Address, msg); err != nil {
+		return fmt.Errorf("error sending email to %s: %w", to.Address, err)
+	}
+
+	return nil
+}
+
+func (n *SMTPNotifier) deliver(ctx context.Context, from, to string, msg []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, smtpTimeout)
+	defer cancel()
+
+	var dialer net.Dialer
+
+	conn, err := dialer.DialContext(ctx, "tcp", n.config.Addr)
+	if err != nil {
+		return err
+	}
+
+	deadline, _ := ctx.Deadline()
+	_ = conn.SetDeadline(deadline)
+
+	host, _, _ := net.SplitHostPort(n.config.Addr)
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+
+	if n.auth != nil {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return errors.New("server doesn't support AUTH")
+		}
+
+		if err := client.Auth(n.auth); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+func (n *SMTPNotifier) address(recipient string) (*mail.Address, error) {
+	if recipient == "" {
+		return nil, errors.New("notification has no recipient")
+	}
+
+	if !strings.Contains(recipient, "@") {
+		if n.config.Domain == "" {
+			return nil, fmt.Errorf("recipient %q isn't an email address", recipient)
+		}
+
+		recipient += "@" + n.config.Domain
+	}
+
+	address, err := mail.ParseAddress(recipient)
+	if err != nil {
+		return nil, fmt.Errorf("recipient %q isn't an email address: %w", recipient, err)
+	}
+
+	return address, nil
+}
+
+func message(from, to *mail.Address, notification Notification) []byte {
+	var b bytes.Buffer
+
+	header := func(name, value string) {
+		fmt.Fprintf(&b, "%s: %s\r\n", name, value)
+	}
+
+	date := notification.CreatedAt
+	if date.IsZero() {
+		date = time.Now()
+	}
+
+	header("From", from.String())
+	header("To", to.String())
+	header("Subject", mime.QEncoding.Encode("utf-8", notification.Subject))
+	header("Date", date.Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=utf-8")
+	header("Content-Transfer-Encoding", "8bit")
+	b.WriteString("\r\n")
+
+	body := strings.ReplaceAll(notification.Body, "\r\n", "\n")
+	b.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+
+	return b.Bytes()
+}
@@ -0,0 +1,193 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeSMTPServer accepts one message per connection and records the envelope and
+// data, enough of RFC 5321 for net/smtp.
+type fakeSMTPServer struct {
+	listener net.Listener
+	messages chan smtpMessage
+}
+
+type smtpMessage struct {
+	from string
+	to   []string
+	data string
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	server := &fakeSMTPServer{listener: listener, messages: make(chan smtpMessage, 10)}
+
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go server.serve(conn)
+		}
+	}()
+
+	return server
+}
+
+func (s *fakeSMTPServer) serve(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { fmt.Fprintf(conn, "%s\r\n", line) }
+
+	var message smtpMessage
+
+	reply("220 localhost ESMTP")
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		command := strings.TrimRight(line, "\r\n")
+
+		switch verb := strings.ToUpper(strings.SplitN(command, " ", 2)[0]); verb {
+		case "EHLO", "HELO":
+			reply("250-localhost")
+			reply("250 8BITMIME")
+		case "MAIL":
+			message.from = strings.Fields(strings.TrimPrefix(command, "MAIL FROM:"))[0]
+			reply("250 OK")
+		case "RCPT":
+			message.to = append(message.to, strings.TrimPrefix(command, "RCPT TO:"))
+			reply("250 OK")
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+
+			var data strings.Builder
+
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+
+				if line == ".\r\n" {
+					break
+				}
+
+				data.WriteString(line)
+			}
+
+			message.data = data.String()
+			s.messages <- message
+			reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+func TestSMTPNotifier_Notify(t *testing.T) {
+	server := newFakeSMTPServer(t)
+
+	notifier := NewSMTPNotifier(SMTPConfig{
+		Addr:   server.listener.Addr().String(),
+		From:   "Task Tracker <tasks@example.com>",
+		Domain: "example.com",
+	})
+
+	notification := Notification{
+		Kind:      KindReminder,
+		Recipient: "alice",
+		Subject:   "Reminder: Fix Ölheizung is due",
+		Body:      "Fix Ölheizung\n\nDue: tomorrow\n",
+		CreatedAt: time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC),
+	}
+
+	if err := notifier.Notify(context.Background(), notification); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	message := <-server.messages
+
+	if message.from != "<tasks@example.com>" || len(message.to) != 1 || message.to[0] != "<alice@example.com>" {
+		t.Fatalf("returned envelope %v -> %v; expected <tasks@example.com> -> [<alice@example.com>]", message.from, message.to)
+	}
+
+	for _, expected := range []string{
+		"From: \"Task Tracker\" <tasks@example.com>\r\n",
+		"To: <alice@example.com>\r\n",
+		"Subject: =?utf-8?q?Reminder:_Fix_=C3=96lheizung_is_due?=\r\n",
+		"Date: Mon, 04 Mar 2024 08:00:00 +0000\r\n",
+		"\r\n\r\nFix Ölheizung\r\n\r\nDue: tomorrow\r\n",
+	} {
+		if !strings.Contains(message.data, expected) {
+			t.Fatalf("returned %q; expected to contain %q", message.data, expected)
+		}
+	}
+}
+
+func TestSMTPNotifier_Address(t *testing.T) {
+	tests := map[string]struct {
+		domain        string
+		recipient     string
+		expected      string
+		expectedError bool
+	}{
+		"email address": {
+			recipient: "bob@example.org",
+			expected:  "bob@example.org",
+		},
+
+		"subject with domain": {
+			domain:    "example.com",
+			recipient: "bob",
+			expected:  "bob@example.com",
+		},
+
+		"subject without domain": {
+			recipient:     "bob",
+			expectedError: true,
+		},
+
+		"empty": {
+			domain:        "example.com",
+			expectedError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			notifier := NewSMTPNotifier(SMTPConfig{Addr: "localhost:25", Domain: test.domain})
+
+			address, err := notifier.address(test.recipient)
+			if (err != nil) != test.expectedError {
+				t.Fatalf("test-case: (%q); returned %v; expected error %v", name, err, test.expectedError)
+			}
+
+			if err == nil && address.Address != test.expected {
+				t.Fatalf("test-case: (%q); returned %q; expected %q", name, address.Address, test.expected)
+			}
+		})
+	}
+}
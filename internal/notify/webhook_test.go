@@ -0,0 +1,67 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWebhookNotifier_Notify(t *testing.T) {
+	tests := map[string]struct {
+		secret        string
+		status        int
+		expectedError bool
+	}{
+		"signed": {
+			secret: "s3cret",
+			status: http.StatusNoContent,
+		},
+
+		"unsigned": {
+			status: http.StatusOK,
+		},
+
+		"rejected": {
+			secret:        "s3cret",
+			status:        http.StatusBadGateway,
+			expectedError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var received Notification
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+
+				signature := r.Header.Get(SignatureHeader)
+				if test.secret == "" && signature != "" || test.secret != "" && signature != "sha256="+Sign(test.secret, body) {
+					t.Errorf("test-case: (%q); returned signature %q; expected the HMAC of the body", name, signature)
+				}
+
+				if err := json.Unmarshal(body, &received); err != nil {
+					t.Errorf("test-case: (%q); unexpected error: %v", name, err)
+				}
+
+				w.WriteHeader(test.status)
+			}))
+			defer server.Close()
+
+			err := NewWebhookNotifier(server.URL, test.secret).Notify(context.Background(),
+				Notification{Kind: KindDigest, Recipient: "alice", Subject: "2 overdue tasks"})
+			if (err != nil) != test.expectedError {
+				t.Fatalf("test-case: (%q); returned %v; expected error %v", name, err, test.expectedError)
+			}
+
+			if received.Kind != KindDigest || received.Recipient != "alice" {
+				t.Fatalf("test-case: (%q); returned %+v; expected the notification", name, received)
+			}
+		})
+	}
+}
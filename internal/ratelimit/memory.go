@@ -0,0 +1,67 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"task-tracker/internal/clock"
+)
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+type MemoryLimiter struct {
+	buckets map[string]*bucket
+	clock   clock.Clock
+	mu      sync.Mutex
+}
+
+func NewMemoryLimiter(clock clock.Clock) *MemoryLimiter {
+	return &MemoryLimiter{
+		buckets: make(map[string]*bucket),
+		clock:   clock,
+	}
+}
+
+func (l *MemoryLimiter) Allow(_ context.Context, key string, limit Limit) (Result, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.clock.Now()
+
+	b, found := l.buckets[key]
+	if !found {
+		b = &bucket{tokens: float64(limit.Requests), updatedAt: now}
+		l.buckets[key] = b
+	}
+
+	elapsed := math.Max(now.Sub(b.updatedAt).Seconds(), 0)
+	b.tokens = math.Min(float64(limit.Requests), b.tokens+elapsed*limit.rate())
+	b.updatedAt = now
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+
+	return result(allowed, b.tokens, limit), nil
+}
+
+func (l *MemoryLimiter) Cleanup(_ context.Context, idle time.Duration) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	cutoff := l.clock.Now().Add(-idle)
+
+	for key, b := range l.buckets {
+		if b.updatedAt.Before(cutoff) {
+			delete(l.buckets, key)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type manualClock struct {
+	now time.Time
+}
+
+func (c *manualClock) Now() time.Time {
+	return c.now
+}
+
+func (c *manualClock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func TestMemoryLimiter_Allow(t *testing.T) {
+	limit := Limit{Requests: 3, Window: 3 * time.Second}
+
+	tests := map[string]struct {
+		requests  int
+		wait      time.Duration
+		expected  Result
+		otherKeys bool
+	}{
+		"first request": {
+			requests: 1,
+			expected: Result{Allowed: true, Limit: 3, Remaining: 2, Reset: time.Second},
+		},
+
+		"burst up to the limit": {
+			requests: 3,
+			expected: Result{Allowed: true, Limit: 3, Remaining: 0, Reset: 3 * time.Second},
+		},
+
+		"over the limit": {
+			requests: 4,
+			expected: Result{Allowed: false, Limit: 3, Remaining: 0, Reset: 3 * time.Second, RetryAfter: time.Second},
+		},
+
+		"refilled after waiting": {
+			requests: 4,
+			wait:     time.Second,
+			expected: Result{Allowed: true, Limit: 3, Remaining: 0, Reset: 3 * time.Second},
+		},
+
+		"keys have separate buckets": {
+			requests:  4,
+			otherKeys: true,
+			expected:  Result{Allowed: true, Limit: 3, Remaining: 2, Reset: time.Second},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			clk := &manualClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+			limiter := NewMemoryLimiter(clk)
+
+			var res Result
+
+			for i := range test.requests {
+				key := "client"
+				if test.otherKeys {
+					key = string(rune('a' + i))
+				}
+
+				if i == test.requests-1 {
+					clk.advance(test.wait)
+				}
+
+				var err error
+
+				res, err = limiter.Allow(context.Background(), key, limit)
+				if err != nil {
+					t.Fatalf("test-case: (%q); unexpected error: %v", name, err)
+				}
+			}
+
+			if res != test.expected {
+				t.Fatalf("test-case: (%q); returned %+v; expected %+v", name, res, test.expected)
+			}
+		})
+	}
+}
+
+func TestMemoryLimiter_Cleanup(t *testing.T) {
+	clk := &manualClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	limiter := NewMemoryLimiter(clk)
+	limit := Limit{Requests: 1, Window: time.Minute}
+
+	for _, key := range []string{"idle", "active"} {
+		if _, err := limiter.Allow(context.Background(), key, limit); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		clk.advance(2 * time.Minute)
+	}
+
+	if err := limiter.Cleanup(context.Background(), 3*time.Minute); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, found := limiter.buckets["idle"]; found {
+		t.Fatalf("idle bucket wasn't removed")
+	}
+
+	if _, found := limiter.buckets["active"]; !found {
+		t.Fatalf("active bucket was removed")
+	}
+}
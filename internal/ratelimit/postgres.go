@@ -0,0 +1,75 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Each decision locks the bucket's row and uses the database clock, so instances can't
+// both spend the last token and don't need synchronized clocks.
+type PostgresLimiter struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresLimiter(db *pgxpool.Pool) *PostgresLimiter {
+	return &PostgresLimiter{
+		db: db,
+	}
+}
+
+func (l *PostgresLimiter) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	var res Result
+
+	err := pgx.BeginFunc(ctx, l.db, func(tx pgx.Tx) error {
+		insert := `INSERT INTO rate_limit_buckets (key, tokens, updated_at) VALUES ($1, $2, now())
+			ON CONFLICT (key) DO NOTHING`
+		if _, err := tx.Exec(ctx, insert, key, float64(limit.Requests)); err != nil {
+			return err
+		}
+
+		var tokens, elapsed float64
+
+		query := `SELECT tokens, EXTRACT(EPOCH FROM now() - updated_at)::float8 FROM rate_limit_buckets
+			WHERE key=$1 FOR UPDATE`
+		if err := tx.QueryRow(ctx, query, key).Scan(&tokens, &elapsed); err != nil {
+			return err
+		}
+
+		tokens = math.Min(float64(limit.Requests), tokens+math.Max(elapsed, 0)*limit.rate())
+
+		allowed := tokens >= 1
+		if allowed {
+			tokens--
+		}
+
+		update := `UPDATE rate_limit_buckets SET tokens=$1, updated_at=now() WHERE key=$2`
+		if _, err := tx.Exec(ctx, update, tokens, key); err != nil {
+			return err
+		}
+
+		res = result(allowed, tokens, limit)
+
+		return nil
+	})
+
+	if err != nil {
+		return Result{}, fmt.Errorf("error taking rate limit token: %v", err)
+	}
+
+	return res, nil
+}
+
+func (l *PostgresLimiter) Cleanup(ctx context.Context, idle time.Duration) error {
+	query := `DELETE FROM rate_limit_buckets WHERE updated_at < now() - $1::interval`
+
+	if _, err := l.db.Exec(ctx, query, idle); err != nil {
+		return fmt.Errorf("error cleaning up rate limit buckets: %v", err)
+	}
+
+	return nil
+}
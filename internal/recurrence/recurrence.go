@@ -0,0 +1,101 @@
+package recurrence
+
+import (
+	"strings"
+	"time"
+
+	"github.com/teambition/rrule-go"
+
+	"task-tracker/internal/clock"
+	"task-tracker/internal/models"
+)
+
+// maxCount bounds COUNT, whose occurrences are all expanded once by Bound.
+const maxCount = 1000
+
+// Rule is evaluated in a time zone, so occurrences keep their wall-clock time across
+// daylight saving changes.
+type Rule struct {
+	option   rrule.ROption
+	location *time.Location
+}
+
+// The series start comes from the task's due date, so DTSTART isn't accepted, and rules
+// more frequent than hourly are rejected.
+func Parse(value, timeZone string, clk clock.Clock) (Rule, error) {
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return Rule{}, models.ErrInvalidTimeZone
+	}
+
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	if value == "" || strings.ContainsAny(value, "\r\n") || strings.Contains(strings.ToUpper(value), "DTSTART") {
+		return Rule{}, models.ErrInvalidRRule
+	}
+
+	option, err := rrule.StrToROptionInLocation(value, location)
+	if err != nil {
+		return Rule{}, models.ErrInvalidRRule
+	}
+
+	if option.Freq > rrule.HOURLY || option.Interval < 0 || option.Count < 0 || option.Count > maxCount || (option.Count > 0 && !option.Until.IsZero()) {
+		return Rule{}, models.ErrInvalidRRule
+	}
+
+	rule := Rule{option: *option, location: location}
+
+	if _, err := rule.at(clk.Now()); err != nil {
+		return Rule{}, models.ErrInvalidRRule
+	}
+
+	return rule, nil
+}
+
+func (r Rule) String() string {
+	return r.option.RRuleString()
+}
+
+func (r Rule) Location() *time.Location {
+	return r.location
+}
+
+// Bound replaces COUNT with an UNTIL, as series are evaluated from their latest
+// occurrence, which would restart the count.
+func (r Rule) Bound(start time.Time) Rule {
+	if r.option.Count == 0 {
+		return r
+	}
+
+	rule, err := r.at(start)
+	if err != nil {
+		return r
+	}
+
+	until := start.Add(-time.Second)
+	if all := rule.All(); len(all) > 0 {
+		until = all[len(all)-1]
+	}
+
+	r.option.Count = 0
+	r.option.Until = until.UTC()
+
+	return r
+}
+
+func (r Rule) Next(occurrence, after time.Time) (time.Time, bool) {
+	rule, err := r.at(occurrence)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	next := rule.After(after, false)
+
+	return next.UTC(), !next.IsZero()
+}
+
+func (r Rule) at(start time.Time) (*rrule.RRule, error) {
+	option := r.option
+	option.Dtstart = start.In(r.location)
+
+	return rrule.NewRRule(option)
+}
@@ -0,0 +1,158 @@
+package recurrence
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"task-tracker/internal/clock"
+	"task-tracker/internal/models"
+)
+
+// parsedAt is when the rules of the tests are parsed.
+var parsedAt = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func TestParse(t *testing.T) {
+	tests := map[string]struct {
+		rule          string
+		timeZone      string
+		expectedRule  string
+		expectedError error
+	}{
+		"weekly": {
+			rule:         "FREQ=WEEKLY;BYDAY=MO,TH",
+			timeZone:     "Europe/Berlin",
+			expectedRule: "FREQ=WEEKLY;BYDAY=MO,TH",
+		},
+
+		"property name is accepted": {
+			rule:         "RRULE:FREQ=DAILY;INTERVAL=2",
+			expectedRule: "FREQ=DAILY;INTERVAL=2",
+		},
+
+		"unknown time zone": {
+			rule:          "FREQ=DAILY",
+			timeZone:      "Mars/Olympus_Mons",
+			expectedError: models.ErrInvalidTimeZone,
+		},
+
+		"garbage": {
+			rule:          "every monday",
+			expectedError: models.ErrInvalidRRule,
+		},
+
+		"dtstart": {
+			rule:          "DTSTART:20240101T090000Z\nRRULE:FREQ=DAILY",
+			expectedError: models.ErrInvalidRRule,
+		},
+
+		"too frequent": {
+			rule:          "FREQ=MINUTELY",
+			expectedError: models.ErrInvalidRRule,
+		},
+
+		"count and until": {
+			rule:          "FREQ=DAILY;COUNT=3;UNTIL=20240110T000000Z",
+			expectedError: models.ErrInvalidRRule,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			rule, err := Parse(test.rule, test.timeZone, clock.NewFixed(parsedAt))
+			if !errors.Is(err, test.expectedError) {
+				t.Fatalf("test-case: (%q); returned %v; expected %v", name, err, test.expectedError)
+			}
+
+			if err == nil && rule.String() != test.expectedRule {
+				t.Fatalf("test-case: (%q); returned %v; expected %v", name, rule.String(), test.expectedRule)
+			}
+		})
+	}
+}
+
+func TestRule_Next(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+
+	tests := map[string]struct {
+		rule       string
+		timeZone   string
+		occurrence time.Time
+		after      time.Time
+		expected   time.Time
+		ended      bool
+	}{
+		"next week": {
+			rule:       "FREQ=WEEKLY",
+			occurrence: time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC),
+			after:      time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC),
+			expected:   time.Date(2024, 3, 11, 9, 0, 0, 0, time.UTC),
+		},
+
+		"wall clock is kept across daylight saving time": {
+			rule:       "FREQ=WEEKLY",
+			timeZone:   "Europe/Berlin",
+			occurrence: time.Date(2024, 3, 25, 9, 0, 0, 0, berlin),
+			after:      time.Date(2024, 3, 25, 9, 0, 0, 0, berlin),
+			expected:   time.Date(2024, 4, 1, 9, 0, 0, 0, berlin),
+		},
+
+		"missed occurrences are skipped": {
+			rule:       "FREQ=DAILY",
+			occurrence: time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC),
+			after:      time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC),
+			expected:   time.Date(2024, 3, 11, 9, 0, 0, 0, time.UTC),
+		},
+
+		"ended": {
+			rule:       "FREQ=DAILY;UNTIL=20240305T090000Z",
+			occurrence: time.Date(2024, 3, 5, 9, 0, 0, 0, time.UTC),
+			after:      time.Date(2024, 3, 5, 9, 0, 0, 0, time.UTC),
+			ended:      true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			rule, err := Parse(test.rule, test.timeZone, clock.NewFixed(parsedAt))
+			if err != nil {
+				t.Fatalf("test-case: (%q); unexpected error: %v", name, err)
+			}
+
+			next, ok := rule.Next(test.occurrence, test.after)
+			if ok == test.ended {
+				t.Fatalf("test-case: (%q); returned %v; expected the series to end: %v", name, next, test.ended)
+			}
+
+			if ok && !next.Equal(test.expected) {
+				t.Fatalf("test-case: (%q); returned %v; expected %v", name, next, test.expected)
+			}
+		})
+	}
+}
+
+func TestRule_Bound(t *testing.T) {
+	rule, err := Parse("FREQ=DAILY;COUNT=3", "", clock.NewFixed(parsedAt))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	start := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	bound := rule.Bound(start)
+
+	if expected := "FREQ=DAILY;UNTIL=20240306T090000Z"; bound.String() != expected {
+		t.Fatalf("returned %v; expected %v", bound.String(), expected)
+	}
+
+	// The third occurrence is the last one, even when evaluated from the second.
+	if next, ok := bound.Next(start.AddDate(0, 0, 1), start.AddDate(0, 0, 2)); ok {
+		t.Fatalf("returned %v; expected the series to end", next)
+	}
+}
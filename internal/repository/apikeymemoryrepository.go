@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"task-tracker/internal/models"
+)
+
+type MemoryAPIKeyRepository struct {
+	store map[string]models.APIKey
+	mu    sync.Mutex
+}
+
+func NewMemoryAPIKeyRepository() *MemoryAPIKeyRepository {
+	return &MemoryAPIKeyRepository{
+		store: make(map[string]models.APIKey),
+	}
+}
+
+func (repo *MemoryAPIKeyRepository) Add(_ context.Context, key *models.APIKey) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	repo.store[key.ID] = cloneAPIKey(*key)
+
+	return nil
+}
+
+func (repo *MemoryAPIKeyRepository) Get(_ context.Context, id string) (models.APIKey, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	key, found := repo.store[id]
+	if !found {
+		return models.APIKey{}, models.ErrAPIKeyNotFound
+	}
+
+	return cloneAPIKey(key), nil
+}
+
+func (repo *MemoryAPIKeyRepository) GetAll(_ context.Context) ([]models.APIKey, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	keys := make([]models.APIKey, 0, len(repo.store))
+
+	for _, key := range repo.store {
+		keys = append(keys, cloneAPIKey(key))
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+	})
+
+	return keys, nil
+}
+
+func (repo *MemoryAPIKeyRepository) Revoke(_ context.Context, id string, at time.Time) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	key, found := repo.store[id]
+	if !found {
+		return models.ErrAPIKeyNotFound
+	}
+
+	if key.RevokedAt == nil {
+		key.RevokedAt = &at
+		repo.store[id] = key
+	}
+
+	return nil
+}
+
+func (repo *MemoryAPIKeyRepository) Touch(_ context.Context, id string, at time.Time) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	key, found := repo.store[id]
+	if !found {
+		return models.ErrAPIKeyNotFound
+	}
+
+	key.LastUsedAt = &at
+	repo.store[id] = key
+
+	return nil
+}
+
+func cloneAPIKey(key models.APIKey) models.APIKey {
+	key.Scopes = slices.Clone(key.Scopes)
+	return key
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"task-tracker/internal/models"
+)
+
+type PostgresAPIKeyRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresAPIKeyRepository(db *pgxpool.Pool) *PostgresAPIKeyRepository {
+	return &PostgresAPIKeyRepository{
+		db: db,
+	}
+}
+
+func (repo *PostgresAPIKeyRepository) Add(ctx context.Context, key *models.APIKey) error {
+	query := `INSERT INTO api_keys (id, name, hash, scopes, created_at) VALUES ($1, $2, $3, $4, $5)`
+	_, err := repo.db.Exec(ctx, query, key.ID, key.Name, key.Hash, scopesToStrings(key.Scopes), key.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("error adding api key: %v", err)
+	}
+
+	return nil
+}
+
+func (repo *PostgresAPIKeyRepository) Get(ctx context.Context, id string) (models.APIKey, error) {
+	query := `SELECT id, name, hash, scopes, created_at, last_used_at, revoked_at FROM api_keys WHERE id=$1`
+
+	key, err := scanAPIKey(repo.db.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.APIKey{}, models.ErrAPIKeyNotFound
+	}
+
+	if err != nil {
+		return models.APIKey{}, fmt.Errorf("error getting api key: %v", err)
+	}
+
+	return key, nil
+}
+
+func (repo *PostgresAPIKeyRepository) GetAll(ctx context.Context) ([]models.APIKey, error) {
+	query := `SELECT id, name, hash, scopes, created_at, last_used_at, revoked_at FROM api_keys ORDER BY created_at`
+	rows, err := repo.db.Query(ctx, query)
+
+	if err != nil {
+		return nil, fmt.Errorf("error getting api keys: %v", err)
+	}
+
+	defer rows.Close()
+
+	keys := []models.APIKey{}
+
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning row: %v", err)
+		}
+
+		keys = append(keys, key)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return keys, nil
+}
+
+func (repo *PostgresAPIKeyRepository) Revoke(ctx context.Context, id string, at time.Time) error {
+	query := `UPDATE api_keys SET revoked_at=COALESCE(revoked_at, $1) WHERE id=$2`
+
+	tag, err := repo.db.Exec(ctx, query, at, id)
+	if err != nil {
+		return fmt.Errorf("error revoking api key: %v", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return models.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+func (repo *PostgresAPIKeyRepository) Touch(ctx context.Context, id string, at time.Time) error {
+	query := `UPDATE api_keys SET last_used_at=$1 WHERE id=$2`
+
+	if _, err := repo.db.Exec(ctx, query, at, id); err != nil {
+		return fmt.Errorf("error updating api key last use: %v", err)
+	}
+
+	return nil
+}
+
+func scanAPIKey(row pgx.Row) (models.APIKey, error) {
+	var (
+		key    models.APIKey
+		scopes []string
+	)
+
+	err := row.Scan(&key.ID, &key.Name, &key.Hash, &scopes, &key.CreatedAt, &key.LastUsedAt, &key.RevokedAt)
+	if err != nil {
+		return models.APIKey{}, err
+	}
+
+	key.Scopes = make([]models.Scope, len(scopes))
+	for i, scope := range scopes {
+		key.Scopes[i] = models.Scope(scope)
+	}
+
+	key.CreatedAt = key.CreatedAt.UTC()
+
+	if key.LastUsedAt != nil {
+		*key.LastUsedAt = key.LastUsedAt.UTC()
+	}
+
+	if key.RevokedAt != nil {
+		*key.RevokedAt = key.RevokedAt.UTC()
+	}
+
+	return key, nil
+}
+
+func scopesToStrings(scopes []models.Scope) []string {
+	result := make([]string, len(scopes))
+	for i, scope := range scopes {
+		result[i] = string(scope)
+	}
+
+	return result
+}
@@ -0,0 +1,299 @@
+package repository
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"task-tracker/internal/events"
+	"task-tracker/internal/models"
+	"task-tracker/internal/tenant"
+)
+
+type CacheStats struct {
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+}
+
+type CachedTaskRepository struct {
+	next   TaskRepository
+	cache  *lruCache
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+func NewCachedTaskRepository(next TaskRepository, size int, ttl time.Duration) *CachedTaskRepository {
+	return &CachedTaskRepository{
+		next:  next,
+		cache: newLRUCache(size, ttl),
+	}
+}
+
+func (repo *CachedTaskRepository) Add(ctx context.Context, task *models.Task) error {
+	if err := repo.next.Add(ctx, task); err != nil {
+		return err
+	}
+
+	repo.cache.remove(allTasksKey(tenant.Workspace(ctx)))
+
+	return nil
+}
+
+func (repo *CachedTaskRepository) Delete(ctx context.Context, id string) error {
+	defer repo.invalidate(tenant.Workspace(ctx), id)
+
+	return repo.next.Delete(ctx, id)
+}
+
+// Exists skips the cache, a task deleted by another replica may still be cached.
+func (repo *CachedTaskRepository) Exists(ctx context.Context, id string) (bool, error) {
+	return repo.next.Exists(ctx, id)
+}
+
+func (repo *CachedTaskRepository) IDTaken(ctx context.Context, id string) (bool, error) {
+	return repo.next.IDTaken(ctx, id)
+}
+
+func (repo *CachedTaskRepository) Get(ctx context.Context, id string) (models.Task, error) {
+	if value, found := repo.cache.get(taskKey(tenant.Workspace(ctx), id)); found {
+		repo.hits.Add(1)
+		return cloneTask(value.(models.Task)), nil
+	}
+
+	repo.misses.Add(1)
+
+	generation := repo.cache.generation()
+
+	task, err := repo.next.Get(ctx, id)
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	repo.cache.put(taskKey(tenant.Workspace(ctx), id), cloneTask(task), generation)
+
+	return task, nil
+}
+
+func (repo *CachedTaskRepository) GetAll(ctx context.Context) ([]models.Task, error) {
+	if value, found := repo.cache.get(allTasksKey(tenant.Workspace(ctx))); found {
+		repo.hits.Add(1)
+		return cloneTasks(value.([]models.Task)), nil
+	}
+
+	repo.misses.Add(1)
+
+	generation := repo.cache.generation()
+
+	tasks, err := repo.next.GetAll(ctx)
+	if err != nil {
+		return tasks, err
+	}
+
+	repo.cache.put(allTasksKey(tenant.Workspace(ctx)), cloneTasks(tasks), generation)
+
+	return tasks, nil
+}
+
+// FindByCalendar is not cached: CalDAV clients look up the objects they change.
+func (repo *CachedTaskRepository) FindByCalendar(ctx context.Context, name, uid string) ([]models.Task, error) {
+	return repo.next.FindByCalendar(ctx, name, uid)
+}
+
+// Stream is not cached: it backs exports, which would evict everything else.
+func (repo *CachedTaskRepository) Stream(ctx context.Context, fn func(models.Task) error) error {
+	return repo.next.Stream(ctx, fn)
+}
+
+func (repo *CachedTaskRepository) Update(ctx context.Context, updatedTask *models.Task) error {
+	defer repo.invalidate(tenant.Workspace(ctx), updatedTask.ID)
+
+	return repo.next.Update(ctx, updatedTask)
+}
+
+// CountByStatus is not cached: it backs monitoring, which should see the real numbers.
+func (repo *CachedTaskRepository) CountByStatus(ctx context.Context) (map[string]int, error) {
+	return repo.next.CountByStatus(ctx)
+}
+
+// GetSeriesHeads is not cached: the scheduler must see the latest state of every series.
+func (repo *CachedTaskRepository) GetSeriesHeads(ctx context.Context) ([]models.Task, error) {
+	return repo.next.GetSeriesHeads(ctx)
+}
+
+func (repo *CachedTaskRepository) AddNextOccurrence(ctx context.Context, currentID string, next *models.Task) error {
+	defer repo.invalidate(tenant.Workspace(ctx), currentID)
+
+	return repo.next.AddNextOccurrence(ctx, currentID, next)
+}
+
+// GetDueReminders is not cached, like GetSeriesHeads.
+func (repo *CachedTaskRepository) GetDueReminders(ctx context.Context, now time.Time) ([]models.Task, error) {
+	return repo.next.GetDueReminders(ctx, now)
+}
+
+func (repo *CachedTaskRepository) AdvanceReminder(ctx context.Context, id string, due, sentAt time.Time) error {
+	defer repo.invalidate(tenant.Workspace(ctx), id)
+
+	return repo.next.AdvanceReminder(ctx, id, due, sentAt)
+}
+
+// GetOverdue is not cached, like GetSeriesHeads.
+func (repo *CachedTaskRepository) GetOverdue(ctx context.Context, now time.Time) ([]models.Task, error) {
+	return repo.next.GetOverdue(ctx, now)
+}
+
+func (repo *CachedTaskRepository) Ping(ctx context.Context) error {
+	return repo.next.Ping(ctx)
+}
+
+func (repo *CachedTaskRepository) Stats() CacheStats {
+	return CacheStats{
+		Hits:   repo.hits.Load(),
+		Misses: repo.misses.Load(),
+	}
+}
+
+func (repo *CachedTaskRepository) Watch(ctx context.Context, bus *events.Bus) {
+	changes, unsubscribe := bus.Subscribe(64)
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-changes:
+			workspace := event.WorkspaceID
+			if workspace == "" {
+				// Sent by a replica that predates workspaces.
+				workspace = tenant.DefaultWorkspace
+			}
+
+			repo.invalidate(workspace, event.TaskID)
+		}
+	}
+}
+
+func (repo *CachedTaskRepository) invalidate(workspace, id string) {
+	repo.cache.remove(taskKey(workspace, id))
+	repo.cache.remove(allTasksKey(workspace))
+}
+
+func taskKey(workspace, id string) string {
+	return "task:" + workspace + ":" + id
+}
+
+func allTasksKey(workspace string) string {
+	return "list:" + workspace
+}
+
+func cloneTasks(tasks []models.Task) []models.Task {
+	if tasks == nil {
+		return nil
+	}
+
+	clones := make([]models.Task, len(tasks))
+	for i, task := range tasks {
+		clones[i] = cloneTask(task)
+	}
+
+	return clones
+}
+
+type lruEntry struct {
+	key       string
+	value     any
+	expiresAt time.Time
+}
+
+// put skips values read before the last removal, they may predate the write that
+// caused it.
+type lruCache struct {
+	size    int
+	ttl     time.Duration
+	order   *list.List
+	entries map[string]*list.Element
+	removed uint64
+	mu      sync.Mutex
+}
+
+func newLRUCache(size int, ttl time.Duration) *lruCache {
+	return &lruCache{
+		size:    size,
+		ttl:     ttl,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+func (c *lruCache) get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, found := c.entries[key]
+	if !found {
+		return nil, false
+	}
+
+	entry := element.Value.(*lruEntry)
+
+	if time.Now().After(entry.expiresAt) {
+		c.order.Remove(element)
+		delete(c.entries, key)
+
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+
+	return entry.value, true
+}
+
+func (c *lruCache) generation() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.removed
+}
+
+func (c *lruCache) put(key string, value any, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.removed != generation {
+		return
+	}
+
+	expiresAt := time.Now().Add(c.ttl)
+
+	if element, found := c.entries[key]; found {
+		entry := element.Value.(*lruEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+
+		c.order.MoveToFront(element)
+
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+func (c *lruCache) remove(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.removed++
+
+	if element, found := c.entries[key]; found {
+		c.order.Remove(element)
+		delete(c.entries, key)
+	}
+}
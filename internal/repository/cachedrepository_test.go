@@ -0,0 +1,276 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"task-tracker/internal/clock"
+	"task-tracker/internal/events"
+	"task-tracker/internal/models"
+)
+
+func TestCachedRepository_Get(t *testing.T) {
+	tests := map[string]struct {
+		size     int
+		ttl      time.Duration
+		reads    []string
+		expected CacheStats
+	}{
+		"repeated reads are served from cache": {
+			size:     10,
+			ttl:      time.Minute,
+			reads:    []string{"task1", "task1", "task1"},
+			expected: CacheStats{Hits: 2, Misses: 1},
+		},
+
+		"least recently used entry is evicted": {
+			size:     1,
+			ttl:      time.Minute,
+			reads:    []string{"task1", "task2", "task1"},
+			expected: CacheStats{Hits: 0, Misses: 3},
+		},
+
+		"expired entries are reloaded": {
+			size:     10,
+			ttl:      time.Nanosecond,
+			reads:    []string{"task1", "task1"},
+			expected: CacheStats{Hits: 0, Misses: 2},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			memory := NewMemoryTaskRepository(clock.New())
+			memory.tasks(context.Background(), true)["task1"] = models.Task{ID: "task1", Title: "First task"}
+			memory.tasks(context.Background(), true)["task2"] = models.Task{ID: "task2", Title: "Second task"}
+
+			repo := NewCachedTaskRepository(memory, test.size, test.ttl)
+
+			for _, id := range test.reads {
+				task, err := repo.Get(context.Background(), id)
+				if err != nil || task.ID != id {
+					t.Fatalf("test-case: (%q); returned [%v %v]; expected task %q", name, task, err, id)
+				}
+
+				time.Sleep(time.Millisecond)
+			}
+
+			if stats := repo.Stats(); stats != test.expected {
+				t.Fatalf("test-case: (%q); returned %+v; expected %+v", name, stats, test.expected)
+			}
+		})
+	}
+}
+
+func TestCachedRepository_Invalidation(t *testing.T) {
+	tests := map[string]struct {
+		write func(repo *CachedTaskRepository) error
+		check func(tasks []models.Task) bool
+	}{
+		"add invalidates task list": {
+			write: func(repo *CachedTaskRepository) error {
+				return repo.Add(context.Background(), &models.Task{ID: "task2", Title: "Second task"})
+			},
+			check: func(tasks []models.Task) bool { return len(tasks) == 2 },
+		},
+
+		"update invalidates task list": {
+			write: func(repo *CachedTaskRepository) error {
+				return repo.Update(context.Background(), &models.Task{ID: "task1", Title: "Renamed"})
+			},
+			check: func(tasks []models.Task) bool { return len(tasks) == 1 && tasks[0].Title == "Renamed" },
+		},
+
+		"delete invalidates task list": {
+			write: func(repo *CachedTaskRepository) error {
+				return repo.Delete(context.Background(), "task1")
+			},
+			check: func(tasks []models.Task) bool { return len(tasks) == 0 },
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			memory := NewMemoryTaskRepository(clock.New())
+			memory.tasks(context.Background(), true)["task1"] = models.Task{ID: "task1", Title: "First task"}
+
+			repo := NewCachedTaskRepository(memory, 10, time.Minute)
+
+			if _, err := repo.GetAll(context.Background()); err != nil {
+				t.Fatalf("test-case: (%q); unexpected error: %v", name, err)
+			}
+
+			if err := test.write(repo); err != nil {
+				t.Fatalf("test-case: (%q); unexpected error: %v", name, err)
+			}
+
+			tasks, err := repo.GetAll(context.Background())
+			if err != nil || !test.check(tasks) {
+				t.Fatalf("test-case: (%q); returned stale tasks [%v %v]", name, tasks, err)
+			}
+		})
+	}
+}
+
+// racingRepository runs write while a read is in flight, after the read got its value.
+type racingRepository struct {
+	TaskRepository
+	write func()
+}
+
+func (repo *racingRepository) Get(ctx context.Context, id string) (models.Task, error) {
+	task, err := repo.TaskRepository.Get(ctx, id)
+	repo.write()
+
+	return task, err
+}
+
+func (repo *racingRepository) GetAll(ctx context.Context) ([]models.Task, error) {
+	tasks, err := repo.TaskRepository.GetAll(ctx)
+	repo.write()
+
+	return tasks, err
+}
+
+func TestCachedRepository_WriteDuringMiss(t *testing.T) {
+	tests := map[string]struct {
+		read func(repo *CachedTaskRepository) (string, error)
+	}{
+		"get": {
+			read: func(repo *CachedTaskRepository) (string, error) {
+				task, err := repo.Get(context.Background(), "task1")
+				return task.Title, err
+			},
+		},
+
+		"get all": {
+			read: func(repo *CachedTaskRepository) (string, error) {
+				tasks, err := repo.GetAll(context.Background())
+				if err != nil || len(tasks) != 1 {
+					return "", err
+				}
+
+				return tasks[0].Title, nil
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			memory := NewMemoryTaskRepository(clock.New())
+			memory.tasks(context.Background(), true)["task1"] = models.Task{ID: "task1", Title: "First task"}
+
+			var repo *CachedTaskRepository
+
+			racing := &racingRepository{TaskRepository: memory}
+			racing.write = func() {
+				racing.write = func() {}
+
+				if err := repo.Update(context.Background(), &models.Task{ID: "task1", Title: "Renamed"}); err != nil {
+					t.Errorf("test-case: (%q); unexpected error: %v", name, err)
+				}
+			}
+
+			repo = NewCachedTaskRepository(racing, 10, time.Minute)
+
+			if title, err := test.read(repo); err != nil || title != "First task" {
+				t.Fatalf("test-case: (%q); returned [%q %v]; expected %q", name, title, err, "First task")
+			}
+
+			if title, err := test.read(repo); err != nil || title != "Renamed" {
+				t.Fatalf("test-case: (%q); returned stale [%q %v]; expected %q", name, title, err, "Renamed")
+			}
+		})
+	}
+}
+
+func TestCachedRepository_Watch(t *testing.T) {
+	memory := NewMemoryTaskRepository(clock.New())
+	memory.tasks(context.Background(), true)["task1"] = models.Task{ID: "task1", Title: "First task"}
+
+	repo := NewCachedTaskRepository(memory, 10, time.Minute)
+	bus := events.NewBus()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go repo.Watch(ctx, bus)
+
+	if _, err := repo.Get(ctx, "task1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Simulate a write made by another replica.
+	memory.tasks(context.Background(), true)["task1"] = models.Task{ID: "task1", Title: "Renamed elsewhere"}
+
+	deadline := time.Now().Add(time.Second)
+
+	for time.Now().Before(deadline) {
+		bus.Publish(events.TaskEvent{Type: events.TaskUpdated, TaskID: "task1"})
+
+		task, err := repo.Get(ctx, "task1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if task.Title == "Renamed elsewhere" {
+			return
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("cache entry hasn't been invalidated by bus event")
+}
+
+func TestCachedRepository_Copies(t *testing.T) {
+	ctx := context.Background()
+	repo := NewCachedTaskRepository(NewMemoryTaskRepository(clock.New()), 10, time.Minute)
+
+	expected := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	due := expected
+
+	reminder := models.Duration(time.Hour)
+	task := &models.Task{ID: "task1", Title: "Title", DueAt: &due, Reminders: []models.Duration{reminder}}
+
+	if err := repo.Add(ctx, task); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	due = due.Add(time.Hour)
+	task.Reminders[0] = 0
+
+	// The first reads miss the cache, the second ones hit it.
+	for i := range 2 {
+		task, err := repo.Get(ctx, "task1")
+		if err != nil {
+			t.Fatalf("read %d: unexpected error: %v", i, err)
+		}
+
+		if !task.DueAt.Equal(expected) || task.Reminders[0] != reminder {
+			t.Fatalf("read %d: returned %+v; expected due at %v with reminder %v", i, task, expected, reminder)
+		}
+
+		*task.DueAt = task.DueAt.Add(time.Hour)
+		task.Reminders[0] = 0
+
+		tasks, err := repo.GetAll(ctx)
+		if err != nil {
+			t.Fatalf("read %d: unexpected error: %v", i, err)
+		}
+
+		if len(tasks) != 1 || !tasks[0].DueAt.Equal(expected) || tasks[0].Reminders[0] != reminder {
+			t.Fatalf("read %d: returned %+v; expected due at %v with reminder %v", i, tasks, expected, reminder)
+		}
+
+		*tasks[0].DueAt = tasks[0].DueAt.Add(time.Hour)
+		tasks[0].Reminders[0] = 0
+	}
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"task-tracker/internal/models"
+)
+
+type MemoryFeedTokenRepository struct {
+	store map[string]models.FeedToken
+	mu    sync.Mutex
+}
+
+func NewMemoryFeedTokenRepository() *MemoryFeedTokenRepository {
+	return &MemoryFeedTokenRepository{
+		store: make(map[string]models.FeedToken),
+	}
+}
+
+func (repo *MemoryFeedTokenRepository) Add(_ context.Context, token *models.FeedToken) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	repo.store[token.ID] = *token
+
+	return nil
+}
+
+func (repo *MemoryFeedTokenRepository) Get(_ context.Context, id string) (models.FeedToken, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	token, found := repo.store[id]
+	if !found {
+		return models.FeedToken{}, models.ErrFeedTokenNotFound
+	}
+
+	return token, nil
+}
+
+func (repo *MemoryFeedTokenRepository) GetAllForSubject(_ context.Context, subject string) ([]models.FeedToken, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	tokens := []models.FeedToken{}
+
+	for _, token := range repo.store {
+		if token.Subject == subject {
+			tokens = append(tokens, token)
+		}
+	}
+
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].CreatedAt.Before(tokens[j].CreatedAt)
+	})
+
+	return tokens, nil
+}
+
+func (repo *MemoryFeedTokenRepository) Revoke(_ context.Context, id string, at time.Time) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	token, found := repo.store[id]
+	if !found {
+		return models.ErrFeedTokenNotFound
+	}
+
+	if token.RevokedAt == nil {
+		token.RevokedAt = &at
+		repo.store[id] = token
+	}
+
+	return nil
+}
+
+func (repo *MemoryFeedTokenRepository) Touch(_ context.Context, id string, at time.Time) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	token, found := repo.store[id]
+	if !found {
+		return models.ErrFeedTokenNotFound
+	}
+
+	token.LastUsedAt = &at
+	repo.store[id] = token
+
+	return nil
+}
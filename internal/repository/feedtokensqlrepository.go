@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"task-tracker/internal/models"
+)
+
+type PostgresFeedTokenRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresFeedTokenRepository(db *pgxpool.Pool) *PostgresFeedTokenRepository {
+	return &PostgresFeedTokenRepository{
+		db: db,
+	}
+}
+
+func (repo *PostgresFeedTokenRepository) Add(ctx context.Context, token *models.FeedToken) error {
+	query := `INSERT INTO feed_tokens (id, subject, hash, created_at) VALUES ($1, $2, $3, $4)`
+
+	if _, err := repo.db.Exec(ctx, query, token.ID, token.Subject, token.Hash, token.CreatedAt); err != nil {
+		return fmt.Errorf("error adding feed token: %v", err)
+	}
+
+	return nil
+}
+
+func (repo *PostgresFeedTokenRepository) Get(ctx context.Context, id string) (models.FeedToken, error) {
+	query := `SELECT id, subject, hash, created_at, last_used_at, revoked_at FROM feed_tokens WHERE id=$1`
+
+	token, err := scanFeedToken(repo.db.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.FeedToken{}, models.ErrFeedTokenNotFound
+	}
+
+	if err != nil {
+		return models.FeedToken{}, fmt.Errorf("error getting feed token: %v", err)
+	}
+
+	return token, nil
+}
+
+func (repo *PostgresFeedTokenRepository) GetAllForSubject(ctx context.Context, subject string) ([]models.FeedToken, error) {
+	query := `SELECT id, subject, hash, created_at, last_used_at, revoked_at FROM feed_tokens WHERE subject=$1 ORDER BY created_at`
+
+	rows, err := repo.db.Query(ctx, query, subject)
+	if err != nil {
+		return nil, fmt.Errorf("error getting feed tokens: %v", err)
+	}
+
+	defer rows.Close()
+
+	tokens := []models.FeedToken{}
+
+	for rows.Next() {
+		token, err := scanFeedToken(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning row: %v", err)
+		}
+
+		tokens = append(tokens, token)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return tokens, nil
+}
+
+func (repo *PostgresFeedTokenRepository) Revoke(ctx context.Context, id string, at time.Time) error {
+	query := `UPDATE feed_tokens SET revoked_at=COALESCE(revoked_at, $1) WHERE id=$2`
+
+	tag, err := repo.db.Exec(ctx, query, at, id)
+	if err != nil {
+		return fmt.Errorf("error revoking feed token: %v", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return models.ErrFeedTokenNotFound
+	}
+
+	return nil
+}
+
+func (repo *PostgresFeedTokenRepository) Touch(ctx context.Context, id string, at time.Time) error {
+	query := `UPDATE feed_tokens SET last_used_at=$1 WHERE id=$2`
+
+	if _, err := repo.db.Exec(ctx, query, at, id); err != nil {
+		return fmt.Errorf("error updating feed token last use: %v", err)
+	}
+
+	return nil
+}
+
+func scanFeedToken(row pgx.Row) (models.FeedToken, error) {
+	var token models.FeedToken
+
+	err := row.Scan(&token.ID, &token.Subject, &token.Hash, &token.CreatedAt, &token.LastUsedAt, &token.RevokedAt)
+	if err != nil {
+		return models.FeedToken{}, err
+	}
+
+	token.CreatedAt = token.CreatedAt.UTC()
+
+	if token.LastUsedAt != nil {
+		*token.LastUsedAt = token.LastUsedAt.UTC()
+	}
+
+	if token.RevokedAt != nil {
+		*token.RevokedAt = token.RevokedAt.UTC()
+	}
+
+	return token, nil
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"task-tracker/internal/events"
+)
+
+const (
+	listenerMinBackoff = time.Second
+	listenerMaxBackoff = 30 * time.Second
+)
+
+type TaskChangeListener struct {
+	db     *pgxpool.Pool
+	bus    *events.Bus
+	logger *slog.Logger
+}
+
+func NewTaskChangeListener(db *pgxpool.Pool, bus *events.Bus, logger *slog.Logger) *TaskChangeListener {
+	return &TaskChangeListener{
+		db:     db,
+		bus:    bus,
+		logger: logger,
+	}
+}
+
+func (l *TaskChangeListener) Run(ctx context.Context) {
+	backoff := listenerMinBackoff
+
+	for {
+		err := l.listen(ctx, func() { backoff = listenerMinBackoff })
+		if ctx.Err() != nil {
+			return
+		}
+
+		l.logger.Warn("Task change listener failed, reconnecting",
+			slog.Duration("backoff", backoff),
+			slog.String("error", err.Error()),
+		)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff = min(backoff*2, listenerMaxBackoff)
+	}
+}
+
+func (l *TaskChangeListener) listen(ctx context.Context, onConnected func()) error {
+	conn, err := l.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("error acquiring connection: %w", err)
+	}
+
+	// The connection keeps its LISTEN registration, so it must never go back to the pool.
+	pgConn := conn.Hijack()
+	defer pgConn.Close(context.Background())
+
+	if _, err := pgConn.Exec(ctx, "LISTEN "+TaskChangesChannel); err != nil {
+		return fmt.Errorf("error subscribing to %s: %w", TaskChangesChannel, err)
+	}
+
+	onConnected()
+
+	for {
+		notification, err := pgConn.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("error waiting for notification: %w", err)
+		}
+
+		var event events.TaskEvent
+
+		if err := json.Unmarshal([]byte(notification.Payload), &event); err != nil {
+			l.logger.Warn("Ignoring malformed task change notification",
+				slog.String("payload", notification.Payload),
+				slog.String("error", err.Error()),
+			)
+
+			continue
+		}
+
+		l.bus.Publish(event)
+	}
+}
@@ -2,79 +2,165 @@ package repository
 
 import (
 	"context"
+	"slices"
+	"sort"
 	"sync"
 	"time"
 
+	"task-tracker/internal/clock"
 	"task-tracker/internal/models"
+	"task-tracker/internal/tenant"
 )
 
 type MemoryTaskRepository struct {
-	store map[string]models.Task
+	store map[string]map[string]models.Task
+	clock clock.Clock
 	mu    sync.Mutex
 }
 
-func NewMemoryTaskRepository() *MemoryTaskRepository {
+func NewMemoryTaskRepository(clock clock.Clock) *MemoryTaskRepository {
 	return &MemoryTaskRepository{
-		store: make(map[string]models.Task),
+		store: make(map[string]map[string]models.Task),
+		clock: clock,
 	}
 }
 
-func (repo *MemoryTaskRepository) Add(_ context.Context, task *models.Task) error {
+func (repo *MemoryTaskRepository) tasks(ctx context.Context, create bool) map[string]models.Task {
+	workspace := tenant.Workspace(ctx)
+
+	tasks, found := repo.store[workspace]
+	if !found && create {
+		tasks = make(map[string]models.Task)
+		repo.store[workspace] = tasks
+	}
+
+	return tasks
+}
+
+func (repo *MemoryTaskRepository) Add(ctx context.Context, task *models.Task) error {
 	repo.mu.Lock()
 	defer repo.mu.Unlock()
 
-	repo.store[task.ID] = *task
+	if repo.idTaken(task.ID) {
+		return models.ErrTaskExists
+	}
+
+	task.WorkspaceID = tenant.Workspace(ctx)
+	task.ScheduleReminders()
+
+	repo.tasks(ctx, true)[task.ID] = cloneTask(*task)
 
 	return nil
 }
 
-func (repo *MemoryTaskRepository) Delete(_ context.Context, id string) error {
+func (repo *MemoryTaskRepository) Delete(ctx context.Context, id string) error {
 	repo.mu.Lock()
 	defer repo.mu.Unlock()
 
-	delete(repo.store, id)
+	delete(repo.tasks(ctx, false), id)
 
 	return nil
 }
 
-func (repo *MemoryTaskRepository) Exists(_ context.Context, id string) (bool, error) {
+func (repo *MemoryTaskRepository) Exists(ctx context.Context, id string) (bool, error) {
 	repo.mu.Lock()
 	defer repo.mu.Unlock()
 
-	_, found := repo.store[id]
+	_, found := repo.tasks(ctx, false)[id]
 
 	return found, nil
 }
 
-func (repo *MemoryTaskRepository) Get(_ context.Context, id string) (models.Task, error) {
+func (repo *MemoryTaskRepository) IDTaken(_ context.Context, id string) (bool, error) {
 	repo.mu.Lock()
 	defer repo.mu.Unlock()
 
-	task := repo.store[id]
+	return repo.idTaken(id), nil
+}
+
+// idTaken checks every workspace, like the primary key of the tasks table does.
+func (repo *MemoryTaskRepository) idTaken(id string) bool {
+	for _, tasks := range repo.store {
+		if _, found := tasks[id]; found {
+			return true
+		}
+	}
 
-	return task, nil
+	return false
 }
 
-func (repo *MemoryTaskRepository) GetAll(_ context.Context) ([]models.Task, error) {
+func (repo *MemoryTaskRepository) Get(ctx context.Context, id string) (models.Task, error) {
 	repo.mu.Lock()
 	defer repo.mu.Unlock()
 
-	tasks := make([]models.Task, len(repo.store))
+	task := repo.tasks(ctx, false)[id]
+
+	return cloneTask(task), nil
+}
+
+func (repo *MemoryTaskRepository) GetAll(ctx context.Context) ([]models.Task, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	store := repo.tasks(ctx, false)
+	tasks := make([]models.Task, len(store))
 	i := 0
 
-	for _, task := range repo.store {
-		tasks[i] = task
+	for _, task := range store {
+		tasks[i] = cloneTask(task)
 		i++
 	}
 
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].CreatedAt.Before(tasks[j].CreatedAt)
+	})
+
 	return tasks, nil
 }
 
-func (repo *MemoryTaskRepository) Update(_ context.Context, updatedTask *models.Task) error {
+func (repo *MemoryTaskRepository) FindByCalendar(ctx context.Context, name, uid string) ([]models.Task, error) {
+	tasks, err := repo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []models.Task
+
+	for _, task := range tasks {
+		if (name != "" && task.CalendarName == name) || (uid != "" && task.CalendarUID == uid) {
+			found = append(found, task)
+		}
+	}
+
+	return found, nil
+}
+
+// Stream walks a copy of the workspace's tasks, so fn can use the repository.
+func (repo *MemoryTaskRepository) Stream(ctx context.Context, fn func(models.Task) error) error {
+	tasks, err := repo.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		if err := fn(task); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (repo *MemoryTaskRepository) Update(ctx context.Context, updatedTask *models.Task) error {
 	repo.mu.Lock()
 	defer repo.mu.Unlock()
 
-	task := repo.store[updatedTask.ID]
+	store := repo.tasks(ctx, false)
+
+	task, found := store[updatedTask.ID]
+	if !found {
+		return nil
+	}
 
 	updated := false
 
@@ -93,10 +179,168 @@ func (repo *MemoryTaskRepository) Update(_ context.Context, updatedTask *models.
 		updated = true
 	}
 
+	if updatedTask.Assignee != "" && updatedTask.Assignee != task.Assignee {
+		task.Assignee = updatedTask.Assignee
+		updated = true
+	}
+
+	// A nil due date clears it, as in Postgres.
+	if (updatedTask.DueAt == nil) != (task.DueAt == nil) || (task.DueAt != nil && !updatedTask.DueAt.Equal(*task.DueAt)) {
+		task.DueAt = cloneTime(updatedTask.DueAt)
+		updated = true
+	}
+
+	if updatedTask.Reminders != nil && !slices.Equal(updatedTask.Reminders, task.Reminders) {
+		task.Reminders = slices.Clone(updatedTask.Reminders)
+		updated = true
+	}
+
+	if updatedTask.Labels != nil && !slices.Equal(updatedTask.Labels, task.Labels) {
+		task.Labels = slices.Clone(updatedTask.Labels)
+		updated = true
+	}
+
 	if updated {
-		task.UpdatedAt = time.Now().Format(time.RFC3339Nano)
-		repo.store[updatedTask.ID] = task
+		task.UpdatedAt = repo.clock.Now()
+		task.ScheduleReminders()
+		store[updatedTask.ID] = task
+	}
+
+	return nil
+}
+
+func (repo *MemoryTaskRepository) CountByStatus(_ context.Context) (map[string]int, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	counts := make(map[string]int)
+
+	for _, tasks := range repo.store {
+		for _, task := range tasks {
+			counts[task.Status]++
+		}
+	}
+
+	return counts, nil
+}
+
+func (repo *MemoryTaskRepository) GetSeriesHeads(_ context.Context) ([]models.Task, error) {
+	return repo.filter(func(task models.Task) bool {
+		return task.Recurring() && !task.Advanced
+	}, func(task models.Task) time.Time {
+		return task.CreatedAt
+	}), nil
+}
+
+func (repo *MemoryTaskRepository) AddNextOccurrence(ctx context.Context, currentID string, next *models.Task) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	store := repo.tasks(ctx, false)
+
+	current, found := store[currentID]
+	if !found {
+		return models.ErrTaskNotFound
+	}
+
+	if current.Advanced {
+		return models.ErrSeriesAdvanced
+	}
+
+	current.Advanced = true
+	store[currentID] = current
+
+	if next != nil {
+		next.WorkspaceID = tenant.Workspace(ctx)
+		next.ScheduleReminders()
+
+		store[next.ID] = cloneTask(*next)
+	}
+
+	return nil
+}
+
+func (repo *MemoryTaskRepository) GetDueReminders(_ context.Context, now time.Time) ([]models.Task, error) {
+	return repo.filter(func(task models.Task) bool {
+		return task.NextReminderAt != nil && !task.NextReminderAt.After(now)
+	}, func(task models.Task) time.Time {
+		return *task.NextReminderAt
+	}), nil
+}
+
+func (repo *MemoryTaskRepository) AdvanceReminder(ctx context.Context, id string, due, sentAt time.Time) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	store := repo.tasks(ctx, false)
+
+	task, found := store[id]
+	if !found {
+		return models.ErrTaskNotFound
+	}
+
+	if task.NextReminderAt == nil || !task.NextReminderAt.Equal(due) {
+		return models.ErrReminderSent
 	}
 
+	task.RemindedAt = &sentAt
+	task.ScheduleReminders()
+	store[id] = task
+
 	return nil
 }
+
+func (repo *MemoryTaskRepository) GetOverdue(_ context.Context, now time.Time) ([]models.Task, error) {
+	return repo.filter(func(task models.Task) bool {
+		return task.DueAt != nil && task.DueAt.Before(now) && !models.TerminalStatus(task.Status)
+	}, func(task models.Task) time.Time {
+		return *task.DueAt
+	}), nil
+}
+
+func (repo *MemoryTaskRepository) filter(keep func(models.Task) bool, key func(models.Task) time.Time) []models.Task {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	var tasks []models.Task
+
+	for _, workspaceTasks := range repo.store {
+		for _, task := range workspaceTasks {
+			if keep(task) {
+				tasks = append(tasks, cloneTask(task))
+			}
+		}
+	}
+
+	sort.Slice(tasks, func(i, j int) bool {
+		return key(tasks[i]).Before(key(tasks[j]))
+	})
+
+	return tasks
+}
+
+func (repo *MemoryTaskRepository) Ping(_ context.Context) error {
+	return nil
+}
+
+// cloneTask copies the pointers and slices of task, so callers can't modify the stored task.
+func cloneTask(task models.Task) models.Task {
+	task.DueAt = cloneTime(task.DueAt)
+	task.OccurrenceAt = cloneTime(task.OccurrenceAt)
+	task.Reminders = slices.Clone(task.Reminders)
+	task.Labels = slices.Clone(task.Labels)
+	task.RemindedAt = cloneTime(task.RemindedAt)
+	task.NextReminderAt = cloneTime(task.NextReminderAt)
+
+	return task
+}
+
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	clone := *t
+
+	return &clone
+}
@@ -4,10 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"reflect"
+	"sort"
 	"testing"
 	"time"
 
+	"task-tracker/internal/clock"
 	"task-tracker/internal/models"
+	"task-tracker/internal/tenant"
 )
 
 type TestResultGet struct {
@@ -35,7 +39,7 @@ func TestStorage_Add(t *testing.T) {
 					Status:      "Todo",
 				},
 			},
-			storage: NewMemoryTaskRepository(),
+			storage: NewMemoryTaskRepository(clock.New()),
 			result:  []error{nil},
 		},
 
@@ -48,7 +52,7 @@ func TestStorage_Add(t *testing.T) {
 					Status:      "Todo",
 				},
 			},
-			storage: NewMemoryTaskRepository(),
+			storage: NewMemoryTaskRepository(clock.New()),
 			result:  []error{nil},
 		},
 
@@ -73,7 +77,7 @@ func TestStorage_Add(t *testing.T) {
 					Status:      "Todo",
 				},
 			},
-			storage: NewMemoryTaskRepository(),
+			storage: NewMemoryTaskRepository(clock.New()),
 			result:  []error{nil, nil, nil},
 		},
 	}
@@ -106,7 +110,7 @@ func TestStorage_Add(t *testing.T) {
 }
 
 func TestStorage_Get(t *testing.T) {
-	fixedTime := time.Now().Format(time.RFC3339Nano)
+	fixedTime := time.Now().UTC()
 
 	tests := map[string]struct {
 		inputIDs []string
@@ -116,7 +120,7 @@ func TestStorage_Get(t *testing.T) {
 		"get existing task": {
 			inputIDs: []string{"task1"},
 			storage: &MemoryTaskRepository{
-				store: map[string]models.Task{
+				store: inDefaultWorkspace(map[string]models.Task{
 					"task1": {
 						ID:          "task1",
 						Title:       "First task",
@@ -125,7 +129,7 @@ func TestStorage_Get(t *testing.T) {
 						CreatedAt:   fixedTime,
 						UpdatedAt:   fixedTime,
 					},
-				},
+				}),
 			},
 			result: TestResultGet{
 				resultTasks: []models.Task{{
@@ -143,7 +147,7 @@ func TestStorage_Get(t *testing.T) {
 		"get multiple tasks with duplicates": {
 			inputIDs: []string{"task1", "task2", "task3", "task1", "task2", "task3"},
 			storage: &MemoryTaskRepository{
-				store: map[string]models.Task{
+				store: inDefaultWorkspace(map[string]models.Task{
 					"task1": {
 						ID:          "task1",
 						Title:       "First task",
@@ -168,7 +172,7 @@ func TestStorage_Get(t *testing.T) {
 						CreatedAt:   fixedTime,
 						UpdatedAt:   fixedTime,
 					},
-				}},
+				})},
 			result: TestResultGet{
 				resultTasks: []models.Task{
 					{
@@ -234,8 +238,8 @@ func TestStorage_Get(t *testing.T) {
 				resultTask := test.result.resultTasks[i]
 				resultError := test.result.resultErrors[i]
 
-				if !errors.Is(err, resultError) || task != resultTask {
-					t.Fatalf("test-case: (%q); returned [%q %q]; expected [%q %q]", name, task, err, resultTask, resultError)
+				if !errors.Is(err, resultError) || !reflect.DeepEqual(task, resultTask) {
+					t.Fatalf("test-case: (%q); returned [%v %v]; expected [%v %v]", name, task, err, resultTask, resultError)
 				}
 			}
 		})
@@ -258,16 +262,17 @@ func TestStorage_Update(t *testing.T) {
 				},
 			},
 			storage: &MemoryTaskRepository{
-				store: map[string]models.Task{
+				clock: clock.New(),
+				store: inDefaultWorkspace(map[string]models.Task{
 					"task1": {
 						ID:          "task1",
 						Title:       "Title",
 						Description: "Description",
 						Status:      "Todo",
-						CreatedAt:   time.Now().Format(time.RFC3339Nano),
-						UpdatedAt:   time.Now().Format(time.RFC3339Nano),
+						CreatedAt:   time.Now().UTC(),
+						UpdatedAt:   time.Now().UTC(),
 					},
-				},
+				}),
 			},
 			result: []error{nil},
 		},
@@ -294,32 +299,33 @@ func TestStorage_Update(t *testing.T) {
 				},
 			},
 			storage: &MemoryTaskRepository{
-				store: map[string]models.Task{
+				clock: clock.New(),
+				store: inDefaultWorkspace(map[string]models.Task{
 					"task1": {
 						ID:          "task1",
 						Title:       "Title",
 						Description: "Description",
 						Status:      "Todo",
-						CreatedAt:   time.Now().Format(time.RFC3339Nano),
-						UpdatedAt:   time.Now().Format(time.RFC3339Nano),
+						CreatedAt:   time.Now().UTC(),
+						UpdatedAt:   time.Now().UTC(),
 					},
 					"task2": {
 						ID:          "task2",
 						Title:       "Title",
 						Description: "Description",
 						Status:      "Todo",
-						CreatedAt:   time.Now().Format(time.RFC3339Nano),
-						UpdatedAt:   time.Now().Format(time.RFC3339Nano),
+						CreatedAt:   time.Now().UTC(),
+						UpdatedAt:   time.Now().UTC(),
 					},
 					"task3": {
 						ID:          "task3",
 						Title:       "Title",
 						Description: "Description",
 						Status:      "Todo",
-						CreatedAt:   time.Now().Format(time.RFC3339Nano),
-						UpdatedAt:   time.Now().Format(time.RFC3339Nano),
+						CreatedAt:   time.Now().UTC(),
+						UpdatedAt:   time.Now().UTC(),
 					},
-				},
+				}),
 			},
 			result: []error{nil, nil, nil},
 		},
@@ -334,16 +340,17 @@ func TestStorage_Update(t *testing.T) {
 				},
 			},
 			storage: &MemoryTaskRepository{
-				store: map[string]models.Task{
+				clock: clock.New(),
+				store: inDefaultWorkspace(map[string]models.Task{
 					"task1": {
 						ID:          "task1",
 						Title:       "Title",
 						Description: "Description",
 						Status:      "Todo",
-						CreatedAt:   time.Now().Format(time.RFC3339Nano),
-						UpdatedAt:   time.Now().Format(time.RFC3339Nano),
+						CreatedAt:   time.Now().UTC(),
+						UpdatedAt:   time.Now().UTC(),
 					},
-				},
+				}),
 			},
 			result: []error{nil},
 		},
@@ -371,7 +378,7 @@ func TestStorage_Update(t *testing.T) {
 				}
 
 				if updatedTask.Description != task.Description {
-					t.Fatalf("test-case: (%q); task hasn't been updated; expected [%q]; got: [%q] ", name, task, updatedTask)
+					t.Fatalf("test-case: (%q); task hasn't been updated; expected [%v]; got: [%v] ", name, task, updatedTask)
 				}
 
 				fmt.Println(updatedTask)
@@ -380,6 +387,31 @@ func TestStorage_Update(t *testing.T) {
 	}
 }
 
+func TestStorage_UpdateTimestamp(t *testing.T) {
+	createdAt := time.Date(2025, time.April, 9, 8, 0, 0, 0, time.UTC)
+	updatedAt := createdAt.Add(time.Hour)
+
+	storage := NewMemoryTaskRepository(clock.NewFixed(updatedAt))
+	storage.tasks(context.Background(), true)["task1"] = models.Task{
+		ID:        "task1",
+		Title:     "Title",
+		Status:    "Todo",
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+
+	err := storage.Update(context.Background(), &models.Task{ID: "task1", Status: "Done"})
+	if err != nil {
+		t.Fatalf("unexpected error: %q", err)
+	}
+
+	task, _ := storage.Get(context.Background(), "task1")
+
+	if !task.CreatedAt.Equal(createdAt) || !task.UpdatedAt.Equal(updatedAt) {
+		t.Fatalf("returned [%v %v]; expected [%v %v]", task.CreatedAt, task.UpdatedAt, createdAt, updatedAt)
+	}
+}
+
 func TestStorage_Delete(t *testing.T) {
 	tests := map[string]struct {
 		inputIDs []string
@@ -389,16 +421,16 @@ func TestStorage_Delete(t *testing.T) {
 		"delete existing task": {
 			inputIDs: []string{"task1"},
 			storage: &MemoryTaskRepository{
-				store: map[string]models.Task{
+				store: inDefaultWorkspace(map[string]models.Task{
 					"task1": {
 						ID:          "task1",
 						Title:       "Title",
 						Description: "Description",
 						Status:      "Todo",
-						CreatedAt:   time.Now().Format(time.RFC3339Nano),
-						UpdatedAt:   time.Now().Format(time.RFC3339Nano),
+						CreatedAt:   time.Now().UTC(),
+						UpdatedAt:   time.Now().UTC(),
 					},
-				},
+				}),
 			},
 			result: []error{nil},
 		},
@@ -406,24 +438,24 @@ func TestStorage_Delete(t *testing.T) {
 		"delete multiple tasks": {
 			inputIDs: []string{"task1", "task2"},
 			storage: &MemoryTaskRepository{
-				store: map[string]models.Task{
+				store: inDefaultWorkspace(map[string]models.Task{
 					"task1": {
 						ID:          "task1",
 						Title:       "Title",
 						Description: "Description",
 						Status:      "Todo",
-						CreatedAt:   time.Now().Format(time.RFC3339Nano),
-						UpdatedAt:   time.Now().Format(time.RFC3339Nano),
+						CreatedAt:   time.Now().UTC(),
+						UpdatedAt:   time.Now().UTC(),
 					},
 					"task2": {
 						ID:          "task2",
 						Title:       "Title",
 						Description: "Description",
 						Status:      "Todo",
-						CreatedAt:   time.Now().Format(time.RFC3339Nano),
-						UpdatedAt:   time.Now().Format(time.RFC3339Nano),
+						CreatedAt:   time.Now().UTC(),
+						UpdatedAt:   time.Now().UTC(),
 					},
-				},
+				}),
 			},
 			result: []error{nil, nil},
 		},
@@ -457,7 +489,7 @@ func TestStorage_Delete(t *testing.T) {
 }
 
 func TestStorage_GetAll(t *testing.T) {
-	fixedTime := time.Now().Format(time.RFC3339Nano)
+	fixedTime := time.Now().UTC()
 
 	tests := map[string]struct {
 		storage *MemoryTaskRepository
@@ -465,7 +497,7 @@ func TestStorage_GetAll(t *testing.T) {
 	}{
 		"get all tasks when storage has multiple tasks": {
 			storage: &MemoryTaskRepository{
-				store: map[string]models.Task{
+				store: inDefaultWorkspace(map[string]models.Task{
 					"task1": {
 						ID:          "task1",
 						Title:       "Title",
@@ -490,7 +522,7 @@ func TestStorage_GetAll(t *testing.T) {
 						CreatedAt:   fixedTime,
 						UpdatedAt:   fixedTime,
 					},
-				},
+				}),
 			},
 			result: TestResultGetAll{
 				resultTasks: []models.Task{
@@ -524,7 +556,7 @@ func TestStorage_GetAll(t *testing.T) {
 		},
 
 		"get all tasks when storage is empty": {
-			storage: NewMemoryTaskRepository(),
+			storage: NewMemoryTaskRepository(clock.New()),
 			result: TestResultGetAll{
 				resultTasks: []models.Task{},
 				resultError: nil,
@@ -533,7 +565,7 @@ func TestStorage_GetAll(t *testing.T) {
 
 		"get all tasks when storage has one task": {
 			storage: &MemoryTaskRepository{
-				store: map[string]models.Task{
+				store: inDefaultWorkspace(map[string]models.Task{
 					"task1": {
 						ID:          "task1",
 						Title:       "Title",
@@ -542,7 +574,7 @@ func TestStorage_GetAll(t *testing.T) {
 						CreatedAt:   fixedTime,
 						UpdatedAt:   fixedTime,
 					},
-				},
+				}),
 			},
 			result: TestResultGetAll{
 				resultTasks: []models.Task{
@@ -578,10 +610,165 @@ func TestStorage_GetAll(t *testing.T) {
 
 			for _, expectedTask := range resultTasks {
 				actualTask, err := test.storage.Get(context.Background(), expectedTask.ID)
-				if err != nil || expectedTask != actualTask {
+				if err != nil || !reflect.DeepEqual(expectedTask, actualTask) {
 					t.Fatalf("test-case: %q; expected task %v, got %v", name, expectedTask, actualTask)
 				}
 			}
 		})
 	}
 }
+
+func inDefaultWorkspace(tasks map[string]models.Task) map[string]map[string]models.Task {
+	return map[string]map[string]models.Task{tenant.DefaultWorkspace: tasks}
+}
+
+func TestStorage_WorkspaceIsolation(t *testing.T) {
+	storage := NewMemoryTaskRepository(clock.New())
+
+	teamA := tenant.WithWorkspace(context.Background(), "team-a")
+	teamB := tenant.WithWorkspace(context.Background(), "team-b")
+
+	task := &models.Task{ID: "task1", Title: "Title", Description: "Description", Status: "Todo"}
+	if err := storage.Add(teamA, task); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if task.WorkspaceID != "team-a" {
+		t.Fatalf("workspace is %q; expected %q", task.WorkspaceID, "team-a")
+	}
+
+	if exists, _ := storage.Exists(teamB, "task1"); exists {
+		t.Fatal("task is visible in another workspace")
+	}
+
+	if tasks, _ := storage.GetAll(teamB); len(tasks) != 0 {
+		t.Fatalf("returned %v; expected no tasks in another workspace", tasks)
+	}
+
+	if err := storage.Update(teamB, &models.Task{ID: "task1", Title: "Hijacked"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := storage.Delete(teamB, "task1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, _ := storage.Get(teamA, "task1"); got.Title != "Title" {
+		t.Fatalf("returned %+v; expected the task to be untouched by another workspace", got)
+	}
+
+	// IDs are unique across workspaces, like the primary key of the tasks table.
+	if taken, _ := storage.IDTaken(teamB, "task1"); !taken {
+		t.Fatal("returned false; expected the id to be taken")
+	}
+
+	if err := storage.Add(teamB, &models.Task{ID: "task1", Title: "Copy"}); !errors.Is(err, models.ErrTaskExists) {
+		t.Fatalf("returned %v; expected %v", err, models.ErrTaskExists)
+	}
+}
+
+func TestStorage_AddNextOccurrence(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryTaskRepository(clock.New())
+
+	head := models.Task{ID: "head", Title: "Water plants", SeriesID: "head", RRule: "FREQ=DAILY"}
+	next := models.Task{ID: "next", Title: "Water plants", SeriesID: "head", RRule: "FREQ=DAILY"}
+
+	if err := repo.Add(ctx, &head); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := repo.AddNextOccurrence(ctx, head.ID, &next); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := repo.AddNextOccurrence(ctx, head.ID, &next); !errors.Is(err, models.ErrSeriesAdvanced) {
+		t.Fatalf("returned %v; expected %v", err, models.ErrSeriesAdvanced)
+	}
+
+	heads, err := repo.GetSeriesHeads(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(heads) != 1 || heads[0].ID != next.ID {
+		t.Fatalf("returned %v; expected only %v", heads, next.ID)
+	}
+}
+
+func TestStorage_AdvanceReminder(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryTaskRepository(clock.New())
+	due := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+
+	task := models.Task{ID: "task1", Title: "File taxes", DueAt: &due,
+		Reminders: []models.Duration{models.Duration(time.Hour), models.Duration(24 * time.Hour)}}
+	if err := repo.Add(ctx, &task); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	first := due.Add(-24 * time.Hour)
+
+	reminders, err := repo.GetDueReminders(ctx, first)
+	if err != nil || len(reminders) != 1 || !reminders[0].NextReminderAt.Equal(first) {
+		t.Fatalf("returned %v, %v; expected the reminder due at %v", reminders, err, first)
+	}
+
+	if err := repo.AdvanceReminder(ctx, task.ID, first, first); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := repo.AdvanceReminder(ctx, task.ID, first, first); !errors.Is(err, models.ErrReminderSent) {
+		t.Fatalf("returned %v; expected %v", err, models.ErrReminderSent)
+	}
+
+	stored, err := repo.Get(ctx, task.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !stored.RemindedAt.Equal(first) || !stored.NextReminderAt.Equal(due.Add(-time.Hour)) {
+		t.Fatalf("returned %v, %v; expected reminded at %v, next at %v", stored.RemindedAt, stored.NextReminderAt, first, due.Add(-time.Hour))
+	}
+}
+
+func TestStorage_FindByCalendar(t *testing.T) {
+	storage := NewMemoryTaskRepository(clock.New())
+	ctx := context.Background()
+
+	for _, task := range []*models.Task{
+		{ID: "task1", Title: "Synced", CalendarUID: "uid-1", CalendarName: "one.ics"},
+		{ID: "task2", Title: "Synced", CalendarUID: "uid-2", CalendarName: "two.ics"},
+		{ID: "task3", Title: "From the API"},
+	} {
+		if err := storage.Add(ctx, task); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	tests := map[string]struct {
+		name, uid string
+		expected  []string
+	}{
+		"by name":            {name: "one.ics", expected: []string{"task1"}},
+		"by uid":             {uid: "uid-2", expected: []string{"task2"}},
+		"name and uid":       {name: "one.ics", uid: "uid-2", expected: []string{"task1", "task2"}},
+		"empty matches none": {expected: nil},
+		"unknown":            {name: "three.ics", uid: "uid-3", expected: nil},
+	}
+
+	for name, test := range tests {
+		tasks, err := storage.FindByCalendar(ctx, test.name, test.uid)
+
+		var ids []string
+		for _, task := range tasks {
+			ids = append(ids, task.ID)
+		}
+
+		sort.Strings(ids)
+
+		if err != nil || !reflect.DeepEqual(ids, test.expected) {
+			t.Fatalf("test-case: (%q); returned %v, %v; expected %v", name, ids, err, test.expected)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"task-tracker/internal/models"
 )
@@ -18,6 +19,13 @@ var (
 	ErrGettingTask     = fmt.Errorf("error getting task")
 	ErrGettingAllTasks = fmt.Errorf("error getting all tasks")
 	ErrUpdatingTask    = fmt.Errorf("error updating task")
+	ErrCountingTasks   = fmt.Errorf("error counting tasks")
+	ErrPinging         = fmt.Errorf("error pinging repository")
+	ErrGettingSeries   = fmt.Errorf("error getting series heads")
+	ErrAdvancingSeries = fmt.Errorf("error advancing series")
+	ErrGettingDue      = fmt.Errorf("error getting due reminders")
+	ErrRemindingTask   = fmt.Errorf("error advancing reminder")
+	ErrGettingOverdue  = fmt.Errorf("error getting overdue tasks")
 )
 
 func (repo *MockTaskRepository) Add(_ context.Context, _ *models.Task) error {
@@ -40,6 +48,10 @@ func (repo *MockTaskRepository) Exists(_ context.Context, _ string) (bool, error
 	return repo.IsExist, nil
 }
 
+func (repo *MockTaskRepository) IDTaken(_ context.Context, _ string) (bool, error) {
+	return repo.IsExist, nil
+}
+
 func (repo *MockTaskRepository) Get(_ context.Context, id string) (models.Task, error) {
 	if repo.ForceRepositoryError {
 		return models.Task{}, ErrGettingTask
@@ -56,6 +68,22 @@ func (repo *MockTaskRepository) GetAll(_ context.Context) ([]models.Task, error)
 	return []models.Task{{ID: "task1", Title: "Mock Task"}}, nil
 }
 
+func (repo *MockTaskRepository) FindByCalendar(_ context.Context, _, _ string) ([]models.Task, error) {
+	if repo.ForceRepositoryError {
+		return nil, ErrGettingAllTasks
+	}
+
+	return nil, nil
+}
+
+func (repo *MockTaskRepository) Stream(_ context.Context, fn func(models.Task) error) error {
+	if repo.ForceRepositoryError {
+		return ErrGettingAllTasks
+	}
+
+	return fn(models.Task{ID: "task1", Title: "Mock Task"})
+}
+
 func (repo *MockTaskRepository) Update(_ context.Context, _ *models.Task) error {
 	if repo.ForceRepositoryError {
 		return ErrUpdatingTask
@@ -63,3 +91,59 @@ func (repo *MockTaskRepository) Update(_ context.Context, _ *models.Task) error
 
 	return nil
 }
+
+func (repo *MockTaskRepository) CountByStatus(_ context.Context) (map[string]int, error) {
+	if repo.ForceRepositoryError {
+		return nil, ErrCountingTasks
+	}
+
+	return map[string]int{"todo": 1}, nil
+}
+
+func (repo *MockTaskRepository) Ping(_ context.Context) error {
+	if repo.ForceRepositoryError {
+		return ErrPinging
+	}
+
+	return nil
+}
+
+func (repo *MockTaskRepository) GetSeriesHeads(_ context.Context) ([]models.Task, error) {
+	if repo.ForceRepositoryError {
+		return nil, ErrGettingSeries
+	}
+
+	return nil, nil
+}
+
+func (repo *MockTaskRepository) AddNextOccurrence(_ context.Context, _ string, _ *models.Task) error {
+	if repo.ForceRepositoryError {
+		return ErrAdvancingSeries
+	}
+
+	return nil
+}
+
+func (repo *MockTaskRepository) GetDueReminders(_ context.Context, _ time.Time) ([]models.Task, error) {
+	if repo.ForceRepositoryError {
+		return nil, ErrGettingDue
+	}
+
+	return nil, nil
+}
+
+func (repo *MockTaskRepository) AdvanceReminder(_ context.Context, _ string, _, _ time.Time) error {
+	if repo.ForceRepositoryError {
+		return ErrRemindingTask
+	}
+
+	return nil
+}
+
+func (repo *MockTaskRepository) GetOverdue(_ context.Context, _ time.Time) ([]models.Task, error) {
+	if repo.ForceRepositoryError {
+		return nil, ErrGettingOverdue
+	}
+
+	return nil, nil
+}
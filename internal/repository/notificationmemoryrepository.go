@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type MemoryNotificationRepository struct {
+	digests map[string]bool
+	mu      sync.Mutex
+}
+
+func NewMemoryNotificationRepository() *MemoryNotificationRepository {
+	return &MemoryNotificationRepository{
+		digests: make(map[string]bool),
+	}
+}
+
+func (repo *MemoryNotificationRepository) ClaimDigest(_ context.Context, day time.Time) (bool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	key := day.Format(time.DateOnly)
+	if repo.digests[key] {
+		return false, nil
+	}
+
+	repo.digests[key] = true
+
+	return true, nil
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PostgresNotificationRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresNotificationRepository(db *pgxpool.Pool) *PostgresNotificationRepository {
+	return &PostgresNotificationRepository{
+		db: db,
+	}
+}
+
+func (repo *PostgresNotificationRepository) ClaimDigest(ctx context.Context, day time.Time) (bool, error) {
+	query := `INSERT INTO notification_digests (day) VALUES ($1) ON CONFLICT (day) DO NOTHING`
+
+	tag, err := repo.db.Exec(ctx, query, day.Format(time.DateOnly))
+	if err != nil {
+		return false, fmt.Errorf("error claiming digest: %v", err)
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
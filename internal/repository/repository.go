@@ -2,15 +2,71 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"task-tracker/internal/models"
 )
 
+// Tasks are scoped to the workspace in the context, see tenant.WithWorkspace, except in
+// CountByStatus, GetSeriesHeads, GetDueReminders and GetOverdue, which span all workspaces
+// for operator metrics and the background schedulers. Writes keep NextReminderAt in sync,
+// see models.Task.ScheduleReminders.
 type TaskRepository interface {
 	Add(ctx context.Context, task *models.Task) error
 	Delete(ctx context.Context, id string) error
 	Exists(ctx context.Context, id string) (bool, error)
+	// IDTaken checks the tasks of every workspace, like Add does.
+	IDTaken(ctx context.Context, id string) (bool, error)
 	Get(ctx context.Context, id string) (models.Task, error)
 	GetAll(ctx context.Context) ([]models.Task, error)
+	// An empty name or uid matches no task.
+	FindByCalendar(ctx context.Context, name, uid string) ([]models.Task, error)
+	// Stream doesn't load all tasks at once and stops at the first error fn returns.
+	Stream(ctx context.Context, fn func(models.Task) error) error
 	Update(ctx context.Context, updatedTask *models.Task) error
+	CountByStatus(ctx context.Context) (map[string]int, error)
+	Ping(ctx context.Context) error
+	// Series heads are the occurrences that haven't been advanced yet.
+	GetSeriesHeads(ctx context.Context) ([]models.Task, error)
+	// AddNextOccurrence marks currentID advanced and adds next in one step, failing with
+	// models.ErrSeriesAdvanced if it already was.
+	AddNextOccurrence(ctx context.Context, currentID string, next *models.Task) error
+	GetDueReminders(ctx context.Context, now time.Time) ([]models.Task, error)
+	// AdvanceReminder schedules the reminder after sentAt, so missed ones are sent once, and
+	// fails with models.ErrReminderSent if the reminder due at due was already sent.
+	AdvanceReminder(ctx context.Context, id string, due, sentAt time.Time) error
+	GetOverdue(ctx context.Context, now time.Time) ([]models.Task, error)
+}
+
+// Sent notifications are recorded so restarts and other instances don't send them again.
+type NotificationRepository interface {
+	// ClaimDigest returns true for the first caller claiming the digest of day.
+	ClaimDigest(ctx context.Context, day time.Time) (bool, error)
+}
+
+type APIKeyRepository interface {
+	Add(ctx context.Context, key *models.APIKey) error
+	Get(ctx context.Context, id string) (models.APIKey, error)
+	GetAll(ctx context.Context) ([]models.APIKey, error)
+	Revoke(ctx context.Context, id string, at time.Time) error
+	Touch(ctx context.Context, id string, at time.Time) error
+}
+
+type FeedTokenRepository interface {
+	Add(ctx context.Context, token *models.FeedToken) error
+	Get(ctx context.Context, id string) (models.FeedToken, error)
+	GetAllForSubject(ctx context.Context, subject string) ([]models.FeedToken, error)
+	Revoke(ctx context.Context, id string, at time.Time) error
+	Touch(ctx context.Context, id string, at time.Time) error
+}
+
+type WorkspaceRepository interface {
+	Add(ctx context.Context, workspace *models.Workspace) error
+	Get(ctx context.Context, id string) (models.Workspace, error)
+	GetAll(ctx context.Context) ([]models.Workspace, error)
+	GetAllForSubject(ctx context.Context, subject string) ([]models.Workspace, error)
+	Member(ctx context.Context, workspaceID, subject string) (models.WorkspaceMember, error)
+	Members(ctx context.Context, workspaceID string) ([]models.WorkspaceMember, error)
+	SetMember(ctx context.Context, member *models.WorkspaceMember) error
+	RemoveMember(ctx context.Context, workspaceID, subject string) error
 }
@@ -2,30 +2,51 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"task-tracker/internal/clock"
+	"task-tracker/internal/events"
 	"task-tracker/internal/models"
+	"task-tracker/internal/tenant"
+)
+
+const TaskChangesChannel = "task_changes"
+
+const (
+	taskColumns = `id, workspace_id, title, description, status, created_by, assignee, created_at, updated_at,
+		due_at, rrule, time_zone, series_id, occurrence_at, advanced, reminders, reminded_at, next_reminder_at, labels,
+		calendar_uid, calendar_name`
+
+	insertTaskQuery = `INSERT INTO tasks (` + taskColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)`
 )
 
 type PostgresTaskRepository struct {
-	db *pgxpool.Pool
+	db    *pgxpool.Pool
+	clock clock.Clock
 }
 
-func NewPostgresTaskRepository(db *pgxpool.Pool) *PostgresTaskRepository {
+func NewPostgresTaskRepository(db *pgxpool.Pool, clock clock.Clock) *PostgresTaskRepository {
 	return &PostgresTaskRepository{
-		db: db,
+		db:    db,
+		clock: clock,
 	}
 }
 
-func CreateDBPool(ctx context.Context, connString string) (*pgxpool.Pool, error) {
+func CreateDBPool(ctx context.Context, connString string, tracer pgx.QueryTracer) (*pgxpool.Pool, error) {
 	config, err := pgxpool.ParseConfig(connString)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing config: %v", err)
 	}
 
+	config.ConnConfig.Tracer = tracer
+
 	pool, err := pgxpool.NewWithConfig(ctx, config)
 	if err != nil {
 		return nil, fmt.Errorf("error connecting to db: %v", err)
@@ -35,18 +56,19 @@ func CreateDBPool(ctx context.Context, connString string) (*pgxpool.Pool, error)
 }
 
 func (repo *PostgresTaskRepository) Add(ctx context.Context, task *models.Task) error {
-	query := `INSERT INTO tasks (id, title, description, status, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6)`
-	_, err := repo.db.Exec(
+	task.WorkspaceID = tenant.Workspace(ctx)
+	task.ScheduleReminders()
+	err := repo.execAndNotify(
 		ctx,
-		query,
-		task.ID,
-		task.Title,
-		task.Description,
-		task.Status,
-		task.CreatedAt,
-		task.UpdatedAt,
+		events.TaskEvent{Type: events.TaskCreated, WorkspaceID: task.WorkspaceID, TaskID: task.ID},
+		insertTaskQuery,
+		taskValues(task)...,
 	)
 
+	if isPgError(err, uniqueViolation) {
+		return fmt.Errorf("error adding task: %w", models.ErrTaskExists)
+	}
+
 	if err != nil {
 		return fmt.Errorf("error adding task: %v", err)
 	}
@@ -55,8 +77,9 @@ func (repo *PostgresTaskRepository) Add(ctx context.Context, task *models.Task)
 }
 
 func (repo *PostgresTaskRepository) Delete(ctx context.Context, id string) error {
-	query := `DELETE FROM tasks WHERE id=$1`
-	_, err := repo.db.Exec(ctx, query, id)
+	query := `DELETE FROM tasks WHERE id=$1 AND workspace_id=$2`
+	workspace := tenant.Workspace(ctx)
+	err := repo.execAndNotify(ctx, events.TaskEvent{Type: events.TaskDeleted, WorkspaceID: workspace, TaskID: id}, query, id, workspace)
 
 	if err != nil {
 		return fmt.Errorf("error deleting task: %v", err)
@@ -68,8 +91,8 @@ func (repo *PostgresTaskRepository) Delete(ctx context.Context, id string) error
 func (repo *PostgresTaskRepository) Exists(ctx context.Context, id string) (bool, error) {
 	var exists bool
 
-	query := `SELECT EXISTS(SELECT 1 FROM tasks WHERE id=$1)`
-	err := repo.db.QueryRow(ctx, query, id).Scan(&exists)
+	query := `SELECT EXISTS(SELECT 1 FROM tasks WHERE id=$1 AND workspace_id=$2)`
+	err := repo.db.QueryRow(ctx, query, id, tenant.Workspace(ctx)).Scan(&exists)
 
 	if err != nil {
 		return false, fmt.Errorf("error checking if task exists: %v", err)
@@ -78,19 +101,21 @@ func (repo *PostgresTaskRepository) Exists(ctx context.Context, id string) (bool
 	return exists, nil
 }
 
-func (repo *PostgresTaskRepository) Get(ctx context.Context, id string) (models.Task, error) {
-	var task models.Task
+func (repo *PostgresTaskRepository) IDTaken(ctx context.Context, id string) (bool, error) {
+	var taken bool
 
-	query := `SELECT id, title, description, status, created_at, updated_at FROM tasks WHERE id=$1`
-	err := repo.db.QueryRow(ctx, query, id).Scan(
-		&task.ID,
-		&task.Title,
-		&task.Description,
-		&task.Status,
-		&task.CreatedAt,
-		&task.UpdatedAt,
-	)
+	err := repo.db.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM tasks WHERE id=$1)`, id).Scan(&taken)
+	if err != nil {
+		return false, fmt.Errorf("error checking if task id is taken: %v", err)
+	}
 
+	return taken, nil
+}
+
+func (repo *PostgresTaskRepository) Get(ctx context.Context, id string) (models.Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE id=$1 AND workspace_id=$2`
+
+	task, err := scanTask(repo.db.QueryRow(ctx, query, id, tenant.Workspace(ctx)))
 	if err != nil {
 		return models.Task{}, fmt.Errorf("error getting task: %v", err)
 	}
@@ -99,53 +124,68 @@ func (repo *PostgresTaskRepository) Get(ctx context.Context, id string) (models.
 }
 
 func (repo *PostgresTaskRepository) GetAll(ctx context.Context) ([]models.Task, error) {
-	query := `SELECT id, title, description, status, created_at, updated_at FROM tasks`
-	rows, err := repo.db.Query(ctx, query)
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE workspace_id=$1 ORDER BY created_at`
+
+	return repo.queryTasks(ctx, query, tenant.Workspace(ctx))
+}
 
+func (repo *PostgresTaskRepository) FindByCalendar(ctx context.Context, name, uid string) ([]models.Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE workspace_id=$1
+		AND ((calendar_name=$2 AND $2 <> '') OR (calendar_uid=$3 AND $3 <> '')) ORDER BY created_at`
+
+	return repo.queryTasks(ctx, query, tenant.Workspace(ctx), name, uid)
+}
+
+// Stream holds a connection until fn has consumed every row.
+func (repo *PostgresTaskRepository) Stream(ctx context.Context, fn func(models.Task) error) error {
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE workspace_id=$1 ORDER BY created_at`
+
+	rows, err := repo.db.Query(ctx, query, tenant.Workspace(ctx))
 	if err != nil {
-		return []models.Task{}, fmt.Errorf("error getting tasks: %v", err)
+		return fmt.Errorf("error getting tasks: %v", err)
 	}
 
 	defer rows.Close()
 
-	var tasks []models.Task
-
 	for rows.Next() {
-		var task models.Task
-		err := rows.Scan(
-			&task.ID,
-			&task.Title,
-			&task.Description,
-			&task.Status,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-		)
-
+		task, err := scanTask(rows)
 		if err != nil {
-			return []models.Task{}, fmt.Errorf("error scanning row: %v", err)
+			return fmt.Errorf("error scanning row: %v", err)
 		}
 
-		tasks = append(tasks, task)
+		if err := fn(task); err != nil {
+			return err
+		}
 	}
 
 	if err = rows.Err(); err != nil {
-		return []models.Task{}, fmt.Errorf("error iterating rows: %w", err)
+		return fmt.Errorf("error iterating rows: %w", err)
 	}
 
-	return tasks, nil
+	return nil
 }
 
 func (repo *PostgresTaskRepository) Update(ctx context.Context, updatedTask *models.Task) error {
-	query := `UPDATE tasks SET title=$1, description=$2, status=$3, updated_at=$4 WHERE id=$5`
-	updatedTask.UpdatedAt = time.Now().Format(time.RFC3339Nano)
-	_, err := repo.db.Exec(
+	query := `UPDATE tasks SET title=$1, description=$2, status=$3, assignee=$4, due_at=$5, reminders=$6,
+		next_reminder_at=$7, labels=$8, updated_at=$9 WHERE id=$10 AND workspace_id=$11`
+	updatedTask.UpdatedAt = repo.clock.Now()
+	updatedTask.WorkspaceID = tenant.Workspace(ctx)
+	updatedTask.ScheduleReminders()
+	err := repo.execAndNotify(
 		ctx,
+		events.TaskEvent{Type: events.TaskUpdated, WorkspaceID: updatedTask.WorkspaceID, TaskID: updatedTask.ID},
 		query,
 		updatedTask.Title,
 		updatedTask.Description,
 		updatedTask.Status,
+		updatedTask.Assignee,
+		updatedTask.DueAt,
+		reminderSeconds(updatedTask.Reminders),
+		updatedTask.NextReminderAt,
+		labelValues(updatedTask.Labels),
 		updatedTask.UpdatedAt,
 		updatedTask.ID,
+		updatedTask.WorkspaceID,
 	)
 
 	if err != nil {
@@ -154,3 +194,291 @@ func (repo *PostgresTaskRepository) Update(ctx context.Context, updatedTask *mod
 
 	return nil
 }
+
+func (repo *PostgresTaskRepository) CountByStatus(ctx context.Context) (map[string]int, error) {
+	query := `SELECT status, count(*) FROM tasks GROUP BY status`
+	rows, err := repo.db.Query(ctx, query)
+
+	if err != nil {
+		return nil, fmt.Errorf("error counting tasks: %v", err)
+	}
+
+	defer rows.Close()
+
+	counts := make(map[string]int)
+
+	for rows.Next() {
+		var (
+			status string
+			count  int
+		)
+
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, fmt.Errorf("error scanning row: %v", err)
+		}
+
+		counts[status] = count
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return counts, nil
+}
+
+func (repo *PostgresTaskRepository) GetSeriesHeads(ctx context.Context) ([]models.Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE series_id <> '' AND NOT advanced ORDER BY created_at`
+
+	return repo.queryTasks(ctx, query)
+}
+
+func (repo *PostgresTaskRepository) AddNextOccurrence(ctx context.Context, currentID string, next *models.Task) error {
+	workspace := tenant.Workspace(ctx)
+
+	err := pgx.BeginFunc(ctx, repo.db, func(tx pgx.Tx) error {
+		query := `UPDATE tasks SET advanced=true WHERE id=$1 AND workspace_id=$2 AND NOT advanced`
+
+		tag, err := tx.Exec(ctx, query, currentID, workspace)
+		if err != nil {
+			return err
+		}
+
+		if tag.RowsAffected() == 0 {
+			return models.ErrSeriesAdvanced
+		}
+
+		if next == nil {
+			return nil
+		}
+
+		next.WorkspaceID = workspace
+		next.ScheduleReminders()
+
+		if _, err := tx.Exec(ctx, insertTaskQuery, taskValues(next)...); err != nil {
+			if isPgError(err, uniqueViolation) {
+				return models.ErrSeriesAdvanced
+			}
+
+			return err
+		}
+
+		return notify(ctx, tx, events.TaskEvent{Type: events.TaskCreated, WorkspaceID: workspace, TaskID: next.ID})
+	})
+
+	if errors.Is(err, models.ErrSeriesAdvanced) {
+		return models.ErrSeriesAdvanced
+	}
+
+	if err != nil {
+		return fmt.Errorf("error adding next occurrence: %v", err)
+	}
+
+	return nil
+}
+
+func (repo *PostgresTaskRepository) GetDueReminders(ctx context.Context, now time.Time) ([]models.Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE next_reminder_at <= $1 ORDER BY next_reminder_at`
+
+	return repo.queryTasks(ctx, query, now)
+}
+
+func (repo *PostgresTaskRepository) AdvanceReminder(ctx context.Context, id string, due, sentAt time.Time) error {
+	workspace := tenant.Workspace(ctx)
+
+	err := pgx.BeginFunc(ctx, repo.db, func(tx pgx.Tx) error {
+		query := `SELECT ` + taskColumns + ` FROM tasks WHERE id=$1 AND workspace_id=$2 AND next_reminder_at=$3 FOR UPDATE`
+
+		task, err := scanTask(tx.QueryRow(ctx, query, id, workspace, due))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.ErrReminderSent
+		}
+
+		if err != nil {
+			return err
+		}
+
+		task.RemindedAt = &sentAt
+		task.ScheduleReminders()
+
+		update := `UPDATE tasks SET reminded_at=$1, next_reminder_at=$2 WHERE id=$3 AND workspace_id=$4`
+		_, err = tx.Exec(ctx, update, task.RemindedAt, task.NextReminderAt, id, workspace)
+
+		return err
+	})
+
+	if errors.Is(err, models.ErrReminderSent) {
+		return models.ErrReminderSent
+	}
+
+	if err != nil {
+		return fmt.Errorf("error advancing reminder: %v", err)
+	}
+
+	return nil
+}
+
+func (repo *PostgresTaskRepository) GetOverdue(ctx context.Context, now time.Time) ([]models.Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE due_at < $1 AND status <> ALL($2) ORDER BY due_at`
+
+	return repo.queryTasks(ctx, query, now, models.TerminalStatuses())
+}
+
+func (repo *PostgresTaskRepository) Ping(ctx context.Context) error {
+	if err := repo.db.Ping(ctx); err != nil {
+		return fmt.Errorf("error pinging database: %w", err)
+	}
+
+	return nil
+}
+
+// The notification is sent in the write's transaction, so only commits are announced.
+func (repo *PostgresTaskRepository) execAndNotify(ctx context.Context, event events.TaskEvent, query string, args ...any) error {
+	return pgx.BeginFunc(ctx, repo.db, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, query, args...); err != nil {
+			return err
+		}
+
+		return notify(ctx, tx, event)
+	})
+}
+
+func notify(ctx context.Context, tx pgx.Tx, event events.TaskEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error encoding task event: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `SELECT pg_notify($1, $2)`, TaskChangesChannel, string(payload))
+
+	return err
+}
+
+func (repo *PostgresTaskRepository) queryTasks(ctx context.Context, query string, args ...any) ([]models.Task, error) {
+	rows, err := repo.db.Query(ctx, query, args...)
+
+	if err != nil {
+		return []models.Task{}, fmt.Errorf("error getting tasks: %v", err)
+	}
+
+	defer rows.Close()
+
+	var tasks []models.Task
+
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return []models.Task{}, fmt.Errorf("error scanning row: %v", err)
+		}
+
+		tasks = append(tasks, task)
+	}
+
+	if err = rows.Err(); err != nil {
+		return []models.Task{}, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return tasks, nil
+}
+
+func taskValues(task *models.Task) []any {
+	return []any{
+		task.ID,
+		task.WorkspaceID,
+		task.Title,
+		task.Description,
+		task.Status,
+		task.CreatedBy,
+		task.Assignee,
+		task.CreatedAt,
+		task.UpdatedAt,
+		task.DueAt,
+		task.RRule,
+		task.TimeZone,
+		task.SeriesID,
+		task.OccurrenceAt,
+		task.Advanced,
+		reminderSeconds(task.Reminders),
+		task.RemindedAt,
+		task.NextReminderAt,
+		labelValues(task.Labels),
+		task.CalendarUID,
+		task.CalendarName,
+	}
+}
+
+// labelValues stores missing labels as an empty array rather than NULL.
+func labelValues(labels []string) []string {
+	if labels == nil {
+		return []string{}
+	}
+
+	return labels
+}
+
+// Reminders are stored as whole seconds before the due date.
+func reminderSeconds(reminders []models.Duration) []int64 {
+	seconds := make([]int64, len(reminders))
+
+	for i, before := range reminders {
+		seconds[i] = int64(time.Duration(before) / time.Second)
+	}
+
+	return seconds
+}
+
+// scanTask reads a row selected with taskColumns, with timestamps in UTC.
+func scanTask(row pgx.Row) (models.Task, error) {
+	var (
+		task      models.Task
+		reminders []int64
+		labels    []string
+	)
+
+	err := row.Scan(
+		&task.ID,
+		&task.WorkspaceID,
+		&task.Title,
+		&task.Description,
+		&task.Status,
+		&task.CreatedBy,
+		&task.Assignee,
+		&task.CreatedAt,
+		&task.UpdatedAt,
+		&task.DueAt,
+		&task.RRule,
+		&task.TimeZone,
+		&task.SeriesID,
+		&task.OccurrenceAt,
+		&task.Advanced,
+		&reminders,
+		&task.RemindedAt,
+		&task.NextReminderAt,
+		&labels,
+		&task.CalendarUID,
+		&task.CalendarName,
+	)
+
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	for _, seconds := range reminders {
+		task.Reminders = append(task.Reminders, models.Duration(time.Duration(seconds)*time.Second))
+	}
+
+	if len(labels) > 0 {
+		task.Labels = labels
+	}
+
+	task.CreatedAt = task.CreatedAt.UTC()
+	task.UpdatedAt = task.UpdatedAt.UTC()
+
+	for _, t := range []*time.Time{task.DueAt, task.OccurrenceAt, task.RemindedAt, task.NextReminderAt} {
+		if t != nil {
+			*t = t.UTC()
+		}
+	}
+
+	return task, nil
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"task-tracker/internal/clock"
+	"task-tracker/internal/models"
+	"task-tracker/internal/tenant"
+)
+
+type MemoryWorkspaceRepository struct {
+	workspaces map[string]models.Workspace
+	members    map[string]map[string]models.WorkspaceMember
+	mu         sync.Mutex
+}
+
+// NewMemoryWorkspaceRepository starts with the default workspace, like the migration does.
+func NewMemoryWorkspaceRepository(clock clock.Clock) *MemoryWorkspaceRepository {
+	return &MemoryWorkspaceRepository{
+		workspaces: map[string]models.Workspace{
+			tenant.DefaultWorkspace: {ID: tenant.DefaultWorkspace, Name: "Default", CreatedAt: clock.Now()},
+		},
+		members: make(map[string]map[string]models.WorkspaceMember),
+	}
+}
+
+func (repo *MemoryWorkspaceRepository) Add(_ context.Context, workspace *models.Workspace) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, found := repo.workspaces[workspace.ID]; found {
+		return models.ErrWorkspaceExists
+	}
+
+	repo.workspaces[workspace.ID] = *workspace
+
+	return nil
+}
+
+func (repo *MemoryWorkspaceRepository) Get(_ context.Context, id string) (models.Workspace, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	workspace, found := repo.workspaces[id]
+	if !found {
+		return models.Workspace{}, models.ErrWorkspaceNotFound
+	}
+
+	return workspace, nil
+}
+
+func (repo *MemoryWorkspaceRepository) GetAll(_ context.Context) ([]models.Workspace, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	workspaces := make([]models.Workspace, 0, len(repo.workspaces))
+
+	for _, workspace := range repo.workspaces {
+		workspaces = append(workspaces, workspace)
+	}
+
+	sortWorkspaces(workspaces)
+
+	return workspaces, nil
+}
+
+func (repo *MemoryWorkspaceRepository) GetAllForSubject(_ context.Context, subject string) ([]models.Workspace, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	workspaces := []models.Workspace{}
+
+	for id, members := range repo.members {
+		if _, found := members[subject]; found {
+			workspaces = append(workspaces, repo.workspaces[id])
+		}
+	}
+
+	sortWorkspaces(workspaces)
+
+	return workspaces, nil
+}
+
+func (repo *MemoryWorkspaceRepository) Member(_ context.Context, workspaceID, subject string) (models.WorkspaceMember, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	member, found := repo.members[workspaceID][subject]
+	if !found {
+		return models.WorkspaceMember{}, models.ErrMemberNotFound
+	}
+
+	return member, nil
+}
+
+func (repo *MemoryWorkspaceRepository) Members(_ context.Context, workspaceID string) ([]models.WorkspaceMember, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	members := make([]models.WorkspaceMember, 0, len(repo.members[workspaceID]))
+
+	for _, member := range repo.members[workspaceID] {
+		members = append(members, member)
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].Subject < members[j].Subject
+	})
+
+	return members, nil
+}
+
+func (repo *MemoryWorkspaceRepository) SetMember(_ context.Context, member *models.WorkspaceMember) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, found := repo.workspaces[member.WorkspaceID]; !found {
+		return models.ErrWorkspaceNotFound
+	}
+
+	if repo.members[member.WorkspaceID] == nil {
+		repo.members[member.WorkspaceID] = make(map[string]models.WorkspaceMember)
+	}
+
+	repo.members[member.WorkspaceID][member.Subject] = *member
+
+	return nil
+}
+
+func (repo *MemoryWorkspaceRepository) RemoveMember(_ context.Context, workspaceID, subject string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, found := repo.members[workspaceID][subject]; !found {
+		return models.ErrMemberNotFound
+	}
+
+	delete(repo.members[workspaceID], subject)
+
+	return nil
+}
+
+func sortWorkspaces(workspaces []models.Workspace) {
+	sort.Slice(workspaces, func(i, j int) bool {
+		return workspaces[i].ID < workspaces[j].ID
+	})
+}
@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"task-tracker/internal/models"
+)
+
+// Postgres error codes, see https://www.postgresql.org/docs/current/errcodes-appendix.html.
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+)
+
+type PostgresWorkspaceRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresWorkspaceRepository(db *pgxpool.Pool) *PostgresWorkspaceRepository {
+	return &PostgresWorkspaceRepository{
+		db: db,
+	}
+}
+
+func (repo *PostgresWorkspaceRepository) Add(ctx context.Context, workspace *models.Workspace) error {
+	query := `INSERT INTO workspaces (id, name, created_at) VALUES ($1, $2, $3)`
+	_, err := repo.db.Exec(ctx, query, workspace.ID, workspace.Name, workspace.CreatedAt)
+
+	if isPgError(err, uniqueViolation) {
+		return models.ErrWorkspaceExists
+	}
+
+	if err != nil {
+		return fmt.Errorf("error adding workspace: %v", err)
+	}
+
+	return nil
+}
+
+func (repo *PostgresWorkspaceRepository) Get(ctx context.Context, id string) (models.Workspace, error) {
+	var workspace models.Workspace
+
+	query := `SELECT id, name, created_at FROM workspaces WHERE id=$1`
+	err := repo.db.QueryRow(ctx, query, id).Scan(&workspace.ID, &workspace.Name, &workspace.CreatedAt)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.Workspace{}, models.ErrWorkspaceNotFound
+	}
+
+	if err != nil {
+		return models.Workspace{}, fmt.Errorf("error getting workspace: %v", err)
+	}
+
+	workspace.CreatedAt = workspace.CreatedAt.UTC()
+
+	return workspace, nil
+}
+
+func (repo *PostgresWorkspaceRepository) GetAll(ctx context.Context) ([]models.Workspace, error) {
+	return repo.queryWorkspaces(ctx, `SELECT id, name, created_at FROM workspaces ORDER BY id`)
+}
+
+func (repo *PostgresWorkspaceRepository) GetAllForSubject(ctx context.Context, subject string) ([]models.Workspace, error) {
+	query := `SELECT w.id, w.name, w.created_at FROM workspaces w
+		JOIN workspace_members m ON m.workspace_id = w.id WHERE m.subject=$1 ORDER BY w.id`
+
+	return repo.queryWorkspaces(ctx, query, subject)
+}
+
+func (repo *PostgresWorkspaceRepository) queryWorkspaces(ctx context.Context, query string, args ...any) ([]models.Workspace, error) {
+	rows, err := repo.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting workspaces: %v", err)
+	}
+
+	defer rows.Close()
+
+	workspaces := []models.Workspace{}
+
+	for rows.Next() {
+		var workspace models.Workspace
+
+		if err := rows.Scan(&workspace.ID, &workspace.Name, &workspace.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning row: %v", err)
+		}
+
+		workspace.CreatedAt = workspace.CreatedAt.UTC()
+		workspaces = append(workspaces, workspace)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return workspaces, nil
+}
+
+func (repo *PostgresWorkspaceRepository) Member(ctx context.Context, workspaceID, subject string) (models.WorkspaceMember, error) {
+	member := models.WorkspaceMember{WorkspaceID: workspaceID, Subject: subject}
+
+	query := `SELECT role FROM workspace_members WHERE workspace_id=$1 AND subject=$2`
+	err := repo.db.QueryRow(ctx, query, workspaceID, subject).Scan(&member.Role)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.WorkspaceMember{}, models.ErrMemberNotFound
+	}
+
+	if err != nil {
+		return models.WorkspaceMember{}, fmt.Errorf("error getting workspace member: %v", err)
+	}
+
+	return member, nil
+}
+
+func (repo *PostgresWorkspaceRepository) Members(ctx context.Context, workspaceID string) ([]models.WorkspaceMember, error) {
+	query := `SELECT workspace_id, subject, role FROM workspace_members WHERE workspace_id=$1 ORDER BY subject`
+	rows, err := repo.db.Query(ctx, query, workspaceID)
+
+	if err != nil {
+		return nil, fmt.Errorf("error getting workspace members: %v", err)
+	}
+
+	defer rows.Close()
+
+	members := []models.WorkspaceMember{}
+
+	for rows.Next() {
+		var member models.WorkspaceMember
+
+		if err := rows.Scan(&member.WorkspaceID, &member.Subject, &member.Role); err != nil {
+			return nil, fmt.Errorf("error scanning row: %v", err)
+		}
+
+		members = append(members, member)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return members, nil
+}
+
+func (repo *PostgresWorkspaceRepository) SetMember(ctx context.Context, member *models.WorkspaceMember) error {
+	query := `INSERT INTO workspace_members (workspace_id, subject, role) VALUES ($1, $2, $3)
+		ON CONFLICT (workspace_id, subject) DO UPDATE SET role=EXCLUDED.role`
+	_, err := repo.db.Exec(ctx, query, member.WorkspaceID, member.Subject, member.Role)
+
+	if isPgError(err, foreignKeyViolation) {
+		return models.ErrWorkspaceNotFound
+	}
+
+	if err != nil {
+		return fmt.Errorf("error setting workspace member: %v", err)
+	}
+
+	return nil
+}
+
+func (repo *PostgresWorkspaceRepository) RemoveMember(ctx context.Context, workspaceID, subject string) error {
+	query := `DELETE FROM workspace_members WHERE workspace_id=$1 AND subject=$2`
+
+	tag, err := repo.db.Exec(ctx, query, workspaceID, subject)
+	if err != nil {
+		return fmt.Errorf("error removing workspace member: %v", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return models.ErrMemberNotFound
+	}
+
+	return nil
+}
+
+func isPgError(err error, code string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == code
+}
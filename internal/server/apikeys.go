@@ -0,0 +1,74 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"task-tracker/internal/models"
+)
+
+func (s *HTTPServer) handleAPIKeys(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.handleListAPIKeys(w, r)
+	case http.MethodPost:
+		s.handleCreateAPIKey(w, r)
+	default:
+		s.handleError(w, r, models.ErrMethodNotAllowed)
+	}
+}
+
+func (s *HTTPServer) handleListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := s.apiKeys.List(r.Context())
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(keys); err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+}
+
+func (s *HTTPServer) handleCreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var request models.CreateAPIKeyRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		s.handleError(w, r, models.ErrBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	key, plaintext, err := s.apiKeys.Create(r.Context(), request)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusCreated)
+
+	if err := json.NewEncoder(w).Encode(models.CreateAPIKeyResponse{APIKey: key, Key: plaintext}); err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+}
+
+func (s *HTTPServer) handleAPIKeyByID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		s.handleError(w, r, models.ErrMethodNotAllowed)
+		return
+	}
+
+	if err := s.apiKeys.Revoke(r.Context(), r.PathValue("id")); err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,173 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"task-tracker/internal/config"
+	"task-tracker/internal/models"
+)
+
+func TestServer_APIKeyAuth(t *testing.T) {
+	server := NewHTTPServer(config.Config{
+		InMemory:        true,
+		AuthEnabled:     true,
+		LogLevel:        "error",
+		ShutdownTimeout: time.Second,
+	})
+
+	if err := server.ConfigureServer(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	newKey := func(scope models.Scope) string {
+		_, plaintext, err := server.apiKeys.Create(context.Background(),
+			models.CreateAPIKeyRequest{Name: string(scope), Scopes: []models.Scope{scope}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		return "Bearer " + plaintext
+	}
+
+	readKey, writeKey, adminKey := newKey(models.ScopeRead), newKey(models.ScopeWrite), newKey(models.ScopeAdmin)
+	createTask := `{"title":"title", "description":"description", "status":"todo"}`
+
+	tests := map[string]struct {
+		method         string
+		path           string
+		body           string
+		authorization  string
+		expectedStatus int
+	}{
+		"missing key":                 {http.MethodGet, "/tasks", "", "", http.StatusUnauthorized},
+		"malformed header":            {http.MethodGet, "/tasks", "", "Basic abc", http.StatusUnauthorized},
+		"unknown key":                 {http.MethodGet, "/tasks", "", "Bearer tt_nope_nope", http.StatusUnauthorized},
+		"read key lists tasks":        {http.MethodGet, "/tasks", "", readKey, http.StatusOK},
+		"read key can't write":        {http.MethodPost, "/tasks", createTask, readKey, http.StatusForbidden},
+		"write key creates tasks":     {http.MethodPost, "/tasks", createTask, writeKey, http.StatusCreated},
+		"write key can't manage keys": {http.MethodGet, "/apikeys", "", writeKey, http.StatusForbidden},
+		"admin key lists keys":        {http.MethodGet, "/apikeys", "", adminKey, http.StatusOK},
+		"health stays public":         {http.MethodGet, "/healthz", "", "", http.StatusOK},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp, _ := server.Handle(test.method, test.path, strings.NewReader(test.body),
+				map[string]string{"Authorization": test.authorization})
+
+			if resp.StatusCode != test.expectedStatus {
+				t.Fatalf("test-case: (%q); returned %v; expected %v", name, resp.StatusCode, test.expectedStatus)
+			}
+
+			if resp.StatusCode == http.StatusUnauthorized && resp.Header.Get("WWW-Authenticate") == "" {
+				t.Fatalf("test-case: (%q); missing WWW-Authenticate header", name)
+			}
+		})
+	}
+}
+
+func TestServer_APIKeyLifecycle(t *testing.T) {
+	server := NewHTTPServer(config.Config{
+		InMemory:        true,
+		AuthEnabled:     true,
+		LogLevel:        "error",
+		ShutdownTimeout: time.Second,
+	})
+
+	if err := server.ConfigureServer(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, admin, err := server.apiKeys.Create(context.Background(),
+		models.CreateAPIKeyRequest{Name: "admin", Scopes: []models.Scope{models.ScopeAdmin}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	adminHeaders := map[string]string{"Authorization": "Bearer " + admin}
+
+	resp, _ := server.Handle(http.MethodPost, "/apikeys", strings.NewReader(`{"name":"ci","scopes":["read"]}`), adminHeaders)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("returned %v; expected %v", resp.StatusCode, http.StatusCreated)
+	}
+
+	var created models.CreateAPIKeyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ciHeaders := map[string]string{"Authorization": "Bearer " + created.Key}
+
+	if resp, _ := server.Handle(http.MethodGet, "/tasks", http.NoBody, ciHeaders); resp.StatusCode != http.StatusOK {
+		t.Fatalf("returned %v; expected %v", resp.StatusCode, http.StatusOK)
+	}
+
+	if resp, _ := server.Handle(http.MethodDelete, "/apikeys/"+created.ID, http.NoBody, adminHeaders); resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("returned %v; expected %v", resp.StatusCode, http.StatusNoContent)
+	}
+
+	if resp, _ := server.Handle(http.MethodGet, "/tasks", http.NoBody, ciHeaders); resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("returned %v; expected %v", resp.StatusCode, http.StatusUnauthorized)
+	}
+}
+
+func TestServer_TestIssuer(t *testing.T) {
+	server := NewHTTPServer(config.Config{
+		InMemory:        true,
+		AuthEnabled:     true,
+		TestIssuer:      true,
+		LogLevel:        "error",
+		ShutdownTimeout: time.Second,
+	})
+
+	if err := server.ConfigureServer(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resp, _ := server.Handle(http.MethodPost, "/auth/test/token", strings.NewReader(`{"sub":"alice","roles":["member"]}`), nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("returned %v; expected %v", resp.StatusCode, http.StatusOK)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	headers := map[string]string{"Authorization": "Bearer " + token.AccessToken}
+
+	resp, _ = server.Handle(http.MethodPost, "/tasks",
+		strings.NewReader(`{"title":"title", "description":"description", "status":"todo"}`), headers)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("returned %v; expected %v", resp.StatusCode, http.StatusCreated)
+	}
+
+	if resp, _ := server.Handle(http.MethodGet, "/apikeys", http.NoBody, headers); resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("returned %v; expected %v", resp.StatusCode, http.StatusForbidden)
+	}
+
+	viewer, _ := server.Handle(http.MethodPost, "/auth/test/token", strings.NewReader(`{"sub":"bob","roles":["viewer"]}`), nil)
+
+	if err := json.NewDecoder(viewer.Body).Decode(&token); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resp, _ = server.Handle(http.MethodPost, "/tasks",
+		strings.NewReader(`{"title":"title", "description":"description", "status":"todo"}`),
+		map[string]string{"Authorization": "Bearer " + token.AccessToken})
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("viewer: returned %v; expected %v", resp.StatusCode, http.StatusForbidden)
+	}
+
+	if resp, _ := server.Handle(http.MethodGet, "/.well-known/jwks.json", http.NoBody, nil); resp.StatusCode != http.StatusOK {
+		t.Fatalf("returned %v; expected %v", resp.StatusCode, http.StatusOK)
+	}
+}
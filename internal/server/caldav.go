@@ -0,0 +1,49 @@
+package server
+
+import (
+	"net/http"
+
+	"task-tracker/internal/caldav"
+	"task-tracker/internal/models"
+)
+
+func (s *HTTPServer) setupCalDAVRoutes(mux *http.ServeMux) {
+	dav := caldav.NewHandler(s.taskService, s.workspaces, s.handleError)
+
+	mux.Handle("/.well-known/caldav", http.RedirectHandler(caldav.HomePath, http.StatusMovedPermanently))
+	mux.Handle(caldav.HomePath+"{$}", s.withDAVAuth(dav.ServeHome))
+	mux.Handle(caldav.HomePath+"tasks/{$}", s.withDAVAuth(s.withWorkspace(dav.ServeCollection)))
+	mux.Handle(caldav.HomePath+"tasks/{name}", s.withDAVAuth(s.withWorkspace(dav.ServeObject)))
+	mux.Handle(caldav.HomePath+"workspaces/{ws}/tasks/{$}", s.withDAVAuth(s.withWorkspace(dav.ServeCollection)))
+	mux.Handle(caldav.HomePath+"workspaces/{ws}/tasks/{name}", s.withDAVAuth(s.withWorkspace(dav.ServeObject)))
+}
+
+// CalDAV clients only support Basic auth: the password is taken as the API key or JWT,
+// whatever the user name.
+func (s *HTTPServer) withDAVAuth(next http.HandlerFunc) http.Handler {
+	withAuth := s.withAuth(davScope, next)
+
+	if !s.config.AuthEnabled {
+		return withAuth
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, password, ok := r.BasicAuth(); ok {
+			r = r.Clone(r.Context())
+			r.Header.Set("Authorization", "Bearer "+password)
+		} else if r.Header.Get("Authorization") == "" {
+			w.Header().Add("WWW-Authenticate", `Basic realm="task-tracker"`)
+		}
+
+		withAuth.ServeHTTP(w, r)
+	})
+}
+
+// davScope is scopeByMethod with the read-only WebDAV methods.
+func davScope(r *http.Request) models.Scope {
+	if r.Method == "PROPFIND" || r.Method == "REPORT" {
+		return models.ScopeRead
+	}
+
+	return scopeByMethod(r)
+}
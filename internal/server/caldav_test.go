@@ -0,0 +1,437 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"task-tracker/internal/config"
+	"task-tracker/internal/models"
+)
+
+// TestHandler_CalDAV replays, in order, the requests a to-do app sends to discover the
+// task collection and sync a to-do it creates, completes and deletes. Fixtures are
+// raw HTTP requests in testdata/caldav, with {{etag}} standing for the last ETag
+// the server returned.
+func TestHandler_CalDAV(t *testing.T) {
+	server := NewHTTPServer(config.Config{InMemory: true, LogLevel: "error", ShutdownTimeout: time.Second})
+
+	if err := server.ConfigureServer(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	const href = "/dav/tasks/6b0f4b0c-7a51-4c0e-9f9b-1b7c5d2e8a31.ics"
+
+	tests := []struct {
+		fixture        string
+		expectedStatus int
+		// expectedHeaders must be set, to the given value unless it's empty.
+		expectedHeaders map[string]string
+		expected        []string
+		absent          []string
+	}{
+		{
+			fixture:         "01-well-known.http",
+			expectedStatus:  http.StatusMovedPermanently,
+			expectedHeaders: map[string]string{"Location": "/dav/"},
+		},
+		{
+			fixture:        "02-current-user-principal.http",
+			expectedStatus: http.StatusMultiStatus,
+			expected: []string{
+				"<d:response><d:href>/dav/</d:href>",
+				"<d:resourcetype><d:collection/><d:principal/></d:resourcetype>",
+				"<d:current-user-principal><d:href>/dav/</d:href></d:current-user-principal>",
+			},
+		},
+		{
+			fixture:        "03-calendar-home-set.http",
+			expectedStatus: http.StatusMultiStatus,
+			expected: []string{
+				"<c:calendar-home-set><d:href>/dav/</d:href></c:calendar-home-set>",
+				"<c:calendar-user-address-set/></d:prop><d:status>HTTP/1.1 404 Not Found</d:status>",
+			},
+		},
+		{
+			fixture:        "04-list-calendars.http",
+			expectedStatus: http.StatusMultiStatus,
+			expected: []string{
+				"<d:href>/dav/tasks/</d:href>",
+				"<d:resourcetype><d:collection/><c:calendar/></d:resourcetype><d:displayname>Default</d:displayname>",
+				`<c:supported-calendar-component-set><c:comp name="VTODO"/></c:supported-calendar-component-set>`,
+			},
+		},
+		{
+			fixture:         "05-create-todo.http",
+			expectedStatus:  http.StatusCreated,
+			expectedHeaders: map[string]string{"ETag": ""},
+		},
+		{
+			fixture:        "06-get-ctag.http",
+			expectedStatus: http.StatusMultiStatus,
+			expected:       []string{"<cs:getctag>", "<d:sync-token/></d:prop><d:status>HTTP/1.1 404 Not Found</d:status>"},
+		},
+		{
+			fixture:        "07-list-etags.http",
+			expectedStatus: http.StatusMultiStatus,
+			expected:       []string{"<d:href>" + href + "</d:href><d:propstat><d:prop><d:getetag>"},
+			absent:         []string{"calendar-data"},
+		},
+		{
+			fixture:        "08-multiget.http",
+			expectedStatus: http.StatusMultiStatus,
+			expected: []string{
+				"UID:6b0f4b0c-7a51-4c0e-9f9b-1b7c5d2e8a31&#xD;&#xA;",
+				`SUMMARY:Renew the TLS certificates\, staging first`,
+				"DUE:20250414T070000Z",
+				"STATUS:NEEDS-ACTION",
+				"CATEGORIES:ops,security",
+				"<d:href>/dav/tasks/deleted-elsewhere.ics</d:href><d:status>HTTP/1.1 404 Not Found</d:status>",
+			},
+		},
+		{
+			fixture:        "09-events-query.http",
+			expectedStatus: http.StatusMultiStatus,
+			absent:         []string{href},
+		},
+		{
+			fixture:         "10-complete-todo.http",
+			expectedStatus:  http.StatusNoContent,
+			expectedHeaders: map[string]string{"ETag": ""},
+		},
+		{
+			fixture:        "11-stale-update.http",
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			fixture:         "12-get-todo.http",
+			expectedStatus:  http.StatusOK,
+			expectedHeaders: map[string]string{"Content-Type": "text/calendar; charset=utf-8"},
+			expected:        []string{"STATUS:COMPLETED\r\n", "CATEGORIES:ops\r\n", "DESCRIPTION:Check the expiry dates of all load balancers\\nand renew"},
+		},
+		{
+			fixture:        "13-delete-todo.http",
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			fixture:        "14-get-deleted.http",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	etag := ""
+
+	for _, test := range tests {
+		method, path, headers, body := readFixture(t, test.fixture, etag)
+
+		resp, _ := server.Handle(method, path, strings.NewReader(body), headers)
+
+		if resp.StatusCode != test.expectedStatus {
+			t.Fatalf("test-case: (%q); returned %v; expected %v", test.fixture, resp.StatusCode, test.expectedStatus)
+		}
+
+		for name, expected := range test.expectedHeaders {
+			if value := resp.Header.Get(name); value == "" || (expected != "" && value != expected) {
+				t.Fatalf("test-case: (%q); returned %s %q; expected %q", test.fixture, name, value, expected)
+			}
+		}
+
+		data, _ := io.ReadAll(resp.Body)
+
+		for _, expected := range test.expected {
+			if !strings.Contains(string(data), expected) {
+				t.Fatalf("test-case: (%q); returned %q; expected to contain %q", test.fixture, data, expected)
+			}
+		}
+
+		for _, absent := range test.absent {
+			if strings.Contains(string(data), absent) {
+				t.Fatalf("test-case: (%q); returned %q; expected not to contain %q", test.fixture, data, absent)
+			}
+		}
+
+		if value := resp.Header.Get("ETag"); value != "" {
+			etag = value
+		}
+	}
+
+	resp, _ := server.Handle(http.MethodGet, "/tasks", nil, nil)
+
+	var tasks []models.Task
+	if err := json.NewDecoder(resp.Body).Decode(&tasks); err != nil || len(tasks) != 0 {
+		t.Fatalf("returned %v, %v; expected the synced task to be deleted", tasks, err)
+	}
+}
+
+func TestHandler_CalDAVTaskFromAPI(t *testing.T) {
+	server := NewHTTPServer(config.Config{InMemory: true, LogLevel: "error", ShutdownTimeout: time.Second})
+
+	if err := server.ConfigureServer(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resp, _ := server.Handle(http.MethodPost, "/tasks",
+		strings.NewReader(`{"title":"review", "description":"d", "status":"in_review", "labels":["dev"]}`), nil)
+
+	var task models.Task
+	if err := json.NewDecoder(resp.Body).Decode(&task); err != nil || resp.StatusCode != http.StatusCreated {
+		t.Fatalf("returned %v, %v; expected the task to be created", resp.StatusCode, err)
+	}
+
+	path := "/dav/tasks/" + task.ID + ".ics"
+
+	resp, _ = server.Handle(http.MethodGet, path, nil, nil)
+	data, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(data), "UID:"+task.ID+"@task-tracker\r\n") {
+		t.Fatalf("returned %v %q; expected the task with a UID derived from its ID", resp.StatusCode, data)
+	}
+
+	// The updates apply in order to the same task.
+	tests := []struct {
+		name           string
+		todo           string
+		expectedStatus int
+		expected       string
+	}{
+		{
+			name:           "in process keeps the custom status",
+			todo:           "UID:" + task.ID + "@task-tracker\r\nSUMMARY:review again\r\nSTATUS:IN-PROCESS",
+			expectedStatus: http.StatusNoContent,
+			expected:       "in_review",
+		},
+		{
+			name:           "needs action maps to todo",
+			todo:           "UID:" + task.ID + "@task-tracker\r\nSUMMARY:review again\r\nSTATUS:NEEDS-ACTION",
+			expectedStatus: http.StatusNoContent,
+			expected:       models.StatusTodo,
+		},
+		{
+			name:           "missing summary",
+			todo:           "UID:" + task.ID + "@task-tracker\r\nSTATUS:COMPLETED",
+			expectedStatus: http.StatusBadRequest,
+			expected:       models.StatusTodo,
+		},
+		{
+			name:           "event instead of todo",
+			todo:           "END:VTODO\r\nBEGIN:VEVENT\r\nUID:x\r\nSUMMARY:x\r\nEND:VEVENT\r\nBEGIN:VTODO",
+			expectedStatus: http.StatusBadRequest,
+			expected:       models.StatusTodo,
+		},
+	}
+
+	for _, test := range tests {
+		name := test.name
+		body := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nBEGIN:VTODO\r\n" + test.todo + "\r\nEND:VTODO\r\nEND:VCALENDAR\r\n"
+
+		resp, _ := server.Handle(http.MethodPut, path, strings.NewReader(body), map[string]string{"Content-Type": "text/calendar"})
+
+		if resp.StatusCode != test.expectedStatus {
+			t.Fatalf("test-case: (%q); returned %v; expected %v", name, resp.StatusCode, test.expectedStatus)
+		}
+
+		resp, _ = server.Handle(http.MethodGet, "/tasks/"+task.ID, nil, nil)
+
+		var updated models.Task
+		if err := json.NewDecoder(resp.Body).Decode(&updated); err != nil {
+			t.Fatalf("test-case: (%q); unexpected error: %v", name, err)
+		}
+
+		if updated.Status != test.expected || updated.Title != "review again" || len(updated.Labels) != 1 {
+			t.Fatalf("test-case: (%q); returned %+v; expected status %v, the new title and the labels kept", name, updated, test.expected)
+		}
+	}
+
+	resp, _ = server.Handle(http.MethodPut, "/dav/tasks/copy.ics", strings.NewReader(
+		"BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nUID:"+task.ID+"@task-tracker\r\nSUMMARY:copy\r\nEND:VTODO\r\nEND:VCALENDAR\r\n"), nil)
+
+	if resp.StatusCode != http.StatusConflict {
+		t.Fatalf("returned %v; expected %v for a second task with the same UID", resp.StatusCode, http.StatusConflict)
+	}
+}
+
+func TestHandler_CalDAVPutReplacesTask(t *testing.T) {
+	server := NewHTTPServer(config.Config{InMemory: true, LogLevel: "error", ShutdownTimeout: time.Second})
+
+	if err := server.ConfigureServer(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	create := func(body string) models.Task {
+		resp, _ := server.Handle(http.MethodPost, "/tasks", strings.NewReader(body), nil)
+
+		var task models.Task
+		if err := json.NewDecoder(resp.Body).Decode(&task); err != nil || resp.StatusCode != http.StatusCreated {
+			t.Fatalf("returned %v, %v; expected the task to be created", resp.StatusCode, err)
+		}
+
+		return task
+	}
+
+	single := create(`{"title":"pay", "description":"rent", "status":"todo", "due_at":"2025-05-01T07:00:00Z", "labels":["home"]}`)
+	recurring := create(`{"title":"water", "description":"plants", "status":"todo", "due_at":"2025-05-01T07:00:00Z", "rrule":"FREQ=WEEKLY"}`)
+
+	tests := map[string]struct {
+		task           models.Task
+		todo           string
+		expectedStatus int
+		check          func(task models.Task) bool
+	}{
+		"missing due date is cleared": {
+			task:           single,
+			todo:           "SUMMARY:pay rent",
+			expectedStatus: http.StatusNoContent,
+			check: func(task models.Task) bool {
+				return task.DueAt == nil && task.Description == "pay rent" && len(task.Labels) == 1
+			},
+		},
+		"empty categories clear labels": {
+			task:           single,
+			todo:           "SUMMARY:pay rent\r\nCATEGORIES:",
+			expectedStatus: http.StatusNoContent,
+			check:          func(task models.Task) bool { return len(task.Labels) == 0 },
+		},
+		"recurring tasks keep their due date": {
+			task:           recurring,
+			todo:           "SUMMARY:water",
+			expectedStatus: http.StatusBadRequest,
+			check:          func(task models.Task) bool { return task.DueAt != nil && task.Description == "plants" },
+		},
+	}
+
+	for _, name := range []string{"missing due date is cleared", "empty categories clear labels", "recurring tasks keep their due date"} {
+		test := tests[name]
+
+		t.Run(name, func(t *testing.T) {
+			body := "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nUID:" + test.task.ID + "@task-tracker\r\n" + test.todo + "\r\nEND:VTODO\r\nEND:VCALENDAR\r\n"
+
+			resp, _ := server.Handle(http.MethodPut, "/dav/tasks/"+test.task.ID+".ics", strings.NewReader(body), nil)
+
+			if resp.StatusCode != test.expectedStatus {
+				t.Fatalf("test-case: (%q); returned %v; expected %v", name, resp.StatusCode, test.expectedStatus)
+			}
+
+			resp, _ = server.Handle(http.MethodGet, "/tasks/"+test.task.ID, nil, nil)
+
+			var task models.Task
+			if err := json.NewDecoder(resp.Body).Decode(&task); err != nil || !test.check(task) {
+				t.Fatalf("test-case: (%q); returned %+v, %v", name, task, err)
+			}
+		})
+	}
+}
+
+func TestServer_CalDAVAuth(t *testing.T) {
+	server := NewHTTPServer(config.Config{
+		InMemory:        true,
+		AuthEnabled:     true,
+		LogLevel:        "error",
+		ShutdownTimeout: time.Second,
+	})
+
+	if err := server.ConfigureServer(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, readKey, err := server.apiKeys.Create(context.Background(),
+		models.CreateAPIKeyRequest{Name: "phone", Scopes: []models.Scope{models.ScopeRead}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, writeKey, err := server.apiKeys.Create(context.Background(),
+		models.CreateAPIKeyRequest{Name: "laptop", Scopes: []models.Scope{models.ScopeWrite}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, adminKey, err := server.apiKeys.Create(context.Background(),
+		models.CreateAPIKeyRequest{Name: "admin", Scopes: []models.Scope{models.ScopeAdmin}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Created by the admin key, so the write key may not change it.
+	resp, _ := server.Handle(http.MethodPut, "/dav/tasks/a.ics", strings.NewReader(
+		"BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nUID:a\r\nSUMMARY:a\r\nEND:VTODO\r\nEND:VCALENDAR\r\n"),
+		map[string]string{"Authorization": "Bearer " + adminKey})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("returned %v; expected %v", resp.StatusCode, http.StatusCreated)
+	}
+
+	basic := func(password string) string {
+		req, _ := http.NewRequest(http.MethodGet, "/", nil)
+		req.SetBasicAuth("alice", password)
+
+		return req.Header.Get("Authorization")
+	}
+
+	todo := "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nUID:a\r\nSUMMARY:a\r\nEND:VTODO\r\nEND:VCALENDAR\r\n"
+
+	tests := map[string]struct {
+		method          string
+		body            string
+		authorization   string
+		expectedStatus  int
+		expectedWWWAuth string
+	}{
+		"challenged for basic auth":   {"PROPFIND", "", "", http.StatusUnauthorized, `Basic realm="task-tracker"`},
+		"wrong password":              {"PROPFIND", "", basic("tt_nope_nope"), http.StatusUnauthorized, `Bearer realm="task-tracker"`},
+		"api key as password":         {"PROPFIND", "", basic(readKey), http.StatusMultiStatus, ""},
+		"api key as bearer token":     {"PROPFIND", "", "Bearer " + readKey, http.StatusMultiStatus, ""},
+		"read key can't put":          {http.MethodPut, todo, basic(readKey), http.StatusForbidden, ""},
+		"write key can't put others'": {http.MethodPut, todo, basic(writeKey), http.StatusForbidden, ""},
+		"read key can query a report": {"REPORT", `<c:calendar-query xmlns:c="urn:ietf:params:xml:ns:caldav"/>`, basic(readKey), http.StatusMultiStatus, ""},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := "/dav/tasks/"
+			if test.method == http.MethodPut {
+				path += "a.ics"
+			}
+
+			resp, _ := server.Handle(test.method, path, strings.NewReader(test.body), map[string]string{"Authorization": test.authorization})
+
+			if resp.StatusCode != test.expectedStatus {
+				t.Fatalf("test-case: (%q); returned %v; expected %v", name, resp.StatusCode, test.expectedStatus)
+			}
+
+			if test.expectedWWWAuth != "" && !strings.Contains(strings.Join(resp.Header.Values("WWW-Authenticate"), ", "), test.expectedWWWAuth) {
+				t.Fatalf("test-case: (%q); returned %v; expected %v", name, resp.Header.Values("WWW-Authenticate"), test.expectedWWWAuth)
+			}
+		})
+	}
+}
+
+// readFixture parses a raw HTTP request from testdata/caldav.
+func readFixture(t *testing.T, name, etag string) (string, string, map[string]string, string) {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "caldav", name))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	raw := strings.ReplaceAll(string(data), "{{etag}}", etag)
+	head, body, _ := strings.Cut(raw, "\n\n")
+
+	req, err := http.ReadRequest(bufio.NewReader(strings.NewReader(head + "\n\n")))
+	if err != nil {
+		t.Fatalf("test-case: (%q); unexpected error: %v", name, err)
+	}
+
+	headers := make(map[string]string)
+	for key := range req.Header {
+		headers[key] = req.Header.Get(key)
+	}
+
+	return req.Method, req.URL.RequestURI(), headers, body
+}
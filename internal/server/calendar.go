@@ -0,0 +1,172 @@
+package server
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"task-tracker/internal/auth"
+	"task-tracker/internal/ical"
+	"task-tracker/internal/models"
+	"task-tracker/internal/tenant"
+)
+
+const feedTokenParameter = "token"
+
+// withFeedAuth is withAuth that also accepts a feed token in the query string.
+func (s *HTTPServer) withFeedAuth(next http.HandlerFunc) http.Handler {
+	withAuth := s.withAuth(scopeByMethod, next)
+
+	if s.feedTokens == nil {
+		return withAuth
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get(feedTokenParameter)
+		if token == "" {
+			withAuth.ServeHTTP(w, r)
+			return
+		}
+
+		principal, err := s.feedTokens.Authenticate(r.Context(), token)
+		if err != nil {
+			s.handleError(w, r, err)
+			return
+		}
+
+		if !principal.HasScope(scopeByMethod(r)) {
+			s.handleError(w, r, models.ErrForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+	})
+}
+
+func (s *HTTPServer) handleCalendar(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		s.handleError(w, r, models.ErrMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+
+	component, err := calendarComponent(query.Get("component"))
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	tasks, err := s.taskService.GetAll(r.Context())
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	filter := taskFilter(query)
+	selected := make([]models.Task, 0, len(tasks))
+
+	for _, task := range tasks {
+		if task.DueAt != nil && filter.Match(task) {
+			selected = append(selected, task)
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="calendar.ics"`)
+	w.Header().Set("Cache-Control", "private, no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	calendar := ical.Calendar{
+		Name:      "Tasks (" + tenant.Workspace(r.Context()) + ")",
+		Component: component,
+		Tasks:     selected,
+	}
+
+	// The status is already sent, so the connection is dropped to keep calendar apps
+	// from taking a truncated feed for a complete one.
+	if err := ical.Encode(w, calendar); err != nil {
+		s.logger.LogAttrs(r.Context(), slog.LevelError, "Calendar export failed",
+			slog.String("request_id", requestIDFromContext(r.Context())),
+			slog.String("error", err.Error()),
+		)
+
+		panic(http.ErrAbortHandler)
+	}
+}
+
+func calendarComponent(value string) (string, error) {
+	switch strings.ToLower(value) {
+	case "", "event":
+		return ical.ComponentEvent, nil
+	case "todo":
+		return ical.ComponentTodo, nil
+	default:
+		return "", models.ErrInvalidComponent
+	}
+}
+
+func taskFilter(query url.Values) models.TaskFilter {
+	return models.TaskFilter{
+		Assignees: splitList(query["assignee"]),
+		Labels:    splitList(query["label"]),
+		Statuses:  splitList(query["status"]),
+	}
+}
+
+func splitList(values []string) []string {
+	var items []string
+
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+
+	return items
+}
+
+func (s *HTTPServer) handleFeedTokens(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		tokens, err := s.feedTokens.List(r.Context())
+		if err != nil {
+			s.handleError(w, r, err)
+			return
+		}
+
+		s.writeJSON(w, r, http.StatusOK, tokens)
+	case http.MethodPost:
+		token, plaintext, err := s.feedTokens.Create(r.Context())
+		if err != nil {
+			s.handleError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		s.writeJSON(w, r, http.StatusCreated, models.CreateFeedTokenResponse{FeedToken: token, Token: plaintext})
+	default:
+		s.handleError(w, r, models.ErrMethodNotAllowed)
+	}
+}
+
+func (s *HTTPServer) handleFeedTokenByID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		s.handleError(w, r, models.ErrMethodNotAllowed)
+		return
+	}
+
+	if err := s.feedTokens.Revoke(r.Context(), r.PathValue("id")); err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
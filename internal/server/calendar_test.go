@@ -0,0 +1,170 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"task-tracker/internal/config"
+	"task-tracker/internal/models"
+)
+
+func TestHandler_Calendar(t *testing.T) {
+	server := NewHTTPServer(config.Config{InMemory: true, LogLevel: "error", ShutdownTimeout: time.Second})
+
+	if err := server.ConfigureServer(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ids := make(map[string]string)
+
+	for _, body := range []string{
+		`{"title":"deploy", "description":"d", "status":"todo", "assignee":"alice", "labels":["ops"], "due_at":"2025-04-14T07:00:00Z"}`,
+		`{"title":"review", "description":"d", "status":"done", "assignee":"bob", "labels":["dev","ops"], "due_at":"2025-04-15T07:00:00Z"}`,
+		`{"title":"plan", "description":"d", "status":"todo", "assignee":"alice", "labels":["dev"], "due_at":"2025-04-16T07:00:00Z"}`,
+		`{"title":"someday", "description":"d", "status":"todo", "assignee":"alice"}`,
+	} {
+		resp, _ := server.Handle(http.MethodPost, "/tasks", strings.NewReader(body), nil)
+
+		var task models.Task
+		if err := json.NewDecoder(resp.Body).Decode(&task); err != nil || resp.StatusCode != http.StatusCreated {
+			t.Fatalf("returned %v, %v; expected the task to be created", resp.StatusCode, err)
+		}
+
+		ids[task.Title] = task.ID
+	}
+
+	tests := map[string]struct {
+		query          string
+		expectedStatus int
+		expected       []string
+	}{
+		"all with a due date":  {"", http.StatusOK, []string{"deploy", "review", "plan"}},
+		"by assignee":          {"?assignee=alice", http.StatusOK, []string{"deploy", "plan"}},
+		"by label":             {"?label=ops", http.StatusOK, []string{"deploy", "review"}},
+		"by labels and status": {"?label=ops,dev&status=todo", http.StatusOK, []string{"deploy", "plan"}},
+		"todos":                {"?component=todo&status=done", http.StatusOK, []string{"review"}},
+		"unknown component":    {"?component=journal", http.StatusBadRequest, nil},
+		"no match":             {"?assignee=carol", http.StatusOK, nil},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp, _ := server.Handle(http.MethodGet, "/calendar.ics"+test.query, nil, nil)
+
+			if resp.StatusCode != test.expectedStatus {
+				t.Fatalf("test-case: (%q); returned %v; expected %v", name, resp.StatusCode, test.expectedStatus)
+			}
+
+			if resp.StatusCode != http.StatusOK {
+				return
+			}
+
+			body, _ := io.ReadAll(resp.Body)
+
+			if count := strings.Count(string(body), "UID:"); count != len(test.expected) {
+				t.Fatalf("test-case: (%q); returned %d entries; expected %v", name, count, test.expected)
+			}
+
+			for _, title := range test.expected {
+				if !strings.Contains(string(body), "UID:"+ids[title]+"@task-tracker\r\n") {
+					t.Fatalf("test-case: (%q); returned %q; expected an entry for %v", name, body, title)
+				}
+			}
+		})
+	}
+}
+
+func TestServer_FeedTokens(t *testing.T) {
+	server := NewHTTPServer(config.Config{
+		InMemory:        true,
+		AuthEnabled:     true,
+		LogLevel:        "error",
+		ShutdownTimeout: time.Second,
+	})
+
+	if err := server.ConfigureServer(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, key, err := server.apiKeys.Create(context.Background(),
+		models.CreateAPIKeyRequest{Name: "alice", Scopes: []models.Scope{models.ScopeRead}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	authorization := map[string]string{"Authorization": "Bearer " + key}
+
+	resp, _ := server.Handle(http.MethodPost, "/calendar/tokens", nil, authorization)
+
+	var created models.CreateFeedTokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil || resp.StatusCode != http.StatusCreated {
+		t.Fatalf("returned %v, %v; expected the token to be created", resp.StatusCode, err)
+	}
+
+	tests := map[string]struct {
+		method         string
+		path           string
+		headers        map[string]string
+		expectedStatus int
+	}{
+		"feed token":                 {http.MethodGet, "/calendar.ics?token=" + created.Token, nil, http.StatusOK},
+		"bearer key":                 {http.MethodGet, "/calendar.ics", authorization, http.StatusOK},
+		"no credentials":             {http.MethodGet, "/calendar.ics", nil, http.StatusUnauthorized},
+		"unknown token":              {http.MethodGet, "/calendar.ics?token=tf_nope_nope", nil, http.StatusUnauthorized},
+		"api key isn't a feed token": {http.MethodGet, "/calendar.ics?token=" + key, nil, http.StatusUnauthorized},
+		"feed token is only a feed":  {http.MethodGet, "/tasks?token=" + created.Token, nil, http.StatusUnauthorized},
+		"tokens are listed":          {http.MethodGet, "/calendar/tokens", authorization, http.StatusOK},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp, _ := server.Handle(test.method, test.path, nil, test.headers)
+
+			if resp.StatusCode != test.expectedStatus {
+				t.Fatalf("test-case: (%q); returned %v; expected %v", name, resp.StatusCode, test.expectedStatus)
+			}
+		})
+	}
+
+	resp, _ = server.Handle(http.MethodDelete, "/calendar/tokens/"+created.ID, nil, authorization)
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("returned %v; expected %v", resp.StatusCode, http.StatusNoContent)
+	}
+
+	resp, _ = server.Handle(http.MethodGet, "/calendar.ics?token="+created.Token, nil, nil)
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("returned %v; expected revoked tokens to be rejected", resp.StatusCode)
+	}
+}
+
+// failingWriter is a ResponseWriter whose connection is gone.
+type failingWriter struct {
+	*httptest.ResponseRecorder
+}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func TestHandler_CalendarAbort(t *testing.T) {
+	server := NewHTTPServer(config.Config{InMemory: true, LogLevel: "error", ShutdownTimeout: time.Second})
+
+	if err := server.ConfigureServer(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() {
+		if err, _ := recover().(error); !errors.Is(err, http.ErrAbortHandler) {
+			t.Fatalf("returned %v; expected the handler to be aborted", err)
+		}
+	}()
+
+	server.handleCalendar(failingWriter{httptest.NewRecorder()}, httptest.NewRequest(http.MethodGet, "/calendar.ics", nil))
+}
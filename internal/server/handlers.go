@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
 
@@ -17,18 +18,18 @@ func (s *HTTPServer) handleTasks(w http.ResponseWriter, r *http.Request) {
 	case http.MethodPost:
 		s.handleCreateTask(w, r)
 	default:
-		s.handleError(w, r.RemoteAddr, models.ErrMethodNotAllowed)
+		s.handleError(w, r, models.ErrMethodNotAllowed)
 	}
 }
 
 func (s *HTTPServer) handleSwagger(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		s.handleError(w, r.RemoteAddr, models.ErrMethodNotAllowed)
+		s.handleError(w, r, models.ErrMethodNotAllowed)
 		return
 	}
 
 	if _, err := os.Stat("docs/static/index.html"); os.IsNotExist(err) {
-		s.handleError(w, r.RemoteAddr, models.ErrSwaggerUINotFound)
+		s.handleError(w, r, models.ErrSwaggerUINotFound)
 		return
 	}
 
@@ -44,14 +45,14 @@ func (s *HTTPServer) handleTaskByID(w http.ResponseWriter, r *http.Request) {
 	case http.MethodPatch:
 		s.handleUpdateTask(w, r)
 	default:
-		s.handleError(w, r.RemoteAddr, models.ErrMethodNotAllowed)
+		s.handleError(w, r, models.ErrMethodNotAllowed)
 	}
 }
 
 func (s *HTTPServer) handleGetAllTasks(w http.ResponseWriter, r *http.Request) {
 	tasks, err := s.taskService.GetAll(r.Context())
 	if err != nil {
-		s.handleError(w, r.RemoteAddr, err)
+		s.handleError(w, r, err)
 		return
 	}
 
@@ -59,7 +60,7 @@ func (s *HTTPServer) handleGetAllTasks(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 
 	if err := json.NewEncoder(w).Encode(tasks); err != nil {
-		s.handleError(w, r.RemoteAddr, err)
+		s.handleError(w, r, err)
 		return
 	}
 }
@@ -68,20 +69,20 @@ func (s *HTTPServer) handleCreateTask(w http.ResponseWriter, r *http.Request) {
 	var request models.CreateTaskRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		s.handleError(w, r.RemoteAddr, models.ErrBadRequest)
+		s.handleError(w, r, models.ErrBadRequest)
 		return
 	}
 	defer r.Body.Close()
 
 	if err := request.Validate(); err != nil {
-		s.handleError(w, r.RemoteAddr, fmt.Errorf("request validation: %w", err))
+		s.handleError(w, r, fmt.Errorf("request validation: %w", err))
 		return
 	}
 
 	task := request.ConvertToTask()
 
 	if err := s.taskService.Add(r.Context(), task); err != nil {
-		s.handleError(w, r.RemoteAddr, err)
+		s.handleError(w, r, err)
 		return
 	}
 
@@ -89,7 +90,7 @@ func (s *HTTPServer) handleCreateTask(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusCreated)
 
 	if err := json.NewEncoder(w).Encode(task); err != nil {
-		s.handleError(w, r.RemoteAddr, err)
+		s.handleError(w, r, err)
 		return
 	}
 }
@@ -100,7 +101,7 @@ func (s *HTTPServer) handleGetTask(w http.ResponseWriter, r *http.Request) {
 	task, err := s.taskService.Get(r.Context(), taskID)
 
 	if err != nil {
-		s.handleError(w, r.RemoteAddr, err)
+		s.handleError(w, r, err)
 		return
 	}
 
@@ -108,7 +109,7 @@ func (s *HTTPServer) handleGetTask(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 
 	if err := json.NewEncoder(w).Encode(task); err != nil {
-		s.handleError(w, r.RemoteAddr, err)
+		s.handleError(w, r, err)
 		return
 	}
 }
@@ -117,7 +118,7 @@ func (s *HTTPServer) handleDeleteTask(w http.ResponseWriter, r *http.Request) {
 	taskID := r.PathValue("id")
 
 	if err := s.taskService.Delete(r.Context(), taskID); err != nil {
-		s.handleError(w, r.RemoteAddr, err)
+		s.handleError(w, r, err)
 		return
 	}
 
@@ -130,37 +131,53 @@ func (s *HTTPServer) handleUpdateTask(w http.ResponseWriter, r *http.Request) {
 	var request models.UpdateTaskRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		s.handleError(w, r.RemoteAddr, models.ErrBadRequest)
+		s.handleError(w, r, models.ErrBadRequest)
 		return
 	}
 	defer r.Body.Close()
 
 	if err := request.Validate(); err != nil {
-		s.handleError(w, r.RemoteAddr, fmt.Errorf("request validation: %w", err))
+		s.handleError(w, r, fmt.Errorf("request validation: %w", err))
 		return
 	}
 
 	task := request.ConvertToTask(taskID)
 
 	if err := s.taskService.Update(r.Context(), task); err != nil {
-		s.handleError(w, r.RemoteAddr, err)
+		s.handleError(w, r, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func (s *HTTPServer) handleError(w http.ResponseWriter, ip string, err error) {
+func (s *HTTPServer) handleError(w http.ResponseWriter, r *http.Request, err error) {
 	var modelError models.Error
 
 	if !errors.As(err, &modelError) {
-		s.processError(w, http.StatusInternalServerError, ip, err)
+		s.processError(w, r, http.StatusInternalServerError, err)
+		return
 	}
 
-	s.processError(w, modelError.StatusCode, ip, modelError.Err)
+	s.processError(w, r, modelError.StatusCode, modelError.Err)
 }
 
-func (s *HTTPServer) processError(w http.ResponseWriter, statusCode int, ip string, err error) {
-	s.logger.Printf("HTTP error (%d) from %s: %s", statusCode, ip, err)
-	http.Error(w, err.Error(), statusCode)
+func (s *HTTPServer) processError(w http.ResponseWriter, r *http.Request, statusCode int, err error) {
+	requestID := requestIDFromContext(r.Context())
+
+	s.logger.LogAttrs(r.Context(), slog.LevelError, "HTTP error",
+		slog.String("request_id", requestID),
+		slog.Int("status", statusCode),
+		slog.String("remote_addr", r.RemoteAddr),
+		slog.String("error", err.Error()),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(statusCode)
+
+	_ = json.NewEncoder(w).Encode(models.ErrorResponse{
+		Error:     err.Error(),
+		RequestID: requestID,
+	})
 }
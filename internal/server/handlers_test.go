@@ -2,7 +2,7 @@ package server
 
 import (
 	"bytes"
-	"log"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -56,8 +56,8 @@ func TestHandler_CreateTask(t *testing.T) {
 			t.Parallel()
 
 			server := &HTTPServer{
-				config:      *config.LoadConfig(),
-				logger:      log.New(os.Stdout, "[HTTP Server] ", log.LstdFlags),
+				config:      config.Default(),
+				logger:      slog.New(slog.NewJSONHandler(os.Stdout, nil)),
 				taskService: test.mockSetup,
 			}
 
@@ -110,8 +110,8 @@ func TestHandler_DeleteTask(t *testing.T) {
 			t.Parallel()
 
 			server := &HTTPServer{
-				config:      *config.LoadConfig(),
-				logger:      log.New(os.Stdout, "[HTTP Server] ", log.LstdFlags),
+				config:      config.Default(),
+				logger:      slog.New(slog.NewJSONHandler(os.Stdout, nil)),
 				taskService: test.mockSetup,
 			}
 
@@ -165,8 +165,8 @@ func TestHandler_GetTask(t *testing.T) {
 			t.Parallel()
 
 			server := &HTTPServer{
-				config:      *config.LoadConfig(),
-				logger:      log.New(os.Stdout, "[HTTP Server] ", log.LstdFlags),
+				config:      config.Default(),
+				logger:      slog.New(slog.NewJSONHandler(os.Stdout, nil)),
 				taskService: test.mockSetup,
 			}
 
@@ -209,8 +209,8 @@ func TestHandler_GetAllTasks(t *testing.T) {
 			t.Parallel()
 
 			server := &HTTPServer{
-				config:      *config.LoadConfig(),
-				logger:      log.New(os.Stdout, "[HTTP Server] ", log.LstdFlags),
+				config:      config.Default(),
+				logger:      slog.New(slog.NewJSONHandler(os.Stdout, nil)),
 				taskService: test.mockSetup,
 			}
 
@@ -275,8 +275,8 @@ func TestHandler_UpdateTask(t *testing.T) {
 			t.Parallel()
 
 			server := &HTTPServer{
-				config:      *config.LoadConfig(),
-				logger:      log.New(os.Stdout, "[HTTP Server] ", log.LstdFlags),
+				config:      config.Default(),
+				logger:      slog.New(slog.NewJSONHandler(os.Stdout, nil)),
 				taskService: test.mockSetup,
 			}
 
@@ -294,3 +294,64 @@ func TestHandler_UpdateTask(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_RescheduleTask(t *testing.T) {
+	tests := map[string]struct {
+		taskID         string
+		requestBody    string
+		mockSetup      *service.TaskServiceMock
+		expectedStatus int
+	}{
+		"success": {
+			taskID:         "task1",
+			requestBody:    `{"due_at":"2024-03-04T09:00:00Z"}`,
+			mockSetup:      &service.TaskServiceMock{},
+			expectedStatus: http.StatusOK,
+		},
+
+		"bad request without due date": {
+			taskID:         "task1",
+			requestBody:    `{}`,
+			mockSetup:      &service.TaskServiceMock{},
+			expectedStatus: http.StatusBadRequest,
+		},
+
+		"not found": {
+			taskID:         service.NotFound,
+			requestBody:    `{"due_at":"2024-03-04T09:00:00Z"}`,
+			mockSetup:      &service.TaskServiceMock{},
+			expectedStatus: http.StatusNotFound,
+		},
+
+		"interval server error": {
+			taskID:         "task1",
+			requestBody:    `{"due_at":"2024-03-04T09:00:00Z"}`,
+			mockSetup:      &service.TaskServiceMock{ForceInternalError: true},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := &HTTPServer{
+				config:      config.Default(),
+				logger:      slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+				taskService: test.mockSetup,
+			}
+
+			body := bytes.NewBufferString(test.requestBody)
+			req := httptest.NewRequest(http.MethodPost, "/tasks/{id}/reschedule", body)
+			req.SetPathValue("id", test.taskID)
+
+			w := httptest.NewRecorder()
+
+			server.handleRescheduleTask(w, req)
+
+			if test.expectedStatus != w.Code {
+				t.Fatalf("test-case: (%q); returned %v; expected %v", name, w.Code, test.expectedStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"task-tracker/internal/models"
+)
+
+const (
+	healthCheckTimeout = 2 * time.Second
+
+	statusOK   = "ok"
+	statusFail = "fail"
+)
+
+type checkResult struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type healthReport struct {
+	Status string                 `json:"status"`
+	Checks map[string]checkResult `json:"checks,omitempty"`
+}
+
+func (s *HTTPServer) handleLiveness(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.handleError(w, r, models.ErrMethodNotAllowed)
+		return
+	}
+
+	writeHealthReport(w, healthReport{Status: statusOK})
+}
+
+func (s *HTTPServer) handleReadiness(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.handleError(w, r, models.ErrMethodNotAllowed)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), healthCheckTimeout)
+	defer cancel()
+
+	checks := map[string]func(ctx context.Context) error{
+		"repository": s.repo.Ping,
+		"shutdown": func(context.Context) error {
+			if s.shuttingDown.Load() {
+				return fmt.Errorf("server is shutting down")
+			}
+
+			return nil
+		},
+	}
+
+	if s.migrator != nil {
+		checks["migrations"] = func(ctx context.Context) error {
+			pending, err := s.migrator.Pending(ctx)
+			if err != nil {
+				return err
+			}
+
+			if pending > 0 {
+				return fmt.Errorf("%d migrations pending", pending)
+			}
+
+			return nil
+		}
+	}
+
+	report := healthReport{
+		Status: statusOK,
+		Checks: make(map[string]checkResult, len(checks)),
+	}
+
+	for name, check := range checks {
+		result := checkResult{Status: statusOK}
+
+		if err := check(ctx); err != nil {
+			result = checkResult{Status: statusFail, Error: err.Error()}
+			report.Status = statusFail
+		}
+
+		report.Checks[name] = result
+	}
+
+	writeHealthReport(w, report)
+}
+
+func writeHealthReport(w http.ResponseWriter, report healthReport) {
+	statusCode := http.StatusOK
+	if report.Status != statusOK {
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+
+	_ = json.NewEncoder(w).Encode(report)
+}
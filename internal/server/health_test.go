@@ -0,0 +1,87 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"task-tracker/internal/repository"
+)
+
+func TestHandler_Liveness(t *testing.T) {
+	server := &HTTPServer{
+		logger: slog.New(slog.NewJSONHandler(io.Discard, nil)),
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/healthz", http.NoBody)
+	w := httptest.NewRecorder()
+
+	server.handleLiveness(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("returned %v; expected %v", w.Code, http.StatusOK)
+	}
+}
+
+func TestHandler_Readiness(t *testing.T) {
+	tests := map[string]struct {
+		repo           *repository.MockTaskRepository
+		shuttingDown   bool
+		expectedStatus int
+		failedCheck    string
+	}{
+		"ready": {
+			repo:           &repository.MockTaskRepository{},
+			expectedStatus: http.StatusOK,
+		},
+
+		"repository unreachable": {
+			repo:           &repository.MockTaskRepository{ForceRepositoryError: true},
+			expectedStatus: http.StatusServiceUnavailable,
+			failedCheck:    "repository",
+		},
+
+		"shutting down": {
+			repo:           &repository.MockTaskRepository{},
+			shuttingDown:   true,
+			expectedStatus: http.StatusServiceUnavailable,
+			failedCheck:    "shutdown",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := &HTTPServer{
+				logger: slog.New(slog.NewJSONHandler(io.Discard, nil)),
+				repo:   test.repo,
+			}
+			server.shuttingDown.Store(test.shuttingDown)
+
+			req := httptest.NewRequest(http.MethodGet, "/readyz", http.NoBody)
+			w := httptest.NewRecorder()
+
+			server.handleReadiness(w, req)
+
+			if w.Code != test.expectedStatus {
+				t.Fatalf("test-case: (%q); returned %v; expected %v", name, w.Code, test.expectedStatus)
+			}
+
+			var report healthReport
+
+			if err := json.NewDecoder(w.Body).Decode(&report); err != nil {
+				t.Fatalf("test-case: (%q); unexpected error: %v", name, err)
+			}
+
+			for check, result := range report.Checks {
+				if (check == test.failedCheck) != (result.Status == statusFail) {
+					t.Fatalf("test-case: (%q); check %q has status %q", name, check, result.Status)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"task-tracker/internal/config"
+)
+
+func newTestServer(t *testing.T) *HTTPServer {
+	t.Helper()
+
+	server := NewHTTPServer(config.Config{
+		ServerPort:      0,
+		InMemory:        true,
+		LogLevel:        "error",
+		ShutdownTimeout: time.Second,
+	})
+
+	if err := server.ConfigureServer(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return server
+}
+
+func TestServer_ServeUntilCanceled(t *testing.T) {
+	server := newTestServer(t)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+
+	go func() {
+		served <- server.Serve(ctx, ln)
+	}()
+
+	baseURL := "http://" + ln.Addr().String()
+
+	resp, err := http.Post(baseURL+"/tasks", "application/json",
+		strings.NewReader(`{"title":"title", "description":"description", "status":"todo"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("returned %v; expected %v", resp.StatusCode, http.StatusCreated)
+	}
+
+	cancel()
+
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("server didn't stop after context cancellation")
+	}
+
+	if !server.shuttingDown.Load() {
+		t.Fatalf("server isn't marked as shutting down")
+	}
+
+	if _, err := http.Get(baseURL + "/healthz"); err == nil {
+		t.Fatalf("server still accepts connections after shutdown")
+	}
+}
+
+func TestServer_ShutdownDelay(t *testing.T) {
+	server := NewHTTPServer(config.Config{
+		InMemory:        true,
+		LogLevel:        "error",
+		ShutdownTimeout: time.Second,
+		ShutdownDelay:   time.Second,
+	})
+
+	if err := server.ConfigureServer(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+
+	go func() {
+		served <- server.Serve(ctx, ln)
+	}()
+
+	baseURL := "http://" + ln.Addr().String()
+
+	cancel()
+
+	for !server.shuttingDown.Load() {
+		time.Sleep(time.Millisecond)
+	}
+
+	for path, expected := range map[string]int{"/readyz": http.StatusServiceUnavailable, "/healthz": http.StatusOK} {
+		resp, err := http.Get(baseURL + path)
+		if err != nil {
+			t.Fatalf("test-case: (%q); unexpected error during the shutdown delay: %v", path, err)
+		}
+
+		resp.Body.Close()
+
+		if resp.StatusCode != expected {
+			t.Fatalf("test-case: (%q); returned %v; expected %v", path, resp.StatusCode, expected)
+		}
+	}
+
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("server didn't stop after the shutdown delay")
+	}
+}
+
+func TestServer_StartFailsWhenPortIsTaken(t *testing.T) {
+	ln, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer ln.Close()
+
+	server := newTestServer(t)
+	server.server.Addr = ln.Addr().String()
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- server.Start(context.Background())
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatalf("expected an error when the port is taken")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("server didn't return on listen failure")
+	}
+}
@@ -0,0 +1,178 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"task-tracker/internal/auth"
+	"task-tracker/internal/httpx"
+	"task-tracker/internal/models"
+)
+
+const (
+	requestIDHeader    = "X-Request-ID"
+	maxRequestIDLength = 128
+)
+
+type requestIDKey struct{}
+
+func requestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+func (s *HTTPServer) withRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.New().String()
+		}
+
+		w.Header().Set(requestIDHeader, id)
+
+		ctx := context.WithValue(r.Context(), requestIDKey{}, id)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for _, c := range id {
+		if c < '!' || c > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *HTTPServer) withAccessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := httpx.NewStatusRecorder(w)
+
+		next.ServeHTTP(rec, r)
+
+		s.logger.LogAttrs(r.Context(), slog.LevelInfo, "HTTP request",
+			slog.String("request_id", requestIDFromContext(r.Context())),
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.Int("status", rec.Status()),
+			slog.Duration("latency", time.Since(start)),
+			slog.Int("bytes", rec.Bytes()),
+			slog.String("remote_addr", r.RemoteAddr),
+		)
+	})
+}
+
+func scopeByMethod(r *http.Request) models.Scope {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return models.ScopeRead
+	default:
+		return models.ScopeWrite
+	}
+}
+
+func adminScope(*http.Request) models.Scope {
+	return models.ScopeAdmin
+}
+
+// readScope lets read-only callers manage credentials that grant no more than read,
+// such as feed tokens.
+func readScope(*http.Request) models.Scope {
+	return models.ScopeRead
+}
+
+func (s *HTTPServer) withAuth(scope func(r *http.Request) models.Scope, next http.HandlerFunc) http.Handler {
+	if !s.config.AuthEnabled {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := bearerToken(r)
+		if !ok {
+			s.unauthorized(w, r, models.ErrUnauthorized)
+			return
+		}
+
+		principal, err := s.authenticateRequest(r, token)
+		if err != nil {
+			s.unauthorized(w, r, err)
+			return
+		}
+
+		if !principal.HasScope(scope(r)) {
+			s.handleError(w, r, models.ErrForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+	})
+}
+
+// The rate limiter keeps the authentication in the request context, so withAuth doesn't
+// verify the token twice.
+type authentication struct {
+	token     string
+	principal auth.Principal
+	err       error
+}
+
+type authenticationKey struct{}
+
+func (s *HTTPServer) authenticateRequest(r *http.Request, token string) (auth.Principal, error) {
+	if done, ok := r.Context().Value(authenticationKey{}).(authentication); ok && done.token == token {
+		return done.principal, done.err
+	}
+
+	return s.authenticate(r.Context(), token)
+}
+
+func (s *HTTPServer) authenticate(ctx context.Context, token string) (auth.Principal, error) {
+	if auth.IsAPIKey(token) {
+		return s.apiKeys.Authenticate(ctx, token)
+	}
+
+	if s.jwt == nil {
+		return auth.Principal{}, models.ErrUnauthorized
+	}
+
+	return s.jwt.Verify(ctx, token)
+}
+
+func (s *HTTPServer) unauthorized(w http.ResponseWriter, r *http.Request, err error) {
+	w.Header().Add("WWW-Authenticate", `Bearer realm="task-tracker"`)
+	s.handleError(w, r, err)
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+
+	return token, token != ""
+}
+
+func (s *HTTPServer) withWorkspace(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := s.workspaces.Enter(r.Context(), r.PathValue("ws"))
+		if err != nil {
+			s.handleError(w, r, err)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	}
+}
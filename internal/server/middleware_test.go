@@ -0,0 +1,115 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"task-tracker/internal/models"
+	"task-tracker/internal/service"
+)
+
+func TestMiddleware_RequestID(t *testing.T) {
+	tests := map[string]struct {
+		requestID string
+		generated bool
+	}{
+		"request id is propagated": {
+			requestID: "client-request-1",
+			generated: false,
+		},
+
+		"request id is generated when missing": {
+			requestID: "",
+			generated: true,
+		},
+
+		"malformed request id is replaced": {
+			requestID: "bad id\nwith newline",
+			generated: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := &HTTPServer{
+				logger:      slog.New(slog.NewJSONHandler(io.Discard, nil)),
+				taskService: &service.TaskServiceMock{},
+			}
+
+			var fromContext string
+
+			handler := server.withRequestID(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				fromContext = requestIDFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/tasks", http.NoBody)
+			if test.requestID != "" {
+				req.Header.Set(requestIDHeader, test.requestID)
+			}
+
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			got := w.Header().Get(requestIDHeader)
+
+			if got == "" || got != fromContext {
+				t.Fatalf("test-case: (%q); header %q and context %q must match and be set", name, got, fromContext)
+			}
+
+			if test.generated == (got == test.requestID) {
+				t.Fatalf("test-case: (%q); returned %q; client sent %q", name, got, test.requestID)
+			}
+		})
+	}
+}
+
+func TestMiddleware_ErrorResponseIncludesRequestID(t *testing.T) {
+	var logs strings.Builder
+
+	server := &HTTPServer{
+		logger:      slog.New(slog.NewJSONHandler(&logs, nil)),
+		taskService: &service.TaskServiceMock{},
+	}
+
+	handler := server.withRequestID(server.withAccessLog(http.HandlerFunc(server.handleGetTask)))
+
+	req := httptest.NewRequest(http.MethodGet, "/tasks/{id}", http.NoBody)
+	req.SetPathValue("id", service.NotFound)
+	req.Header.Set(requestIDHeader, "req-42")
+
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("returned %v; expected %v", w.Code, http.StatusNotFound)
+	}
+
+	var body models.ErrorResponse
+
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if body.RequestID != "req-42" || body.Error != models.ErrTaskNotFound.Error() {
+		t.Fatalf("returned %+v; expected request id %q and error %q", body, "req-42", models.ErrTaskNotFound)
+	}
+
+	for _, expected := range []string{`"msg":"HTTP error"`, `"msg":"HTTP request"`, `"status":404`} {
+		if !strings.Contains(logs.String(), expected) {
+			t.Fatalf("logs %s; expected to contain %s", logs.String(), expected)
+		}
+	}
+
+	if strings.Count(logs.String(), `"request_id":"req-42"`) != 2 {
+		t.Fatalf("logs %s; expected request id in error and access log", logs.String())
+	}
+}
@@ -0,0 +1,46 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"task-tracker/internal/clock"
+	"task-tracker/internal/notify"
+	"task-tracker/internal/repository"
+	"task-tracker/internal/service"
+)
+
+func (s *HTTPServer) configureNotifications(repo repository.TaskRepository, clk clock.Clock, interval time.Duration) {
+	digestHour := -1
+	if s.config.DigestEnabled {
+		digestHour = s.config.DigestHour
+	}
+
+	reminders := service.NewReminderScheduler(repo, s.notificationRepository(), s.notifier(), clk, s.logger, digestHour)
+	s.addWorker(func(ctx context.Context) { reminders.Run(ctx, interval) })
+}
+
+func (s *HTTPServer) notifier() notify.Notifier {
+	switch s.config.Notifier {
+	case "smtp":
+		return notify.NewSMTPNotifier(notify.SMTPConfig{
+			Addr:     s.config.SMTPAddr,
+			From:     s.config.SMTPFrom,
+			Username: s.config.SMTPUsername,
+			Password: s.config.SMTPPassword,
+			Domain:   s.config.SMTPDomain,
+		})
+	case "webhook":
+		return notify.NewWebhookNotifier(s.config.WebhookURL, s.config.WebhookSecret)
+	default:
+		return notify.NewLogNotifier(s.logger)
+	}
+}
+
+func (s *HTTPServer) notificationRepository() repository.NotificationRepository {
+	if s.pool == nil {
+		return repository.NewMemoryNotificationRepository()
+	}
+
+	return repository.NewPostgresNotificationRepository(s.pool)
+}
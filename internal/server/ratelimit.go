@@ -0,0 +1,199 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	"task-tracker/internal/clock"
+	"task-tracker/internal/config"
+	"task-tracker/internal/models"
+	"task-tracker/internal/ratelimit"
+)
+
+const (
+	rateLimitWindow = time.Minute
+	// Buckets untouched for rateLimitIdle are full again and can be dropped.
+	rateLimitIdle = 2 * rateLimitWindow
+)
+
+var rateLimitExempt = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+	"/metrics": true,
+}
+
+func (s *HTTPServer) configureRateLimit(clk clock.Clock) error {
+	proxies, err := config.ParseTrustedProxies(s.config.TrustedProxies)
+	if err != nil {
+		return err
+	}
+
+	s.trustedProxies = proxies
+
+	if !s.config.RateLimitEnabled {
+		return nil
+	}
+
+	if s.config.RateLimitBackend == "postgres" {
+		s.limiter = ratelimit.NewPostgresLimiter(s.pool)
+	} else {
+		s.limiter = ratelimit.NewMemoryLimiter(clk)
+	}
+
+	s.addWorker(s.cleanupRateLimits)
+
+	return nil
+}
+
+// Limiter failures let the request through.
+func (s *HTTPServer) withRateLimit(next http.Handler) http.Handler {
+	if s.limiter == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if rateLimitExempt[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		kind, limit := "write", ratelimit.Limit{Requests: s.config.RateLimitWrites, Window: rateLimitWindow}
+		if davScope(r) == models.ScopeRead {
+			kind, limit = "read", ratelimit.Limit{Requests: s.config.RateLimitReads, Window: rateLimitWindow}
+		}
+
+		// The address is charged before bearer credentials are verified, so invalid
+		// tokens can't be tried faster than its limit allows.
+		res, err := s.limiter.Allow(r.Context(), kind+":ip:"+s.clientIP(r), limit)
+
+		var key string
+		if err == nil && res.Allowed {
+			key, r = s.rateLimitKey(r)
+		}
+
+		if key != "" {
+			var keyRes ratelimit.Result
+
+			keyRes, err = s.limiter.Allow(r.Context(), kind+":"+key, limit)
+			if !keyRes.Allowed || keyRes.Remaining < res.Remaining {
+				res = keyRes
+			}
+		}
+
+		if err != nil {
+			s.logger.Warn("Rate limiter failed, allowing request",
+				slog.String("request_id", requestIDFromContext(r.Context())),
+				slog.String("error", err.Error()))
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		header := w.Header()
+		header.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+		header.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		header.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+		header.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit.Requests, int(limit.Window.Seconds())))
+
+		if !res.Allowed {
+			header.Set("Retry-After", strconv.Itoa(max(ceilSeconds(res.RetryAfter), 1)))
+			s.handleError(w, r, models.ErrTooManyRequests)
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// rateLimitKey is empty unless bearer credentials verify. The verification is kept in
+// the returned request for withAuth.
+func (s *HTTPServer) rateLimitKey(r *http.Request) (string, *http.Request) {
+	token, ok := bearerToken(r)
+	if !ok || !s.config.AuthEnabled {
+		return "", r
+	}
+
+	principal, err := s.authenticate(r.Context(), token)
+	r = r.WithContext(context.WithValue(r.Context(), authenticationKey{}, authentication{token, principal, err}))
+
+	switch {
+	case err != nil:
+		return "", r
+	case principal.KeyID != "":
+		return "key:" + principal.KeyID, r
+	default:
+		return "sub:" + principal.Subject, r
+	}
+}
+
+// clientIP is the peer address, or when the peer is a trusted proxy, the rightmost
+// X-Forwarded-For entry that isn't one. Entries left of it are client-controlled.
+func (s *HTTPServer) clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil || !s.trustedProxy(addr) {
+		return host
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			break
+		}
+
+		addr = hop.Unmap()
+
+		if !s.trustedProxy(addr) {
+			break
+		}
+	}
+
+	return addr.String()
+}
+
+func (s *HTTPServer) trustedProxy(addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	for _, prefix := range s.trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *HTTPServer) cleanupRateLimits(ctx context.Context) {
+	ticker := time.NewTicker(rateLimitWindow)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.limiter.Cleanup(ctx, rateLimitIdle); err != nil && ctx.Err() == nil {
+				s.logger.Warn("Failed to clean up rate limit buckets", slog.String("error", err.Error()))
+			}
+		}
+	}
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,232 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"task-tracker/internal/config"
+	"task-tracker/internal/models"
+)
+
+func TestServer_RateLimit(t *testing.T) {
+	server := NewHTTPServer(config.Config{
+		InMemory:         true,
+		LogLevel:         "error",
+		ShutdownTimeout:  time.Second,
+		RateLimitEnabled: true,
+		RateLimitBackend: "memory",
+		RateLimitReads:   2,
+		RateLimitWrites:  1,
+	})
+
+	if err := server.ConfigureServer(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	type request struct {
+		method         string
+		path           string
+		token          string
+		expectedStatus int
+	}
+
+	body := `{"title":"title", "description":"description", "status":"todo"}`
+
+	requests := []request{
+		{method: http.MethodGet, path: "/tasks", expectedStatus: http.StatusOK},
+		{method: http.MethodGet, path: "/tasks", expectedStatus: http.StatusOK},
+		{method: http.MethodGet, path: "/tasks", expectedStatus: http.StatusTooManyRequests},
+		{method: http.MethodPost, path: "/tasks", expectedStatus: http.StatusCreated},
+		{method: http.MethodPost, path: "/tasks", expectedStatus: http.StatusTooManyRequests},
+		// Tokens that can't be verified don't get a bucket of their own.
+		{method: http.MethodGet, path: "/tasks", token: "first", expectedStatus: http.StatusTooManyRequests},
+		{method: http.MethodGet, path: "/healthz", expectedStatus: http.StatusOK},
+	}
+
+	for i, request := range requests {
+		headers := map[string]string{}
+		if request.token != "" {
+			headers["Authorization"] = "Bearer " + request.token
+		}
+
+		resp, err := server.Handle(request.method, request.path, strings.NewReader(body), headers)
+		if err != nil {
+			t.Fatalf("request %d: unexpected error: %v", i, err)
+		}
+
+		if resp.StatusCode != request.expectedStatus {
+			t.Fatalf("request %d: returned %v; expected %v", i, resp.StatusCode, request.expectedStatus)
+		}
+
+		if request.path == "/healthz" {
+			if resp.Header.Get("RateLimit-Limit") != "" {
+				t.Fatalf("request %d: exempt path has rate limit headers", i)
+			}
+
+			continue
+		}
+
+		if resp.Header.Get("RateLimit-Policy") == "" || resp.Header.Get("RateLimit-Remaining") == "" {
+			t.Fatalf("request %d: rate limit headers are missing: %v", i, resp.Header)
+		}
+
+		if (resp.StatusCode == http.StatusTooManyRequests) != (resp.Header.Get("Retry-After") != "") {
+			t.Fatalf("request %d: Retry-After is %q", i, resp.Header.Get("Retry-After"))
+		}
+	}
+}
+
+func TestServer_RateLimitCalDAV(t *testing.T) {
+	server := NewHTTPServer(config.Config{
+		InMemory:         true,
+		LogLevel:         "error",
+		ShutdownTimeout:  time.Second,
+		RateLimitEnabled: true,
+		RateLimitBackend: "memory",
+		RateLimitReads:   1,
+		RateLimitWrites:  1,
+	})
+
+	if err := server.ConfigureServer(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// CalDAV clients poll with PROPFIND, which takes from the read bucket.
+	requests := []struct {
+		method         string
+		path           string
+		expectedStatus int
+	}{
+		{method: "PROPFIND", path: "/dav/tasks/", expectedStatus: http.StatusMultiStatus},
+		{method: "PROPFIND", path: "/dav/tasks/", expectedStatus: http.StatusTooManyRequests},
+		{method: http.MethodPost, path: "/tasks", expectedStatus: http.StatusCreated},
+	}
+
+	for i, request := range requests {
+		resp, err := server.Handle(request.method, request.path,
+			strings.NewReader(`{"title":"title", "description":"description", "status":"todo"}`), nil)
+		if err != nil {
+			t.Fatalf("request %d: unexpected error: %v", i, err)
+		}
+
+		if resp.StatusCode != request.expectedStatus {
+			t.Fatalf("request %d: returned %v; expected %v", i, resp.StatusCode, request.expectedStatus)
+		}
+	}
+}
+
+func TestServer_RateLimitByCredentials(t *testing.T) {
+	server := NewHTTPServer(config.Config{
+		InMemory:         true,
+		AuthEnabled:      true,
+		LogLevel:         "error",
+		ShutdownTimeout:  time.Second,
+		RateLimitEnabled: true,
+		RateLimitBackend: "memory",
+		RateLimitReads:   1,
+		RateLimitWrites:  1,
+		TrustedProxies:   "192.0.2.1",
+	})
+
+	if err := server.ConfigureServer(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, key, err := server.apiKeys.Create(context.Background(),
+		models.CreateAPIKeyRequest{Name: "reader", Scopes: []models.Scope{models.ScopeRead}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	requests := []struct {
+		client         string
+		token          string
+		expectedStatus int
+	}{
+		{client: "203.0.113.1", token: "tt_made_up1", expectedStatus: http.StatusUnauthorized},
+		// The address is out of tokens, so the credentials aren't even verified.
+		{client: "203.0.113.1", token: "tt_made_up2", expectedStatus: http.StatusTooManyRequests},
+		{client: "203.0.113.1", token: key, expectedStatus: http.StatusTooManyRequests},
+		{client: "203.0.113.2", token: key, expectedStatus: http.StatusOK},
+		// The key's bucket follows it to other addresses.
+		{client: "203.0.113.3", token: key, expectedStatus: http.StatusTooManyRequests},
+		{client: "203.0.113.4", token: "not.a.jwt", expectedStatus: http.StatusUnauthorized},
+	}
+
+	for i, request := range requests {
+		resp, err := server.Handle(http.MethodGet, "/tasks", http.NoBody, map[string]string{
+			"Authorization":   "Bearer " + request.token,
+			"X-Forwarded-For": request.client,
+		})
+		if err != nil {
+			t.Fatalf("request %d: unexpected error: %v", i, err)
+		}
+
+		if resp.StatusCode != request.expectedStatus {
+			t.Fatalf("request %d: returned %v; expected %v", i, resp.StatusCode, request.expectedStatus)
+		}
+	}
+}
+
+func TestServer_ClientIP(t *testing.T) {
+	server := &HTTPServer{
+		trustedProxies: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+	}
+
+	tests := map[string]struct {
+		remoteAddr    string
+		forwardedFor  string
+		expectedValue string
+	}{
+		"direct client": {
+			remoteAddr:    "203.0.113.7:5000",
+			expectedValue: "203.0.113.7",
+		},
+
+		"untrusted peer can't spoof": {
+			remoteAddr:    "203.0.113.7:5000",
+			forwardedFor:  "198.51.100.1",
+			expectedValue: "203.0.113.7",
+		},
+
+		"trusted proxy": {
+			remoteAddr:    "10.0.0.2:5000",
+			forwardedFor:  "198.51.100.1",
+			expectedValue: "198.51.100.1",
+		},
+
+		"chain of trusted proxies": {
+			remoteAddr:    "10.0.0.2:5000",
+			forwardedFor:  "192.0.2.9, 198.51.100.1, 10.0.0.3",
+			expectedValue: "198.51.100.1",
+		},
+
+		"malformed entry stops the walk": {
+			remoteAddr:    "10.0.0.2:5000",
+			forwardedFor:  "unknown",
+			expectedValue: "10.0.0.2",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/tasks", http.NoBody)
+			req.RemoteAddr = test.remoteAddr
+
+			if test.forwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", test.forwardedFor)
+			}
+
+			if ip := server.clientIP(req); ip != test.expectedValue {
+				t.Fatalf("test-case: (%q); returned %v; expected %v", name, ip, test.expectedValue)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"task-tracker/internal/models"
+)
+
+func (s *HTTPServer) handleSkipTask(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		s.handleError(w, r, models.ErrMethodNotAllowed)
+		return
+	}
+
+	task, err := s.taskService.Skip(r.Context(), r.PathValue("id"))
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	s.writeJSON(w, r, http.StatusOK, task)
+}
+
+func (s *HTTPServer) handleRescheduleTask(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		s.handleError(w, r, models.ErrMethodNotAllowed)
+		return
+	}
+
+	var request models.RescheduleTaskRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		s.handleError(w, r, models.ErrBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	if err := request.Validate(); err != nil {
+		s.handleError(w, r, fmt.Errorf("request validation: %w", err))
+		return
+	}
+
+	task, err := s.taskService.Reschedule(r.Context(), r.PathValue("id"), *request.DueAt)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	s.writeJSON(w, r, http.StatusOK, task)
+}
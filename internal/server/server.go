@@ -3,39 +3,134 @@ package server
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"io"
-	"log"
+	"log/slog"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/netip"
 	"os"
-	"os/signal"
-	"syscall"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel/trace"
+
+	"task-tracker/internal/auth"
+	"task-tracker/internal/clock"
 	"task-tracker/internal/config"
+	"task-tracker/internal/events"
+	"task-tracker/internal/metrics"
+	"task-tracker/internal/migrate"
+	"task-tracker/internal/ratelimit"
 	"task-tracker/internal/repository"
 	"task-tracker/internal/service"
+	"task-tracker/internal/tracing"
+	"task-tracker/migrations"
+)
+
+const (
+	defaultShutdownTimeout = 10 * time.Second
+
+	defaultTestIssuer   = "task-tracker-test-issuer"
+	defaultTestAudience = "task-tracker"
 )
 
 type HTTPServer struct {
 	config      config.Config
-	logger      *log.Logger
+	logger      *slog.Logger
 	taskService service.TaskService
+	importer    *service.Importer
+	workspaces  *service.WorkspaceService
+	repo        repository.TaskRepository
+	migrator    *migrate.Runner
+	pool        *pgxpool.Pool
+	bus         *events.Bus
+	workers     []func(ctx context.Context)
 	server      *http.Server
 	mux         *http.ServeMux
-	cancelFunc  context.CancelFunc
+	handler     http.Handler
+	cache       *repository.CachedTaskRepository
+	metrics     *metrics.Metrics
+	apiKeys     *auth.APIKeyService
+	feedTokens  *auth.FeedTokenService
+	jwt         *auth.JWTVerifier
+	testIssuer  *auth.TestIssuer
+	limiter     ratelimit.Limiter
+
+	trustedProxies []netip.Prefix
+
+	shuttingDown atomic.Bool
+
+	tracerProvider  trace.TracerProvider
+	tracingShutdown func(context.Context) error
+}
+
+type Option func(*HTTPServer)
+
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(s *HTTPServer) {
+		s.tracerProvider = provider
+	}
 }
 
-func NewHTTPServer(config config.Config) *HTTPServer {
-	return &HTTPServer{
+func NewHTTPServer(config config.Config, options ...Option) *HTTPServer {
+	s := &HTTPServer{
 		config: config,
 	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	return s
 }
 
 func (s *HTTPServer) setupRoutes(mux *http.ServeMux) {
-	mux.HandleFunc("/tasks", s.handleTasks)
-	mux.HandleFunc("/tasks/{id}", s.handleTaskByID)
+	mux.Handle("/tasks", s.withAuth(scopeByMethod, s.withWorkspace(s.handleTasks)))
+	mux.Handle("/tasks.txt", s.withAuth(scopeByMethod, s.withWorkspace(s.handleTodoTxt)))
+	mux.Handle("/tasks/export", s.withAuth(scopeByMethod, s.withWorkspace(s.handleExportTasks)))
+	mux.Handle("/tasks/import", s.withAuth(scopeByMethod, s.withWorkspace(s.handleImportTasks)))
+	mux.Handle("/tasks/{id}", s.withAuth(scopeByMethod, s.withWorkspace(s.handleTaskByID)))
+	mux.Handle("/tasks/{id}/skip", s.withAuth(scopeByMethod, s.withWorkspace(s.handleSkipTask)))
+	mux.Handle("/tasks/{id}/reschedule", s.withAuth(scopeByMethod, s.withWorkspace(s.handleRescheduleTask)))
+	mux.Handle("/workspaces", s.withAuth(scopeByMethod, s.handleWorkspaces))
+	mux.Handle("/workspaces/{ws}", s.withAuth(scopeByMethod, s.withWorkspace(s.handleWorkspaceByID)))
+	mux.Handle("/workspaces/{ws}/members", s.withAuth(scopeByMethod, s.withWorkspace(s.handleWorkspaceMembers)))
+	mux.Handle("/workspaces/{ws}/members/{subject}", s.withAuth(scopeByMethod, s.withWorkspace(s.handleWorkspaceMember)))
+	mux.Handle("/workspaces/{ws}/tasks", s.withAuth(scopeByMethod, s.withWorkspace(s.handleTasks)))
+	mux.Handle("/workspaces/{ws}/tasks.txt", s.withAuth(scopeByMethod, s.withWorkspace(s.handleTodoTxt)))
+	mux.Handle("/workspaces/{ws}/tasks/export", s.withAuth(scopeByMethod, s.withWorkspace(s.handleExportTasks)))
+	mux.Handle("/workspaces/{ws}/tasks/import", s.withAuth(scopeByMethod, s.withWorkspace(s.handleImportTasks)))
+	mux.Handle("/workspaces/{ws}/tasks/{id}", s.withAuth(scopeByMethod, s.withWorkspace(s.handleTaskByID)))
+	mux.Handle("/workspaces/{ws}/tasks/{id}/skip", s.withAuth(scopeByMethod, s.withWorkspace(s.handleSkipTask)))
+	mux.Handle("/workspaces/{ws}/tasks/{id}/reschedule", s.withAuth(scopeByMethod, s.withWorkspace(s.handleRescheduleTask)))
+	mux.Handle("/calendar.ics", s.withFeedAuth(s.withWorkspace(s.handleCalendar)))
+	mux.Handle("/workspaces/{ws}/calendar.ics", s.withFeedAuth(s.withWorkspace(s.handleCalendar)))
+	s.setupCalDAVRoutes(mux)
 	mux.HandleFunc("/swagger", s.handleSwagger)
+	mux.HandleFunc("/healthz", s.handleLiveness)
+	mux.HandleFunc("/readyz", s.handleReadiness)
+	mux.Handle("/metrics", s.metrics.Handler())
+
+	if s.apiKeys != nil {
+		mux.Handle("/apikeys", s.withAuth(adminScope, s.handleAPIKeys))
+		mux.Handle("/apikeys/{id}", s.withAuth(adminScope, s.handleAPIKeyByID))
+	}
+
+	if s.feedTokens != nil {
+		mux.Handle("/calendar/tokens", s.withAuth(readScope, s.handleFeedTokens))
+		mux.Handle("/calendar/tokens/{id}", s.withAuth(readScope, s.handleFeedTokenByID))
+	}
+
+	if s.testIssuer != nil {
+		mux.HandleFunc(auth.TestIssuerJWKSPath, s.handleTestIssuerJWKS)
+		mux.HandleFunc(auth.TestIssuerTokenPath, s.handleTestIssuerToken)
+	}
 
 	mux.Handle("/swagger/static/", http.StripPrefix("/swagger/static/", http.FileServer(http.Dir("docs/static"))))
 	mux.Handle("/swagger/swagger.yaml", http.StripPrefix("/swagger/", http.FileServer(http.Dir("docs"))))
@@ -50,7 +145,7 @@ func (s *HTTPServer) Handle(method, path string, body io.Reader, headers map[str
 
 	rr := httptest.NewRecorder()
 
-	s.mux.ServeHTTP(rr, req)
+	s.handler.ServeHTTP(rr, req)
 
 	res := &http.Response{
 		StatusCode: rr.Code,
@@ -61,71 +156,316 @@ func (s *HTTPServer) Handle(method, path string, body io.Reader, headers map[str
 	return res, nil
 }
 
+// ServeHTTP serves requests like Serve does, e.g. behind an httptest.Server.
+func (s *HTTPServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.handler.ServeHTTP(w, r)
+}
+
 func (s *HTTPServer) ConfigureServer(ctx context.Context) error {
-	pool, err := repository.CreateDBPool(ctx, s.config.DBConn)
+	logger, err := newLogger(s.config.LogLevel)
 	if err != nil {
 		return err
 	}
 
-	var repo repository.TaskRepository
+	s.logger = logger
+	s.bus = events.NewBus()
+	s.metrics = metrics.New()
+	s.tracingShutdown = func(context.Context) error { return nil }
 
-	if s.config.InMemory == "True" {
-		repo = repository.NewMemoryTaskRepository()
-	} else {
-		repo = repository.NewPostgresTaskRepository(pool)
+	if s.tracerProvider == nil {
+		s.tracerProvider, s.tracingShutdown, err = tracing.NewProvider(ctx, s.config.TracingExporter, s.config.OTLPEndpoint)
+		if err != nil {
+			return err
+		}
 	}
 
-	s.taskService = service.NewDefaultTaskService(repo)
-	s.logger = log.New(os.Stdout, "[HTTP Server] ", log.LstdFlags)
+	clk := clock.New()
+
+	repo, err := s.configureRepository(ctx, clk)
+	if err != nil {
+		return err
+	}
+
+	s.metrics.Register(metrics.NewTaskCollector(repo))
+
+	if s.config.CacheEnabled {
+		s.cache = repository.NewCachedTaskRepository(repo, s.config.CacheSize, s.config.CacheTTL)
+		s.metrics.Register(metrics.NewCacheCollector(s.cache))
+		s.addWorker(func(ctx context.Context) { s.cache.Watch(ctx, s.bus) })
+
+		repo = s.cache
+	}
+
+	s.repo = repo
+
+	scheduler := service.NewScheduler(repo, clk, s.logger)
+	s.addWorker(func(ctx context.Context) { scheduler.Run(ctx, s.config.SchedulerInterval) })
+
+	s.configureNotifications(repo, clk, s.config.SchedulerInterval)
+
+	if err := s.configureAuth(clk); err != nil {
+		s.release(ctx)
+		return err
+	}
+
+	if err := s.configureRateLimit(clk); err != nil {
+		s.release(ctx)
+		return err
+	}
+
+	var policy service.Policy = service.AllowAll{}
+	if s.config.AuthEnabled {
+		policy = service.RolePolicy{}
+	}
+
+	s.taskService = service.NewTracedTaskService(service.NewDefaultTaskService(repo, clk, policy), s.tracerProvider)
+	s.importer = service.NewImporter(s.taskService)
+	s.workspaces = service.NewWorkspaceService(s.workspaceRepository(clk), clk, policy)
 
 	s.mux = http.NewServeMux()
 
 	s.setupRoutes(s.mux)
 
+	s.handler = s.withRequestID(tracing.Middleware(s.tracerProvider, s.withAccessLog(s.metrics.Middleware(s.withRateLimit(s.mux)))))
+
 	s.server = &http.Server{
-		Addr:              ":" + s.config.ServerPort,
-		Handler:           s.mux,
+		Addr:              ":" + strconv.Itoa(s.config.ServerPort),
+		Handler:           s.handler,
+		ErrorLog:          slog.NewLogLogger(s.logger.Handler(), slog.LevelError),
 		ReadHeaderTimeout: 10 * time.Second,
 	}
 
 	return nil
 }
 
-func (s *HTTPServer) Start(ctx context.Context) error {
-	ctx, cancel := context.WithCancel(ctx)
+func (s *HTTPServer) configureRepository(ctx context.Context, clk clock.Clock) (repository.TaskRepository, error) {
+	if s.config.InMemory {
+		return metrics.NewInstrumentedTaskRepository(repository.NewMemoryTaskRepository(clk), "memory", s.metrics), nil
+	}
+
+	pool, err := repository.CreateDBPool(ctx, s.config.DBConn, tracing.NewQueryTracer(s.tracerProvider))
+	if err != nil {
+		return nil, err
+	}
+
+	s.migrator, err = migrate.NewRunner(pool, migrations.FS)
+	if err != nil {
+		pool.Close()
+		return nil, err
+	}
 
-	s.cancelFunc = cancel
+	if s.config.AutoMigrate {
+		if err := s.migrate(ctx); err != nil {
+			pool.Close()
+			return nil, err
+		}
+	}
+
+	s.pool = pool
+	s.metrics.Register(metrics.NewPoolCollector(pool))
 
-	return s.startHTTPServer(ctx)
+	listener := repository.NewTaskChangeListener(pool, s.bus, s.logger)
+	s.addWorker(listener.Run)
+
+	return metrics.NewInstrumentedTaskRepository(repository.NewPostgresTaskRepository(pool, clk), "postgres", s.metrics), nil
 }
 
-func (s *HTTPServer) startHTTPServer(ctx context.Context) error {
-	sigs := make(chan os.Signal, 1)
-	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+func (s *HTTPServer) configureAuth(clk clock.Clock) error {
+	if !s.config.AuthEnabled {
+		return nil
+	}
 
-	go func() {
-		s.logger.Println("Starting HTTP server on port", s.config.ServerPort)
+	s.apiKeys = auth.NewAPIKeyService(s.apiKeyRepository(), clk)
+	s.feedTokens = auth.NewFeedTokenService(s.feedTokenRepository(), clk)
+
+	verifierConfig := auth.JWTVerifierConfig{
+		Issuer:     s.config.JWTIssuer,
+		Audience:   s.config.JWTAudience,
+		RolesClaim: s.config.JWTRolesClaim,
+	}
 
-		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			s.logger.Fatalf("Server failed: %v", err)
+	switch {
+	case s.config.TestIssuer:
+		if verifierConfig.Issuer == "" {
+			verifierConfig.Issuer = defaultTestIssuer
 		}
-	}()
 
-	<-sigs
-	s.logger.Println("Shutting down server...")
+		if verifierConfig.Audience == "" {
+			verifierConfig.Audience = defaultTestAudience
+		}
+
+		issuer, err := auth.NewTestIssuer(verifierConfig.Issuer, verifierConfig.Audience, clk)
+		if err != nil {
+			return err
+		}
+
+		s.logger.Warn("Test token issuer is enabled, anyone can sign in as any user",
+			slog.String("token_path", auth.TestIssuerTokenPath))
 
-	s.cancelFunc()
+		s.testIssuer = issuer
+		s.jwt = auth.NewJWTVerifier(auth.StaticKeySet(issuer.KeySet()), verifierConfig, clk)
+	case s.config.JWKSSource != "":
+		keys, err := auth.LoadKeySet(s.config.JWKSSource)
+		if err != nil {
+			return err
+		}
 
-	shutdownCtx, shutdown := context.WithTimeout(ctx, 5*time.Second)
-	defer shutdown()
+		s.jwt = auth.NewJWTVerifier(keys, verifierConfig, clk)
+	}
 
-	err := s.server.Shutdown(shutdownCtx)
+	return nil
+}
 
+func (s *HTTPServer) apiKeyRepository() repository.APIKeyRepository {
+	if s.pool == nil {
+		return repository.NewMemoryAPIKeyRepository()
+	}
+
+	return repository.NewPostgresAPIKeyRepository(s.pool)
+}
+
+func (s *HTTPServer) feedTokenRepository() repository.FeedTokenRepository {
+	if s.pool == nil {
+		return repository.NewMemoryFeedTokenRepository()
+	}
+
+	return repository.NewPostgresFeedTokenRepository(s.pool)
+}
+
+func (s *HTTPServer) workspaceRepository(clk clock.Clock) repository.WorkspaceRepository {
+	if s.pool == nil {
+		return repository.NewMemoryWorkspaceRepository(clk)
+	}
+
+	return repository.NewPostgresWorkspaceRepository(s.pool)
+}
+
+func (s *HTTPServer) migrate(ctx context.Context) error {
+	applied, err := s.migrator.Up(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range applied {
+		s.logger.Info("Applied migration", slog.Int64("version", migration.Version), slog.String("name", migration.Name))
+	}
+
+	return nil
+}
+
+// Workers must return once their context is canceled, shutdown waits for them.
+func (s *HTTPServer) addWorker(worker func(ctx context.Context)) {
+	s.workers = append(s.workers, worker)
+}
+
+func (s *HTTPServer) Start(ctx context.Context) error {
+	ln, err := net.Listen("tcp", s.server.Addr)
 	if err != nil {
-		s.logger.Fatalf("Server forced to shutdown: %v", err)
+		s.release(ctx)
+		return fmt.Errorf("error listening on %s: %w", s.server.Addr, err)
+	}
+
+	return s.Serve(ctx, ln)
+}
+
+func (s *HTTPServer) Serve(ctx context.Context, ln net.Listener) error {
+	workersCtx, stopWorkers := context.WithCancel(context.WithoutCancel(ctx))
+	defer stopWorkers()
+
+	var workers sync.WaitGroup
+
+	for _, worker := range s.workers {
+		workers.Add(1)
+
+		go func() {
+			defer workers.Done()
+			worker(workersCtx)
+		}()
+	}
+
+	serveErr := make(chan error, 1)
+
+	go func() {
+		serveErr <- s.server.Serve(ln)
+	}()
+
+	s.logger.Info("Started HTTP server", slog.String("addr", ln.Addr().String()))
+
+	var err error
+
+	select {
+	case <-ctx.Done():
+	case err = <-serveErr:
+		if errors.Is(err, http.ErrServerClosed) {
+			err = nil
+		} else {
+			err = fmt.Errorf("server failed: %w", err)
+		}
+	}
+
+	s.logger.Info("Shutting down server")
+	s.shuttingDown.Store(true)
+
+	// Keep serving while /readyz fails, so load balancers stop sending requests
+	// before the listener closes. A failed server has nothing to drain.
+	if err == nil && s.config.ShutdownDelay > 0 {
+		time.Sleep(s.config.ShutdownDelay)
+	}
+
+	shutdownTimeout := s.config.ShutdownTimeout
+	if shutdownTimeout <= 0 {
+		shutdownTimeout = defaultShutdownTimeout
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+	defer cancel()
+
+	if shutdownErr := s.server.Shutdown(shutdownCtx); shutdownErr != nil {
+		err = errors.Join(err, fmt.Errorf("server forced to shutdown: %w", shutdownErr))
+	}
+
+	stopWorkers()
+
+	drained := make(chan struct{})
+
+	go func() {
+		workers.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+	case <-shutdownCtx.Done():
+		err = errors.Join(err, errors.New("background workers didn't stop in time"))
 	}
 
-	s.logger.Println("Server gracefully shut down")
+	s.release(shutdownCtx)
+
+	if err == nil {
+		s.logger.Info("Server gracefully shut down")
+	}
 
 	return err
 }
+
+func (s *HTTPServer) release(ctx context.Context) {
+	if err := s.tracingShutdown(ctx); err != nil {
+		s.logger.Warn("Failed to flush traces", slog.String("error", err.Error()))
+	}
+
+	if s.pool != nil {
+		s.pool.Close()
+	}
+}
+
+func newLogger(level string) (*slog.Logger, error) {
+	var logLevel slog.Level
+
+	if err := logLevel.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q: %w", level, err)
+	}
+
+	handler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: logLevel})
+
+	return slog.New(handler).With(slog.String("component", "http_server")), nil
+}
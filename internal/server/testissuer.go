@@ -0,0 +1,56 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"task-tracker/internal/auth"
+	"task-tracker/internal/models"
+)
+
+func (s *HTTPServer) handleTestIssuerJWKS(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.handleError(w, r, models.ErrMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(s.testIssuer.KeySet()); err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+}
+
+func (s *HTTPServer) handleTestIssuerToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		s.handleError(w, r, models.ErrMethodNotAllowed)
+		return
+	}
+
+	var request auth.TestTokenRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		s.handleError(w, r, models.ErrBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	token, ttl, err := s.testIssuer.Issue(request)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+
+	response := auth.TestTokenResponse{AccessToken: token, TokenType: "Bearer", ExpiresIn: int(ttl.Seconds())}
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+}
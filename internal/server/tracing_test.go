@@ -0,0 +1,84 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"task-tracker/internal/config"
+	"task-tracker/internal/tracing"
+)
+
+func TestTracing_CreateTaskSpanTree(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := tracing.NewSDKProvider(trace.WithSyncer(exporter))
+
+	server := NewHTTPServer(config.Config{
+		InMemory: true,
+		LogLevel: "error",
+	}, WithTracerProvider(provider))
+
+	if err := server.ConfigureServer(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	const (
+		traceID      = "4bf92f3577b34da6a3ce929d0e0e4736"
+		parentSpanID = "00f067aa0ba902b7"
+	)
+
+	resp, err := server.Handle(
+		http.MethodPost,
+		"/tasks",
+		strings.NewReader(`{"title":"title", "description":"description", "status":"todo"}`),
+		map[string]string{"traceparent": "00-" + traceID + "-" + parentSpanID + "-01"},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("returned %v; expected %v", resp.StatusCode, http.StatusCreated)
+	}
+
+	spans := exporter.GetSpans()
+	byName := make(map[string]tracetest.SpanStub, len(spans))
+
+	for _, span := range spans {
+		byName[span.Name] = span
+	}
+
+	httpSpan, found := byName["POST /tasks"]
+	if !found {
+		t.Fatalf("no HTTP span among %v", spanNames(spans))
+	}
+
+	if httpSpan.SpanContext.TraceID().String() != traceID || httpSpan.Parent.SpanID().String() != parentSpanID {
+		t.Fatalf("HTTP span doesn't continue the incoming trace: %v / %v", httpSpan.SpanContext.TraceID(), httpSpan.Parent.SpanID())
+	}
+
+	serviceSpan, found := byName["TaskService.Add"]
+	if !found {
+		t.Fatalf("no service span among %v", spanNames(spans))
+	}
+
+	if serviceSpan.Parent.SpanID() != httpSpan.SpanContext.SpanID() {
+		t.Fatalf("service span parent is %v; expected HTTP span %v", serviceSpan.Parent.SpanID(), httpSpan.SpanContext.SpanID())
+	}
+}
+
+func spanNames(spans tracetest.SpanStubs) []string {
+	names := make([]string, 0, len(spans))
+
+	for _, span := range spans {
+		names = append(names, span.Name)
+	}
+
+	return names
+}
@@ -0,0 +1,168 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"task-tracker/internal/models"
+	"task-tracker/internal/service"
+	"task-tracker/internal/transfer"
+)
+
+func (s *HTTPServer) handleExportTasks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.handleError(w, r, models.ErrMethodNotAllowed)
+		return
+	}
+
+	format, err := transfer.ParseFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	s.exportTasks(w, r, format)
+}
+
+func (s *HTTPServer) handleTodoTxt(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.exportTasks(w, r, transfer.TodoTxt)
+	case http.MethodPost:
+		s.importTasks(w, r, transfer.TodoTxt)
+	default:
+		s.handleError(w, r, models.ErrMethodNotAllowed)
+	}
+}
+
+func (s *HTTPServer) exportTasks(w http.ResponseWriter, r *http.Request, format transfer.Format) {
+	filter := taskFilter(r.URL.Query())
+	encoder := transfer.NewEncoder(w, format)
+	started := false
+
+	// Headers are only sent with the first task, so errors before it are reported as usual.
+	start := func() {
+		if !started {
+			started = true
+
+			w.Header().Set("Content-Type", format.ContentType())
+			w.Header().Set("Content-Disposition", `attachment; filename="tasks.`+format.Extension()+`"`)
+			w.WriteHeader(http.StatusOK)
+		}
+	}
+
+	err := s.taskService.Stream(r.Context(), func(task models.Task) error {
+		if !filter.Match(task) {
+			return nil
+		}
+
+		start()
+
+		return encoder.Encode(task)
+	})
+
+	if err == nil {
+		start()
+		err = encoder.Close()
+	}
+
+	if err == nil {
+		return
+	}
+
+	if !started {
+		s.handleError(w, r, err)
+		return
+	}
+
+	// The status is already sent, so the connection is dropped to keep clients from
+	// taking a truncated export for a complete one.
+	s.logger.LogAttrs(r.Context(), slog.LevelError, "Export failed",
+		slog.String("request_id", requestIDFromContext(r.Context())),
+		slog.String("error", err.Error()),
+	)
+
+	panic(http.ErrAbortHandler)
+}
+
+func (s *HTTPServer) handleImportTasks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		s.handleError(w, r, models.ErrMethodNotAllowed)
+		return
+	}
+
+	format := transfer.FormatForContentType(r.Header.Get("Content-Type"))
+
+	if name := r.URL.Query().Get("format"); name != "" {
+		var err error
+
+		if format, err = transfer.ParseFormat(name); err != nil {
+			s.handleError(w, r, err)
+			return
+		}
+	}
+
+	s.importTasks(w, r, format)
+}
+
+// importTasks writes nothing with dry_run=true.
+func (s *HTTPServer) importTasks(w http.ResponseWriter, r *http.Request, format transfer.Format) {
+	dryRun := false
+
+	if value := r.URL.Query().Get("dry_run"); value != "" {
+		var err error
+
+		if dryRun, err = strconv.ParseBool(value); err != nil {
+			s.handleError(w, r, models.ErrBadRequest)
+			return
+		}
+	}
+
+	rows := transfer.NewDecoder(r.Body, format)
+	if format == transfer.TodoTxt {
+		rows = &describedRows{ctx: r.Context(), rows: rows, tasks: s.taskService}
+	}
+
+	report, err := s.importer.Import(r.Context(), rows, dryRun)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	s.writeJSON(w, r, http.StatusOK, report)
+}
+
+// Tasks updated from rows without a description, such as todo.txt lines, keep theirs
+// and added tasks are described by their title.
+type describedRows struct {
+	ctx   context.Context
+	rows  transfer.Decoder
+	tasks service.TaskService
+}
+
+func (d *describedRows) Next() (models.ImportTaskRequest, error) {
+	row, err := d.rows.Next()
+	if err != nil || row.Description != "" {
+		return row, err
+	}
+
+	row.Description = row.Title
+
+	if row.ID == "" {
+		return row, nil
+	}
+
+	task, err := d.tasks.Get(d.ctx, row.ID)
+
+	switch {
+	case err == nil:
+		row.Description = task.Description
+	case !errors.Is(err, models.ErrTaskNotFound):
+		return models.ImportTaskRequest{}, err
+	}
+
+	return row, nil
+}
@@ -0,0 +1,225 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"task-tracker/internal/config"
+	"task-tracker/internal/models"
+)
+
+func TestHandler_ExportImport(t *testing.T) {
+	server := NewHTTPServer(config.Config{InMemory: true, LogLevel: "error", ShutdownTimeout: time.Second})
+
+	if err := server.ConfigureServer(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, body := range []string{
+		`{"title":"deploy", "description":"d", "status":"todo", "assignee":"alice", "labels":["ops"]}`,
+		`{"title":"review", "description":"d", "status":"done", "assignee":"bob"}`,
+	} {
+		if resp, _ := server.Handle(http.MethodPost, "/tasks", strings.NewReader(body), nil); resp.StatusCode != http.StatusCreated {
+			t.Fatalf("returned %v; expected the task to be created", resp.StatusCode)
+		}
+	}
+
+	exports := map[string]struct {
+		query          string
+		expectedStatus int
+		expectedType   string
+		expected       []string
+	}{
+		"json by default": {"", http.StatusOK, "application/json", []string{`"title":"deploy"`, `"title":"review"`}},
+		"csv":             {"?format=csv", http.StatusOK, "text/csv; charset=utf-8", []string{"id,title,", ",deploy,", ",review,"}},
+		"ndjson filtered": {"?format=ndjson&assignee=bob", http.StatusOK, "application/x-ndjson", []string{`"title":"review"`}},
+		"unknown format":  {"?format=xml", http.StatusBadRequest, "", nil},
+	}
+
+	for name, test := range exports {
+		t.Run(name, func(t *testing.T) {
+			resp, _ := server.Handle(http.MethodGet, "/tasks/export"+test.query, nil, nil)
+
+			if resp.StatusCode != test.expectedStatus {
+				t.Fatalf("test-case: (%q); returned %v; expected %v", name, resp.StatusCode, test.expectedStatus)
+			}
+
+			if resp.StatusCode != http.StatusOK {
+				return
+			}
+
+			if contentType := resp.Header.Get("Content-Type"); contentType != test.expectedType {
+				t.Fatalf("test-case: (%q); returned %q; expected %q", name, contentType, test.expectedType)
+			}
+
+			body, _ := io.ReadAll(resp.Body)
+
+			for _, expected := range test.expected {
+				if !strings.Contains(string(body), expected) {
+					t.Fatalf("test-case: (%q); returned %q; expected it to contain %q", name, body, expected)
+				}
+			}
+
+			if name == "ndjson filtered" && strings.Contains(string(body), "deploy") {
+				t.Fatalf("test-case: (%q); returned %q; expected only bob's tasks", name, body)
+			}
+		})
+	}
+
+	resp, _ := server.Handle(http.MethodGet, "/tasks/export?format=csv", nil, nil)
+	export, _ := io.ReadAll(resp.Body)
+	export = []byte(strings.Replace(string(export), ",deploy,", ",deployed,", 1) +
+		",new,d,todo,,,,,,,,,\n,,d,todo,,,,,,,,,\n")
+
+	imports := map[string]struct {
+		query          string
+		headers        map[string]string
+		body           string
+		expectedStatus int
+		expected       models.ImportReport
+	}{
+		"dry run": {
+			query:          "?dry_run=true",
+			headers:        map[string]string{"Content-Type": "text/csv"},
+			body:           string(export),
+			expectedStatus: http.StatusOK,
+			expected:       models.ImportReport{DryRun: true, Rows: 4, Created: 1, Updated: 2, Failed: 1},
+		},
+		"csv": {
+			query:          "?format=csv",
+			body:           string(export),
+			expectedStatus: http.StatusOK,
+			expected:       models.ImportReport{Rows: 4, Created: 1, Updated: 2, Failed: 1},
+		},
+		"ndjson": {
+			headers:        map[string]string{"Content-Type": "application/x-ndjson"},
+			body:           `{"id":"not-a-uuid","title":"t","description":"d","status":"todo"}` + "\n",
+			expectedStatus: http.StatusOK,
+			expected:       models.ImportReport{Rows: 1, Failed: 1},
+		},
+		"invalid dry run": {
+			query:          "?dry_run=maybe",
+			body:           "[]",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"unknown format": {
+			query:          "?format=xml",
+			body:           "[]",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"text is not todo.txt": {
+			headers:        map[string]string{"Content-Type": "text/plain"},
+			body:           "(B) Renew passport +admin\n",
+			expectedStatus: http.StatusOK,
+			expected:       models.ImportReport{},
+		},
+	}
+
+	for _, name := range []string{"dry run", "csv", "ndjson", "invalid dry run", "unknown format", "text is not todo.txt"} {
+		test := imports[name]
+
+		resp, _ := server.Handle(http.MethodPost, "/tasks/import"+test.query, strings.NewReader(test.body), test.headers)
+
+		if resp.StatusCode != test.expectedStatus {
+			t.Fatalf("test-case: (%q); returned %v; expected %v", name, resp.StatusCode, test.expectedStatus)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			continue
+		}
+
+		var report models.ImportReport
+		if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+			t.Fatalf("test-case: (%q); returned %v; expected a report", name, err)
+		}
+
+		if report.DryRun != test.expected.DryRun || report.Rows != test.expected.Rows || report.Created != test.expected.Created ||
+			report.Updated != test.expected.Updated || report.Failed != test.expected.Failed {
+			t.Fatalf("test-case: (%q); returned %+v; expected %+v", name, report, test.expected)
+		}
+	}
+
+	resp, _ = server.Handle(http.MethodGet, "/tasks", nil, nil)
+
+	var tasks []models.Task
+	if err := json.NewDecoder(resp.Body).Decode(&tasks); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	titles := make(map[string]bool)
+	for _, task := range tasks {
+		titles[task.Title] = true
+	}
+
+	if len(tasks) != 3 || !titles["deployed"] || !titles["review"] || !titles["new"] {
+		t.Fatalf("returned %+v; expected deployed, review and new", tasks)
+	}
+}
+
+func TestHandler_TodoTxt(t *testing.T) {
+	server := NewHTTPServer(config.Config{InMemory: true, LogLevel: "error", ShutdownTimeout: time.Second})
+
+	if err := server.ConfigureServer(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	body := `{"title":"Call the bank", "description":"About the card.", "status":"todo", "assignee":"alice", "labels":["pri:A","@phone"], "due_at":"2025-04-14T00:00:00Z"}`
+	if resp, _ := server.Handle(http.MethodPost, "/tasks", strings.NewReader(body), nil); resp.StatusCode != http.StatusCreated {
+		t.Fatalf("returned %v; expected the task to be created", resp.StatusCode)
+	}
+
+	resp, _ := server.Handle(http.MethodGet, "/tasks.txt", nil, nil)
+	export, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/plain; charset=utf-8" ||
+		!strings.HasPrefix(string(export), "(A) ") || !strings.Contains(string(export), " Call the bank @phone due:2025-04-14 assignee:alice id:") {
+		t.Fatalf("returned %v, %q; expected a todo.txt line", resp.StatusCode, export)
+	}
+
+	// Completing the task drops its priority.
+	lines := strings.Replace(string(export), "(A) ", "x 2025-04-15 ", 1) + "(B) Renew passport +admin\n\nno title here +x due:soon\n"
+
+	resp, _ = server.Handle(http.MethodPost, "/tasks.txt", strings.NewReader(lines), nil)
+
+	var report models.ImportReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("returned %v, %v; expected a report", resp.StatusCode, err)
+	}
+
+	if report.Rows != 3 || report.Created != 1 || report.Updated != 1 || report.Failed != 1 {
+		t.Fatalf("returned %+v; expected one task created, one updated and one failed", report)
+	}
+
+	resp, _ = server.Handle(http.MethodGet, "/tasks", nil, nil)
+
+	var tasks []models.Task
+	if err := json.NewDecoder(resp.Body).Decode(&tasks); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, task := range tasks {
+		switch task.Title {
+		case "Call the bank":
+			if task.Status != models.StatusDone || task.Description != "About the card." || task.Assignee != "alice" ||
+				len(task.Labels) != 1 || task.Labels[0] != "@phone" {
+				t.Fatalf("returned %+v; expected the task completed with its description kept", task)
+			}
+		case "Renew passport":
+			if task.Status != models.StatusTodo || task.Description != "Renew passport" || len(task.Labels) != 2 {
+				t.Fatalf("returned %+v; expected the task added with its title as description", task)
+			}
+		default:
+			t.Fatalf("returned %+v; expected only the imported tasks", task)
+		}
+	}
+
+	resp, _ = server.Handle(http.MethodDelete, "/tasks.txt", nil, nil)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("returned %v; expected %v", resp.StatusCode, http.StatusBadRequest)
+	}
+}
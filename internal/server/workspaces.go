@@ -0,0 +1,126 @@
+package server
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"task-tracker/internal/models"
+)
+
+func (s *HTTPServer) handleWorkspaces(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.handleListWorkspaces(w, r)
+	case http.MethodPost:
+		s.handleCreateWorkspace(w, r)
+	default:
+		s.handleError(w, r, models.ErrMethodNotAllowed)
+	}
+}
+
+func (s *HTTPServer) handleListWorkspaces(w http.ResponseWriter, r *http.Request) {
+	workspaces, err := s.workspaces.List(r.Context())
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	s.writeJSON(w, r, http.StatusOK, workspaces)
+}
+
+func (s *HTTPServer) handleCreateWorkspace(w http.ResponseWriter, r *http.Request) {
+	var request models.CreateWorkspaceRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		s.handleError(w, r, models.ErrBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	workspace, err := s.workspaces.Create(r.Context(), request)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	s.writeJSON(w, r, http.StatusCreated, workspace)
+}
+
+func (s *HTTPServer) handleWorkspaceByID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.handleError(w, r, models.ErrMethodNotAllowed)
+		return
+	}
+
+	workspace, err := s.workspaces.Get(r.Context())
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	s.writeJSON(w, r, http.StatusOK, workspace)
+}
+
+func (s *HTTPServer) handleWorkspaceMembers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.handleError(w, r, models.ErrMethodNotAllowed)
+		return
+	}
+
+	members, err := s.workspaces.Members(r.Context())
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	s.writeJSON(w, r, http.StatusOK, members)
+}
+
+func (s *HTTPServer) handleWorkspaceMember(w http.ResponseWriter, r *http.Request) {
+	subject := r.PathValue("subject")
+
+	switch r.Method {
+	case http.MethodPut:
+		var request models.SetMemberRequest
+
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			s.handleError(w, r, models.ErrBadRequest)
+			return
+		}
+		defer r.Body.Close()
+
+		member, err := s.workspaces.SetMember(r.Context(), subject, request)
+		if err != nil {
+			s.handleError(w, r, err)
+			return
+		}
+
+		s.writeJSON(w, r, http.StatusOK, member)
+	case http.MethodDelete:
+		if err := s.workspaces.RemoveMember(r.Context(), subject); err != nil {
+			s.handleError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		s.handleError(w, r, models.ErrMethodNotAllowed)
+	}
+}
+
+// writeJSON can't report an encoding error with a status, as one is already sent, so
+// it drops the connection instead of leaving the client with a truncated body.
+func (s *HTTPServer) writeJSON(w http.ResponseWriter, r *http.Request, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		s.logger.LogAttrs(r.Context(), slog.LevelError, "Response encoding failed",
+			slog.String("request_id", requestIDFromContext(r.Context())),
+			slog.String("error", err.Error()),
+		)
+
+		panic(http.ErrAbortHandler)
+	}
+}
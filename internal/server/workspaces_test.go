@@ -0,0 +1,109 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"task-tracker/internal/config"
+	"task-tracker/internal/models"
+)
+
+func TestServer_Workspaces(t *testing.T) {
+	server := NewHTTPServer(config.Config{
+		InMemory:        true,
+		AuthEnabled:     true,
+		TestIssuer:      true,
+		LogLevel:        "error",
+		ShutdownTimeout: time.Second,
+	})
+
+	if err := server.ConfigureServer(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	signIn := func(subject string, role models.Role) map[string]string {
+		resp, _ := server.Handle(http.MethodPost, "/auth/test/token",
+			strings.NewReader(`{"sub":"`+subject+`","roles":["`+string(role)+`"]}`), nil)
+
+		var token struct {
+			AccessToken string `json:"access_token"`
+		}
+
+		if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		return map[string]string{"Authorization": "Bearer " + token.AccessToken}
+	}
+
+	admin, alice, bob := signIn("root", models.RoleAdmin), signIn("alice", models.RoleViewer), signIn("bob", models.RoleMember)
+
+	steps := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		headers        map[string]string
+		expectedStatus int
+	}{
+		{"members can't create workspaces", http.MethodPost, "/workspaces", `{"id":"team-a","name":"Team A"}`, bob, http.StatusForbidden},
+		{"admin creates a workspace", http.MethodPost, "/workspaces", `{"id":"team-a","name":"Team A"}`, admin, http.StatusCreated},
+		{"admin adds alice", http.MethodPut, "/workspaces/team-a/members/alice", `{"role":"member"}`, admin, http.StatusOK},
+		{"alice creates a task", http.MethodPost, "/workspaces/team-a/tasks",
+			`{"title":"title", "description":"description", "status":"todo"}`, alice, http.StatusCreated},
+		{"bob isn't a member", http.MethodGet, "/workspaces/team-a/tasks", "", bob, http.StatusForbidden},
+		{"unknown workspace", http.MethodGet, "/workspaces/team-z/tasks", "", admin, http.StatusNotFound},
+		{"alice can't manage members", http.MethodPut, "/workspaces/team-a/members/bob", `{"role":"member"}`, alice, http.StatusForbidden},
+	}
+
+	for _, step := range steps {
+		resp, _ := server.Handle(step.method, step.path, strings.NewReader(step.body), step.headers)
+
+		if resp.StatusCode != step.expectedStatus {
+			t.Fatalf("%s: returned %v; expected %v", step.name, resp.StatusCode, step.expectedStatus)
+		}
+	}
+
+	var teamTasks, defaultTasks []models.Task
+
+	resp, _ := server.Handle(http.MethodGet, "/workspaces/team-a/tasks", http.NoBody, alice)
+	if err := json.NewDecoder(resp.Body).Decode(&teamTasks); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resp, _ = server.Handle(http.MethodGet, "/tasks", http.NoBody, admin)
+	if err := json.NewDecoder(resp.Body).Decode(&defaultTasks); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(teamTasks) != 1 || teamTasks[0].WorkspaceID != "team-a" || len(defaultTasks) != 0 {
+		t.Fatalf("team tasks %v, default tasks %v; expected the task only in team-a", teamTasks, defaultTasks)
+	}
+
+	if resp, _ := server.Handle(http.MethodGet, "/tasks/"+teamTasks[0].ID, http.NoBody, admin); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("returned %v; expected the task to be hidden outside its workspace", resp.StatusCode)
+	}
+}
+
+func TestServer_WriteJSONAbort(t *testing.T) {
+	server := NewHTTPServer(config.Config{InMemory: true, LogLevel: "error", ShutdownTimeout: time.Second})
+
+	if err := server.ConfigureServer(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() {
+		if err, _ := recover().(error); !errors.Is(err, http.ErrAbortHandler) {
+			t.Fatalf("returned %v; expected the handler to be aborted", err)
+		}
+	}()
+
+	server.writeJSON(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/workspaces", nil), http.StatusOK, math.Inf(1))
+}
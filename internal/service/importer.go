@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/google/uuid"
+
+	"task-tracker/internal/models"
+	"task-tracker/internal/transfer"
+)
+
+// Importer goes through the task service, so imports are subject to the same policy as
+// single requests.
+type Importer struct {
+	tasks TaskService
+}
+
+func NewImporter(tasks TaskService) *Importer {
+	return &Importer{tasks: tasks}
+}
+
+// Rows with the ID of a task of the workspace update it, others add a task. Invalid rows
+// and rows the caller may not write are reported and skipped, only failures of the
+// service itself are returned as errors.
+func (i *Importer) Import(ctx context.Context, rows transfer.Decoder, dryRun bool) (models.ImportReport, error) {
+	return i.ImportWithProgress(ctx, rows, dryRun, nil)
+}
+
+// Progress is called once a row is done with, with a nil error if it was imported.
+type Progress func(row models.ImportTaskRequest, err error)
+
+func (i *Importer) ImportWithProgress(ctx context.Context, rows transfer.Decoder, dryRun bool, progress Progress) (models.ImportReport, error) {
+	report := models.ImportReport{DryRun: dryRun}
+
+	// added tracks the IDs a dry run would have added, which later rows update.
+	added := make(map[string]bool)
+
+	for {
+		row, err := rows.Next()
+		if errors.Is(err, io.EOF) {
+			return report, nil
+		}
+
+		if err != nil && !errors.Is(err, transfer.ErrInvalidRow) {
+			report.Errors = append(report.Errors, models.ImportError{Error: err.Error()})
+			return report, nil
+		}
+
+		report.Rows++
+
+		if err == nil {
+			err = row.Validate()
+		}
+
+		if err == nil {
+			err = i.importRow(ctx, &row, dryRun, added, &report)
+		}
+
+		var modelError models.Error
+		if err != nil && !errors.As(err, &modelError) && !errors.Is(err, transfer.ErrInvalidRow) {
+			return report, err
+		}
+
+		if err != nil {
+			report.Failed++
+			report.Errors = append(report.Errors, models.ImportError{Row: report.Rows, ID: row.ID, Error: err.Error()})
+		}
+
+		if progress != nil {
+			progress(row, err)
+		}
+	}
+}
+
+func (i *Importer) importRow(ctx context.Context, row *models.ImportTaskRequest, dryRun bool, added map[string]bool, report *models.ImportReport) error {
+	if row.ID != "" {
+		row.ID = uuid.MustParse(row.ID).String()
+	}
+
+	exists := added[row.ID]
+
+	if row.ID != "" && !exists {
+		_, err := i.tasks.Get(ctx, row.ID)
+		if err != nil && !errors.Is(err, models.ErrTaskNotFound) {
+			return err
+		}
+
+		exists = err == nil
+	}
+
+	if !exists {
+		task := row.ConvertToTask()
+		task.ID = row.ID
+
+		if dryRun {
+			if err := i.tasks.CheckAdd(ctx, task); err != nil {
+				return err
+			}
+
+			if task.ID != "" {
+				added[task.ID] = true
+			}
+		} else if err := i.tasks.Add(ctx, task); err != nil {
+			return err
+		}
+
+		report.Created++
+
+		return nil
+	}
+
+	if !dryRun {
+		updated := &models.Task{
+			ID:          row.ID,
+			Title:       row.Title,
+			Description: row.Description,
+			Status:      row.Status,
+			Assignee:    row.Assignee,
+			DueAt:       row.DueAt,
+			Reminders:   row.Reminders,
+			Labels:      row.Labels,
+		}
+
+		if err := i.tasks.Update(ctx, updated); err != nil {
+			return err
+		}
+	}
+
+	report.Updated++
+
+	return nil
+}
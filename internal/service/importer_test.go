@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"task-tracker/internal/clock"
+	"task-tracker/internal/models"
+	"task-tracker/internal/repository"
+	"task-tracker/internal/tenant"
+	"task-tracker/internal/transfer"
+)
+
+// rowsDecoder replays rows and decoding errors.
+type rowsDecoder struct {
+	rows []models.ImportTaskRequest
+	errs []error
+}
+
+func (d *rowsDecoder) Next() (models.ImportTaskRequest, error) {
+	if len(d.rows) == 0 {
+		return models.ImportTaskRequest{}, io.EOF
+	}
+
+	row, err := d.rows[0], d.errs[0]
+	d.rows, d.errs = d.rows[1:], d.errs[1:]
+
+	return row, err
+}
+
+func TestImport(t *testing.T) {
+	const existingID = "0f8e3a6c-8d2b-4b55-9c7e-2b1f0a6d4e11"
+	const newID = "6b1d2c3e-4f5a-4b6c-8d7e-9f0a1b2c3d4e"
+	// otherID is the ID of a task of another workspace.
+	const otherID = "9a3e5b7c-1d2f-4a6b-8c0d-2e4f6a8b0c1d"
+
+	row := func(id, title string) models.ImportTaskRequest {
+		return models.ImportTaskRequest{
+			ID:                id,
+			CreateTaskRequest: models.CreateTaskRequest{Title: title, Description: "d", Status: "todo"},
+		}
+	}
+
+	tests := map[string]struct {
+		rows     []models.ImportTaskRequest
+		errs     []error
+		dryRun   bool
+		expected models.ImportReport
+		titles   map[string]string
+		count    int
+	}{
+		"upserts by id": {
+			rows:     []models.ImportTaskRequest{row(existingID, "renamed"), row(newID, "restored"), row("", "new")},
+			errs:     []error{nil, nil, nil},
+			expected: models.ImportReport{Rows: 3, Created: 2, Updated: 1},
+			titles:   map[string]string{existingID: "renamed", newID: "restored"},
+			count:    3,
+		},
+		"dry run writes nothing": {
+			rows:     []models.ImportTaskRequest{row(existingID, "renamed"), row(newID, "restored"), row(newID, "again")},
+			errs:     []error{nil, nil, nil},
+			dryRun:   true,
+			expected: models.ImportReport{DryRun: true, Rows: 3, Created: 1, Updated: 2},
+			titles:   map[string]string{existingID: "existing"},
+			count:    1,
+		},
+		"ids of other workspaces fail their row": {
+			rows: []models.ImportTaskRequest{row(otherID, "taken"), row(newID, "restored")},
+			errs: []error{nil, nil},
+			expected: models.ImportReport{
+				Rows: 2, Created: 1, Failed: 1,
+				Errors: []models.ImportError{{Row: 1, ID: otherID, Error: models.ErrTaskExists.Error()}},
+			},
+			titles: map[string]string{newID: "restored"},
+			count:  2,
+		},
+		"dry run checks ids of other workspaces": {
+			rows:   []models.ImportTaskRequest{row(otherID, "taken")},
+			errs:   []error{nil},
+			dryRun: true,
+			expected: models.ImportReport{
+				DryRun: true, Rows: 1, Failed: 1,
+				Errors: []models.ImportError{{Row: 1, ID: otherID, Error: models.ErrTaskExists.Error()}},
+			},
+			count: 1,
+		},
+		"reports invalid rows": {
+			rows: []models.ImportTaskRequest{row("", ""), row("42", "bad id"), {}, row(newID, "restored")},
+			errs: []error{nil, nil, transfer.ErrInvalidRow, nil},
+			expected: models.ImportReport{
+				Rows: 4, Created: 1, Failed: 3,
+				Errors: []models.ImportError{
+					{Row: 1, Error: models.ErrTitleIsEmpty.Error()},
+					{Row: 2, ID: "42", Error: models.ErrInvalidTaskID.Error()},
+					{Row: 3, Error: transfer.ErrInvalidRow.Error()},
+				},
+			},
+			titles: map[string]string{newID: "restored"},
+			count:  2,
+		},
+		"stops at unreadable input": {
+			rows: []models.ImportTaskRequest{row(newID, "restored"), {}, row("", "never")},
+			errs: []error{nil, errors.New("unexpected end of input"), nil},
+			expected: models.ImportReport{
+				Rows: 1, Created: 1,
+				Errors: []models.ImportError{{Error: "unexpected end of input"}},
+			},
+			titles: map[string]string{newID: "restored"},
+			count:  2,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			clk := clock.NewFixed(time.Date(2025, time.April, 9, 18, 21, 41, 0, time.UTC))
+			tasks := NewDefaultTaskService(repository.NewMemoryTaskRepository(clk), clk, AllowAll{})
+
+			if err := tasks.Add(ctx, &models.Task{ID: existingID, Title: "existing", Description: "d", Status: "todo"}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			other := tenant.WithWorkspace(ctx, "other")
+			if err := tasks.Add(other, &models.Task{ID: otherID, Title: "other", Description: "d", Status: "todo"}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			report, err := NewImporter(tasks).Import(ctx, &rowsDecoder{rows: test.rows, errs: test.errs}, test.dryRun)
+			if err != nil {
+				t.Fatalf("test-case: (%q); returned %v; expected no error", name, err)
+			}
+
+			if report.DryRun != test.expected.DryRun || report.Rows != test.expected.Rows ||
+				report.Created != test.expected.Created || report.Updated != test.expected.Updated ||
+				report.Failed != test.expected.Failed || len(report.Errors) != len(test.expected.Errors) {
+				t.Fatalf("test-case: (%q); returned %+v; expected %+v", name, report, test.expected)
+			}
+
+			for i, expected := range test.expected.Errors {
+				if report.Errors[i] != expected {
+					t.Fatalf("test-case: (%q); returned %+v; expected %+v", name, report.Errors[i], expected)
+				}
+			}
+
+			if all, _ := tasks.GetAll(ctx); len(all) != test.count {
+				t.Fatalf("test-case: (%q); returned %d tasks; expected %d", name, len(all), test.count)
+			}
+
+			for id, title := range test.titles {
+				task, err := tasks.Get(ctx, id)
+				if err != nil || task.Title != title {
+					t.Fatalf("test-case: (%q); returned %q, %v; expected %q", name, task.Title, err, title)
+				}
+			}
+		})
+	}
+}
+
+func TestImportWithProgress(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewFixed(time.Date(2025, time.April, 9, 18, 21, 41, 0, time.UTC))
+	tasks := NewDefaultTaskService(repository.NewMemoryTaskRepository(clk), clk, AllowAll{})
+
+	rows := &rowsDecoder{
+		rows: []models.ImportTaskRequest{
+			{CreateTaskRequest: models.CreateTaskRequest{Title: "a", Description: "d", Status: "todo"}},
+			{CreateTaskRequest: models.CreateTaskRequest{Description: "d", Status: "todo"}},
+		},
+		errs: []error{nil, nil},
+	}
+
+	var titles []string
+	var errs []error
+
+	_, err := NewImporter(tasks).ImportWithProgress(ctx, rows, false, func(row models.ImportTaskRequest, err error) {
+		titles = append(titles, row.Title)
+		errs = append(errs, err)
+	})
+
+	if err != nil || len(titles) != 2 || titles[0] != "a" || errs[0] != nil || !errors.Is(errs[1], models.ErrTitleIsEmpty) {
+		t.Fatalf("returned %v, %q, %v; expected progress for both rows", err, titles, errs)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"time"
 
 	"task-tracker/internal/models"
 )
@@ -31,6 +32,10 @@ func (m *TaskServiceMock) Add(_ context.Context, task *models.Task) error {
 	return nil
 }
 
+func (m *TaskServiceMock) CheckAdd(ctx context.Context, task *models.Task) error {
+	return m.Add(ctx, task)
+}
+
 func (m *TaskServiceMock) Delete(_ context.Context, id string) error {
 	if id == NotFound {
 		return models.ErrTaskNotFound
@@ -63,6 +68,22 @@ func (m *TaskServiceMock) GetAll(_ context.Context) ([]models.Task, error) {
 	return []models.Task{{ID: "task1", Title: "Mock Task"}}, nil
 }
 
+func (m *TaskServiceMock) FindByCalendar(_ context.Context, _, _ string) ([]models.Task, error) {
+	if m.ForceInternalError {
+		return nil, ErrInternalMock
+	}
+
+	return nil, nil
+}
+
+func (m *TaskServiceMock) Stream(_ context.Context, fn func(models.Task) error) error {
+	if m.ForceInternalError {
+		return ErrInternalMock
+	}
+
+	return fn(models.Task{ID: "task1", Title: "Mock Task"})
+}
+
 func (m *TaskServiceMock) Update(_ context.Context, updatedTask *models.Task) error {
 	if updatedTask.ID == NotFound {
 		return models.ErrTaskNotFound
@@ -74,3 +95,27 @@ func (m *TaskServiceMock) Update(_ context.Context, updatedTask *models.Task) er
 
 	return nil
 }
+
+func (m *TaskServiceMock) Skip(_ context.Context, id string) (models.Task, error) {
+	if id == NotFound {
+		return models.Task{}, models.ErrTaskNotFound
+	}
+
+	if m.ForceInternalError {
+		return models.Task{}, ErrInternalMock
+	}
+
+	return models.Task{ID: id, Title: "Mock Task", Status: models.StatusSkipped, SeriesID: id}, nil
+}
+
+func (m *TaskServiceMock) Reschedule(_ context.Context, id string, dueAt time.Time) (models.Task, error) {
+	if id == NotFound {
+		return models.Task{}, models.ErrTaskNotFound
+	}
+
+	if m.ForceInternalError {
+		return models.Task{}, ErrInternalMock
+	}
+
+	return models.Task{ID: id, Title: "Mock Task", DueAt: &dueAt}, nil
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+
+	"task-tracker/internal/auth"
+	"task-tracker/internal/models"
+)
+
+type Action string
+
+const (
+	ActionRead   Action = "read"
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+	// ActionManage covers creating workspaces and managing their members.
+	ActionManage Action = "manage"
+)
+
+// Task is the existing task for updates and deletes and nil otherwise. The caller's
+// role is the one they have in the workspace entered with WorkspaceService.Enter.
+type Policy interface {
+	Authorize(ctx context.Context, action Action, task *models.Task) error
+}
+
+type AllowAll struct{}
+
+func (AllowAll) Authorize(context.Context, Action, *models.Task) error {
+	return nil
+}
+
+type RolePolicy struct{}
+
+func (RolePolicy) Authorize(ctx context.Context, action Action, task *models.Task) error {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return models.ErrUnauthorized
+	}
+
+	role := principal.Role()
+
+	var allowed bool
+
+	switch action {
+	case ActionRead:
+		allowed = role.AtLeast(models.RoleViewer)
+	case ActionCreate:
+		allowed = role.AtLeast(models.RoleMember)
+	case ActionUpdate:
+		allowed = role.AtLeast(models.RoleMaintainer) ||
+			(role.AtLeast(models.RoleMember) && task != nil && involves(task, principal.Subject))
+	case ActionDelete:
+		allowed = role.AtLeast(models.RoleMaintainer)
+	case ActionManage:
+		allowed = role.AtLeast(models.RoleAdmin)
+	}
+
+	if !allowed {
+		return models.ErrForbidden
+	}
+
+	return nil
+}
+
+func involves(task *models.Task, subject string) bool {
+	return subject != "" && (task.CreatedBy == subject || task.Assignee == subject)
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"task-tracker/internal/auth"
+	"task-tracker/internal/models"
+)
+
+func TestRolePolicy_Authorize(t *testing.T) {
+	own := &models.Task{ID: "task1", CreatedBy: "alice"}
+	assigned := &models.Task{ID: "task2", CreatedBy: "bob", Assignee: "alice"}
+	others := &models.Task{ID: "task3", CreatedBy: "bob"}
+
+	tests := map[string]struct {
+		role   models.Role
+		action Action
+		task   *models.Task
+		result error
+	}{
+		"viewer reads":                  {models.RoleViewer, ActionRead, nil, nil},
+		"viewer can't create":           {models.RoleViewer, ActionCreate, nil, models.ErrForbidden},
+		"viewer can't update own":       {models.RoleViewer, ActionUpdate, own, models.ErrForbidden},
+		"member creates":                {models.RoleMember, ActionCreate, nil, nil},
+		"member updates own":            {models.RoleMember, ActionUpdate, own, nil},
+		"member updates assigned":       {models.RoleMember, ActionUpdate, assigned, nil},
+		"member can't update others":    {models.RoleMember, ActionUpdate, others, models.ErrForbidden},
+		"member can't delete own":       {models.RoleMember, ActionDelete, own, models.ErrForbidden},
+		"maintainer updates others":     {models.RoleMaintainer, ActionUpdate, others, nil},
+		"maintainer deletes":            {models.RoleMaintainer, ActionDelete, others, nil},
+		"admin deletes":                 {models.RoleAdmin, ActionDelete, others, nil},
+		"unknown role is only a viewer": {"owner", ActionCreate, nil, models.ErrForbidden},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "alice", Roles: []string{string(test.role)}})
+
+			if err := (RolePolicy{}).Authorize(ctx, test.action, test.task); !errors.Is(err, test.result) {
+				t.Fatalf("test-case: (%q); returned %v; expected %v", name, err, test.result)
+			}
+		})
+	}
+
+	if err := (RolePolicy{}).Authorize(context.Background(), ActionRead, nil); !errors.Is(err, models.ErrUnauthorized) {
+		t.Fatalf("anonymous caller: returned %v; expected %v", err, models.ErrUnauthorized)
+	}
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"task-tracker/internal/clock"
+	"task-tracker/internal/models"
+	"task-tracker/internal/notify"
+	"task-tracker/internal/repository"
+	"task-tracker/internal/tenant"
+)
+
+const dueFormat = "Mon, 02 Jan 2006 15:04 MST"
+
+// Reminders and digests are claimed in the repository before they're sent, so they're
+// sent at most once across instances, and failed deliveries aren't retried.
+type ReminderScheduler struct {
+	tasks         repository.TaskRepository
+	notifications repository.NotificationRepository
+	notifier      notify.Notifier
+	clock         clock.Clock
+	logger        *slog.Logger
+	digestHour    int
+	digestDay     string
+}
+
+// Digests are sent from digestHour o'clock UTC on, a negative hour disables them.
+func NewReminderScheduler(tasks repository.TaskRepository, notifications repository.NotificationRepository,
+	notifier notify.Notifier, clock clock.Clock, logger *slog.Logger, digestHour int) *ReminderScheduler {
+	return &ReminderScheduler{
+		tasks:         tasks,
+		notifications: notifications,
+		notifier:      notifier,
+		clock:         clock,
+		logger:        logger,
+		digestHour:    digestHour,
+	}
+}
+
+func (s *ReminderScheduler) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		s.logger.Warn("Reminders aren't sent, the scheduler interval isn't positive",
+			slog.Duration("interval", interval))
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.SendReminders(ctx); err != nil && ctx.Err() == nil {
+			s.logger.Warn("Failed to send reminders", slog.String("error", err.Error()))
+		}
+
+		if _, err := s.SendDigest(ctx); err != nil && ctx.Err() == nil {
+			s.logger.Warn("Failed to send overdue digest", slog.String("error", err.Error()))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *ReminderScheduler) SendReminders(ctx context.Context) (int, error) {
+	now := s.clock.Now()
+
+	tasks, err := s.tasks.GetDueReminders(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+
+	var errs []error
+
+	for _, task := range tasks {
+		err := s.tasks.AdvanceReminder(tenant.WithWorkspace(ctx, task.WorkspaceID), task.ID, *task.NextReminderAt, now)
+		if errors.Is(err, models.ErrReminderSent) {
+			continue
+		}
+
+		if err == nil {
+			err = s.notifier.Notify(ctx, s.reminder(task))
+		}
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("task %s: %w", task.ID, err))
+			continue
+		}
+
+		sent++
+	}
+
+	return sent, errors.Join(errs...)
+}
+
+func (s *ReminderScheduler) SendDigest(ctx context.Context) (int, error) {
+	now := s.clock.Now()
+	day := now.Format(time.DateOnly)
+
+	if s.digestHour < 0 || now.Hour() < s.digestHour || s.digestDay == day {
+		return 0, nil
+	}
+
+	claimed, err := s.notifications.ClaimDigest(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	s.digestDay = day
+
+	if !claimed {
+		return 0, nil
+	}
+
+	overdue, err := s.tasks.GetOverdue(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	byAssignee := make(map[string][]models.Task)
+
+	for _, task := range overdue {
+		if task.Assignee != "" {
+			byAssignee[task.Assignee] = append(byAssignee[task.Assignee], task)
+		}
+	}
+
+	assignees := make([]string, 0, len(byAssignee))
+	for assignee := range byAssignee {
+		assignees = append(assignees, assignee)
+	}
+
+	sort.Strings(assignees)
+
+	var errs []error
+
+	for _, assignee := range assignees {
+		if err := s.notifier.Notify(ctx, s.digest(assignee, byAssignee[assignee])); err != nil {
+			errs = append(errs, fmt.Errorf("assignee %s: %w", assignee, err))
+		}
+	}
+
+	return len(assignees) - len(errs), errors.Join(errs...)
+}
+
+func (s *ReminderScheduler) reminder(task models.Task) notify.Notification {
+	recipient := task.Assignee
+	if recipient == "" {
+		recipient = task.CreatedBy
+	}
+
+	var body strings.Builder
+
+	fmt.Fprintf(&body, "%s\n\n", task.Title)
+
+	if task.Description != "" {
+		fmt.Fprintf(&body, "%s\n\n", task.Description)
+	}
+
+	fmt.Fprintf(&body, "Due: %s\nStatus: %s\nWorkspace: %s\nTask: %s\n", formatDue(task), task.Status, task.WorkspaceID, task.ID)
+
+	return notify.Notification{
+		Kind:      notify.KindReminder,
+		Recipient: recipient,
+		Subject:   fmt.Sprintf("Reminder: %s is due %s", task.Title, formatDue(task)),
+		Body:      body.String(),
+		Tasks:     []models.Task{task},
+		CreatedAt: s.clock.Now(),
+	}
+}
+
+func (s *ReminderScheduler) digest(assignee string, tasks []models.Task) notify.Notification {
+	var body strings.Builder
+
+	fmt.Fprintf(&body, "You have %d overdue %s:\n\n", len(tasks), plural(len(tasks), "task", "tasks"))
+
+	for _, task := range tasks {
+		fmt.Fprintf(&body, "- %s, due %s (%s, workspace %s)\n", task.Title, formatDue(task), task.Status, task.WorkspaceID)
+	}
+
+	return notify.Notification{
+		Kind:      notify.KindDigest,
+		Recipient: assignee,
+		Subject:   fmt.Sprintf("%d overdue %s", len(tasks), plural(len(tasks), "task", "tasks")),
+		Body:      body.String(),
+		Tasks:     tasks,
+		CreatedAt: s.clock.Now(),
+	}
+}
+
+// formatDue renders the due date in the task's time zone, UTC if it has none.
+func formatDue(task models.Task) string {
+	if task.DueAt == nil {
+		return "-"
+	}
+
+	location, err := time.LoadLocation(task.TimeZone)
+	if err != nil {
+		location = time.UTC
+	}
+
+	return task.DueAt.In(location).Format(dueFormat)
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+
+	return many
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"task-tracker/internal/clock"
+	"task-tracker/internal/models"
+	"task-tracker/internal/notify"
+	"task-tracker/internal/repository"
+	"task-tracker/internal/tenant"
+)
+
+type recordingNotifier struct {
+	sent []notify.Notification
+	err  error
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, notification notify.Notification) error {
+	n.sent = append(n.sent, notification)
+	return n.err
+}
+
+func TestReminderScheduler_SendReminders(t *testing.T) {
+	created := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	due := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		reminders     []models.Duration
+		assignee      string
+		status        string
+		now           time.Time
+		notifyErr     error
+		expectedSent  int
+		expectedTo    string
+		expectedNext  *time.Time
+		expectedError bool
+	}{
+		"not due yet": {
+			reminders:    []models.Duration{models.Duration(time.Hour)},
+			now:          due.Add(-2 * time.Hour),
+			expectedNext: ptr(due.Add(-time.Hour)),
+		},
+
+		"due, sent to the creator": {
+			reminders:    []models.Duration{models.Duration(time.Hour)},
+			now:          due.Add(-time.Hour),
+			expectedSent: 1,
+			expectedTo:   "alice",
+		},
+
+		"due, sent to the assignee": {
+			reminders:    []models.Duration{models.Duration(24 * time.Hour), models.Duration(time.Hour)},
+			assignee:     "bob",
+			now:          due.Add(-12 * time.Hour),
+			expectedSent: 1,
+			expectedTo:   "bob",
+			expectedNext: ptr(due.Add(-time.Hour)),
+		},
+
+		"missed reminders are sent once": {
+			reminders:    []models.Duration{models.Duration(24 * time.Hour), models.Duration(time.Hour), 0},
+			now:          due.Add(time.Hour),
+			expectedSent: 1,
+			expectedTo:   "alice",
+		},
+
+		"done": {
+			reminders: []models.Duration{models.Duration(time.Hour)},
+			status:    models.StatusDone,
+			now:       due,
+		},
+
+		"delivery failed": {
+			reminders:     []models.Duration{models.Duration(time.Hour)},
+			now:           due,
+			notifyErr:     errors.New("connection refused"),
+			expectedTo:    "alice",
+			expectedError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := tenant.WithWorkspace(context.Background(), "team-a")
+			repo := repository.NewMemoryTaskRepository(clock.NewFixed(created))
+
+			task := &models.Task{ID: "1", Title: "File taxes", Status: test.status, Assignee: test.assignee, CreatedBy: "alice",
+				DueAt: &due, Reminders: test.reminders}
+			if err := repo.Add(ctx, task); err != nil {
+				t.Fatalf("test-case: (%q); unexpected error: %v", name, err)
+			}
+
+			notifier := &recordingNotifier{err: test.notifyErr}
+			scheduler := NewReminderScheduler(repo, repository.NewMemoryNotificationRepository(), notifier,
+				clock.NewFixed(test.now), slog.New(slog.NewTextHandler(io.Discard, nil)), -1)
+
+			for range 2 {
+				sent, err := scheduler.SendReminders(context.Background())
+				if (err != nil) != test.expectedError {
+					t.Fatalf("test-case: (%q); returned %v; expected error %v", name, err, test.expectedError)
+				}
+
+				if sent != test.expectedSent {
+					t.Fatalf("test-case: (%q); sent %v; expected %v", name, sent, test.expectedSent)
+				}
+
+				// A reminder is claimed before it's sent, so the second pass has nothing to do.
+				test.expectedSent, test.expectedError = 0, false
+			}
+
+			if test.expectedTo != "" {
+				if len(notifier.sent) != 1 || notifier.sent[0].Recipient != test.expectedTo || notifier.sent[0].Kind != notify.KindReminder {
+					t.Fatalf("test-case: (%q); sent %+v; expected one reminder to %v", name, notifier.sent, test.expectedTo)
+				}
+			} else if len(notifier.sent) != 0 {
+				t.Fatalf("test-case: (%q); sent %+v; expected nothing", name, notifier.sent)
+			}
+
+			stored, err := repo.Get(ctx, task.ID)
+			if err != nil {
+				t.Fatalf("test-case: (%q); unexpected error: %v", name, err)
+			}
+
+			if !equalTimes(stored.NextReminderAt, test.expectedNext) {
+				t.Fatalf("test-case: (%q); returned next reminder %v; expected %v", name, stored.NextReminderAt, test.expectedNext)
+			}
+		})
+	}
+}
+
+func TestReminderScheduler_SendDigest(t *testing.T) {
+	ctx := context.Background()
+	created := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	now := time.Date(2024, 3, 5, 9, 0, 0, 0, time.UTC)
+	repo := repository.NewMemoryTaskRepository(clock.NewFixed(created))
+
+	for _, task := range []models.Task{
+		{ID: "1", Title: "Overdue", Assignee: "alice", DueAt: ptr(now.Add(-time.Hour))},
+		{ID: "2", Title: "Also overdue", Assignee: "alice", DueAt: ptr(now.AddDate(0, 0, -2))},
+		{ID: "3", Title: "Overdue elsewhere", Assignee: "bob", DueAt: ptr(now.Add(-time.Hour)), WorkspaceID: "team-b"},
+		{ID: "4", Title: "Done", Assignee: "bob", Status: models.StatusDone, DueAt: ptr(now.Add(-time.Hour))},
+		{ID: "5", Title: "Unassigned", DueAt: ptr(now.Add(-time.Hour))},
+		{ID: "6", Title: "Not due", Assignee: "carol", DueAt: ptr(now.Add(time.Hour))},
+	} {
+		workspace := task.WorkspaceID
+		if workspace == "" {
+			workspace = "team-a"
+		}
+
+		if err := repo.Add(tenant.WithWorkspace(ctx, workspace), &task); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	notifications := repository.NewMemoryNotificationRepository()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	early := NewReminderScheduler(repo, notifications, &recordingNotifier{}, clock.NewFixed(now.Add(-2*time.Hour)), logger, 8)
+	if sent, err := early.SendDigest(ctx); err != nil || sent != 0 {
+		t.Fatalf("returned %v, %v; expected no digest before 8:00", sent, err)
+	}
+
+	notifier := &recordingNotifier{}
+	scheduler := NewReminderScheduler(repo, notifications, notifier, clock.NewFixed(now), logger, 8)
+
+	sent, err := scheduler.SendDigest(ctx)
+	if err != nil || sent != 2 {
+		t.Fatalf("returned %v, %v; expected digests for 2 assignees", sent, err)
+	}
+
+	if notifier.sent[0].Recipient != "alice" || len(notifier.sent[0].Tasks) != 2 ||
+		notifier.sent[1].Recipient != "bob" || len(notifier.sent[1].Tasks) != 1 {
+		t.Fatalf("sent %+v; expected 2 tasks to alice and 1 to bob", notifier.sent)
+	}
+
+	// Another instance sharing the repository doesn't send the digest again.
+	other := NewReminderScheduler(repo, notifications, notifier, clock.NewFixed(now.Add(time.Hour)), logger, 8)
+	if sent, err := other.SendDigest(ctx); err != nil || sent != 0 {
+		t.Fatalf("returned %v, %v; expected the digest to be sent once a day", sent, err)
+	}
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
+
+func equalTimes(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.Equal(*b)
+}
+
+func TestReminderScheduler_RunWithoutInterval(t *testing.T) {
+	clk := clock.New()
+	scheduler := NewReminderScheduler(repository.NewMemoryTaskRepository(clk), repository.NewMemoryNotificationRepository(),
+		&recordingNotifier{}, clk, slog.New(slog.NewTextHandler(io.Discard, nil)), 8)
+	done := make(chan struct{})
+
+	go func() {
+		scheduler.Run(context.Background(), 0)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("reminder scheduler kept running without an interval")
+	}
+}
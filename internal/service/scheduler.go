@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"task-tracker/internal/clock"
+	"task-tracker/internal/models"
+	"task-tracker/internal/recurrence"
+	"task-tracker/internal/repository"
+	"task-tracker/internal/tenant"
+)
+
+// Scheduler works on the repository directly, across all workspaces, so no policy applies.
+type Scheduler struct {
+	repo   repository.TaskRepository
+	clock  clock.Clock
+	logger *slog.Logger
+}
+
+func NewScheduler(repo repository.TaskRepository, clock clock.Clock, logger *slog.Logger) *Scheduler {
+	return &Scheduler{
+		repo:   repo,
+		clock:  clock,
+		logger: logger,
+	}
+}
+
+func (s *Scheduler) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		s.logger.Warn("Recurring tasks aren't advanced, the scheduler interval isn't positive",
+			slog.Duration("interval", interval))
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		added, err := s.Advance(ctx)
+		if err != nil && ctx.Err() == nil {
+			s.logger.Warn("Failed to advance recurring tasks", slog.String("error", err.Error()))
+		}
+
+		if added > 0 {
+			s.logger.Info("Added next occurrences of recurring tasks", slog.Int("count", added))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// A series whose rule has no further occurrence ends with its latest one.
+func (s *Scheduler) Advance(ctx context.Context) (int, error) {
+	heads, err := s.repo.GetSeriesHeads(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	now := s.clock.Now()
+	added := 0
+
+	var errs []error
+
+	for _, head := range heads {
+		if !due(head, now) {
+			continue
+		}
+
+		next, err := s.nextOccurrence(head, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("series %s: %w", head.SeriesID, err))
+			continue
+		}
+
+		err = s.repo.AddNextOccurrence(tenant.WithWorkspace(ctx, head.WorkspaceID), head.ID, next)
+
+		switch {
+		case errors.Is(err, models.ErrSeriesAdvanced):
+			// Another instance got there first.
+		case err != nil:
+			errs = append(errs, fmt.Errorf("series %s: %w", head.SeriesID, err))
+		case next != nil:
+			added++
+		}
+	}
+
+	return added, errors.Join(errs...)
+}
+
+func due(head models.Task, now time.Time) bool {
+	return models.TerminalStatus(head.Status) || (head.DueAt != nil && !head.DueAt.After(now))
+}
+
+// Passed occurrences are skipped, so a series left alone for a while continues with its
+// next upcoming occurrence instead of a backlog.
+func (s *Scheduler) nextOccurrence(head models.Task, now time.Time) (*models.Task, error) {
+	rule, err := recurrence.Parse(head.RRule, head.TimeZone, s.clock)
+	if err != nil {
+		return nil, err
+	}
+
+	occurrence := head.CreatedAt
+	if head.OccurrenceAt != nil {
+		occurrence = *head.OccurrenceAt
+	}
+
+	after := occurrence
+	if now.After(after) {
+		after = now
+	}
+
+	at, ok := rule.Next(occurrence, after)
+	if !ok {
+		return nil, nil
+	}
+
+	due := at
+
+	return &models.Task{
+		ID:           uuid.New().String(),
+		Title:        head.Title,
+		Description:  head.Description,
+		Status:       models.StatusTodo,
+		CreatedBy:    head.CreatedBy,
+		Assignee:     head.Assignee,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+		DueAt:        &due,
+		RRule:        head.RRule,
+		TimeZone:     head.TimeZone,
+		SeriesID:     head.SeriesID,
+		OccurrenceAt: &at,
+		Reminders:    head.Reminders,
+		Labels:       head.Labels,
+	}, nil
+}
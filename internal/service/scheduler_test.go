@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"task-tracker/internal/clock"
+	"task-tracker/internal/models"
+	"task-tracker/internal/repository"
+	"task-tracker/internal/tenant"
+)
+
+func TestScheduler_Advance(t *testing.T) {
+	created := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	due := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		rule          string
+		status        string
+		now           time.Time
+		expectedAdded int
+		expectedDue   time.Time
+	}{
+		"open and not due yet": {
+			rule:   "FREQ=WEEKLY",
+			status: models.StatusTodo,
+			now:    due.Add(-time.Hour),
+		},
+
+		"done early": {
+			rule:          "FREQ=WEEKLY",
+			status:        models.StatusDone,
+			now:           due.Add(-time.Hour),
+			expectedAdded: 1,
+			expectedDue:   due.AddDate(0, 0, 7),
+		},
+
+		"skipped": {
+			rule:          "FREQ=WEEKLY",
+			status:        models.StatusSkipped,
+			now:           due.Add(-time.Hour),
+			expectedAdded: 1,
+			expectedDue:   due.AddDate(0, 0, 7),
+		},
+
+		"overdue": {
+			rule:          "FREQ=DAILY",
+			status:        models.StatusTodo,
+			now:           due.AddDate(0, 0, 3).Add(time.Hour),
+			expectedAdded: 1,
+			expectedDue:   due.AddDate(0, 0, 4),
+		},
+
+		"series ended": {
+			rule:   "FREQ=DAILY;COUNT=1",
+			status: models.StatusDone,
+			now:    due,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := tenant.WithWorkspace(context.Background(), "team-a")
+			repo := repository.NewMemoryTaskRepository(clock.NewFixed(created))
+			service := NewDefaultTaskService(repo, clock.NewFixed(created), AllowAll{})
+
+			head := &models.Task{Title: "Water plants", Description: "All of them", Status: test.status, DueAt: &due, RRule: test.rule}
+			if err := service.Add(ctx, head); err != nil {
+				t.Fatalf("test-case: (%q); unexpected error: %v", name, err)
+			}
+
+			scheduler := NewScheduler(repo, clock.NewFixed(test.now), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			for range 2 {
+				added, err := scheduler.Advance(context.Background())
+				if err != nil {
+					t.Fatalf("test-case: (%q); unexpected error: %v", name, err)
+				}
+
+				if added != test.expectedAdded {
+					t.Fatalf("test-case: (%q); added %v; expected %v", name, added, test.expectedAdded)
+				}
+
+				// The second pass sees the new occurrence, which is neither finished nor due.
+				test.expectedAdded = 0
+			}
+
+			tasks, err := repo.GetAll(ctx)
+			if err != nil {
+				t.Fatalf("test-case: (%q); unexpected error: %v", name, err)
+			}
+
+			for _, task := range tasks {
+				if task.ID == head.ID {
+					continue
+				}
+
+				if task.SeriesID != head.ID || task.Status != models.StatusTodo || task.WorkspaceID != "team-a" {
+					t.Fatalf("test-case: (%q); returned %+v; expected a new occurrence of %v", name, task, head.ID)
+				}
+
+				if !task.DueAt.Equal(test.expectedDue) || !task.OccurrenceAt.Equal(test.expectedDue) {
+					t.Fatalf("test-case: (%q); returned due %v; expected %v", name, task.DueAt, test.expectedDue)
+				}
+			}
+		})
+	}
+}
+
+func TestScheduler_RunWithoutInterval(t *testing.T) {
+	scheduler := NewScheduler(repository.NewMemoryTaskRepository(clock.New()), clock.New(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	done := make(chan struct{})
+
+	go func() {
+		scheduler.Run(context.Background(), 0)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("scheduler kept running without an interval")
+	}
+}
+
+func TestService_SkipAndReschedule(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	due := now.AddDate(0, 0, 3)
+	service := NewDefaultTaskService(repository.NewMemoryTaskRepository(clock.NewFixed(now)), clock.NewFixed(now), AllowAll{})
+
+	recurring := &models.Task{Title: "Water plants", Description: "All of them", Status: models.StatusTodo, DueAt: &due, RRule: "FREQ=WEEKLY"}
+	single := &models.Task{Title: "Buy a plant", Description: "A big one", Status: models.StatusTodo}
+
+	for _, task := range []*models.Task{recurring, single} {
+		if err := service.Add(ctx, task); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if _, err := service.Skip(ctx, single.ID); !errors.Is(err, models.ErrTaskNotRecurring) {
+		t.Fatalf("returned %v; expected %v", err, models.ErrTaskNotRecurring)
+	}
+
+	skipped, err := service.Skip(ctx, recurring.ID)
+	if err != nil || skipped.Status != models.StatusSkipped {
+		t.Fatalf("returned %+v, %v; expected a skipped occurrence", skipped, err)
+	}
+
+	later := due.Add(2 * time.Hour)
+
+	rescheduled, err := service.Reschedule(ctx, recurring.ID, later)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !rescheduled.DueAt.Equal(later) || !rescheduled.OccurrenceAt.Equal(due) {
+		t.Fatalf("returned due %v at occurrence %v; expected due %v at occurrence %v",
+			rescheduled.DueAt, rescheduled.OccurrenceAt, later, due)
+	}
+
+	if _, err := service.Reschedule(ctx, "missing", later); !errors.Is(err, models.ErrTaskNotFound) {
+		t.Fatalf("returned %v; expected %v", err, models.ErrTaskNotFound)
+	}
+}
@@ -6,49 +6,137 @@ import (
 
 	"github.com/google/uuid"
 
+	"task-tracker/internal/auth"
+	"task-tracker/internal/clock"
 	"task-tracker/internal/models"
+	"task-tracker/internal/recurrence"
 	"task-tracker/internal/repository"
 )
 
 type TaskService interface {
 	Add(ctx context.Context, task *models.Task) error
+	// CheckAdd returns the error Add would fail with, without adding anything. Dry runs
+	// check rows with it.
+	CheckAdd(ctx context.Context, task *models.Task) error
 	Delete(ctx context.Context, id string) error
 	Get(ctx context.Context, id string) (models.Task, error)
 	GetAll(ctx context.Context) ([]models.Task, error)
+	FindByCalendar(ctx context.Context, name, uid string) ([]models.Task, error)
+	Stream(ctx context.Context, fn func(models.Task) error) error
 	Update(ctx context.Context, updatedTask *models.Task) error
+	Skip(ctx context.Context, id string) (models.Task, error)
+	Reschedule(ctx context.Context, id string, dueAt time.Time) (models.Task, error)
 }
 
 type DefaultTaskService struct {
-	repo repository.TaskRepository
+	repo   repository.TaskRepository
+	clock  clock.Clock
+	policy Policy
 }
 
-func NewDefaultTaskService(repo repository.TaskRepository) *DefaultTaskService {
+func NewDefaultTaskService(repo repository.TaskRepository, clock clock.Clock, policy Policy) *DefaultTaskService {
 	return &DefaultTaskService{
-		repo: repo,
+		repo:   repo,
+		clock:  clock,
+		policy: policy,
 	}
 }
 
 func (s *DefaultTaskService) Add(ctx context.Context, task *models.Task) error {
-	if exists, _ := s.repo.Exists(ctx, task.ID); exists {
-		return models.ErrTaskExists
+	if err := s.CheckAdd(ctx, task); err != nil {
+		return err
+	}
+
+	// Imports keep the IDs of the tasks they restore.
+	if task.ID == "" {
+		task.ID = uuid.New().String()
 	}
 
-	task.ID = uuid.New().String()
-	task.CreatedAt = time.Now().Format(time.RFC3339Nano)
+	task.CreatedAt = s.clock.Now()
 	task.UpdatedAt = task.CreatedAt
+	task.CreatedBy = ""
+	task.RemindedAt = nil
+
+	if principal, ok := auth.PrincipalFromContext(ctx); ok {
+		task.CreatedBy = principal.Subject
+	}
+
+	if err := s.startSeries(task); err != nil {
+		return err
+	}
 
 	return s.repo.Add(ctx, task)
 }
 
+func (s *DefaultTaskService) CheckAdd(ctx context.Context, task *models.Task) error {
+	if err := s.policy.Authorize(ctx, ActionCreate, nil); err != nil {
+		return err
+	}
+
+	if task.ID == "" {
+		return nil
+	}
+
+	taken, err := s.repo.IDTaken(ctx, task.ID)
+	if err != nil {
+		return err
+	}
+
+	if taken {
+		return models.ErrTaskExists
+	}
+
+	return nil
+}
+
+func (s *DefaultTaskService) startSeries(task *models.Task) error {
+	task.SeriesID = ""
+	task.OccurrenceAt = nil
+	task.Advanced = false
+
+	if task.RRule == "" {
+		task.TimeZone = ""
+		return nil
+	}
+
+	if task.DueAt == nil {
+		return models.ErrDueAtIsEmpty
+	}
+
+	rule, err := recurrence.Parse(task.RRule, task.TimeZone, s.clock)
+	if err != nil {
+		return err
+	}
+
+	start := task.DueAt.UTC()
+
+	task.DueAt = &start
+	task.RRule = rule.Bound(start).String()
+	task.TimeZone = rule.Location().String()
+	task.SeriesID = task.ID
+	task.OccurrenceAt = &start
+
+	return nil
+}
+
 func (s *DefaultTaskService) Delete(ctx context.Context, id string) error {
-	if exists, _ := s.repo.Exists(ctx, id); !exists {
-		return models.ErrTaskNotFound
+	task, err := s.existing(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.policy.Authorize(ctx, ActionDelete, &task); err != nil {
+		return err
 	}
 
 	return s.repo.Delete(ctx, id)
 }
 
 func (s *DefaultTaskService) Get(ctx context.Context, id string) (models.Task, error) {
+	if err := s.policy.Authorize(ctx, ActionRead, nil); err != nil {
+		return models.Task{}, err
+	}
+
 	if exists, _ := s.repo.Exists(ctx, id); !exists {
 		return models.Task{}, models.ErrTaskNotFound
 	}
@@ -57,13 +145,114 @@ func (s *DefaultTaskService) Get(ctx context.Context, id string) (models.Task, e
 }
 
 func (s *DefaultTaskService) GetAll(ctx context.Context) ([]models.Task, error) {
+	if err := s.policy.Authorize(ctx, ActionRead, nil); err != nil {
+		return nil, err
+	}
+
 	return s.repo.GetAll(ctx)
 }
 
+func (s *DefaultTaskService) FindByCalendar(ctx context.Context, name, uid string) ([]models.Task, error) {
+	if err := s.policy.Authorize(ctx, ActionRead, nil); err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindByCalendar(ctx, name, uid)
+}
+
+func (s *DefaultTaskService) Stream(ctx context.Context, fn func(models.Task) error) error {
+	if err := s.policy.Authorize(ctx, ActionRead, nil); err != nil {
+		return err
+	}
+
+	return s.repo.Stream(ctx, fn)
+}
+
 func (s *DefaultTaskService) Update(ctx context.Context, updatedTask *models.Task) error {
-	if exists, _ := s.repo.Exists(ctx, updatedTask.ID); !exists {
-		return models.ErrTaskNotFound
+	task, err := s.existing(ctx, updatedTask.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.policy.Authorize(ctx, ActionUpdate, &task); err != nil {
+		return err
+	}
+
+	// The creator never changes and omitted fields keep their current values.
+	updatedTask.CreatedBy = task.CreatedBy
+
+	if updatedTask.Assignee == "" {
+		updatedTask.Assignee = task.Assignee
 	}
 
+	if updatedTask.DueAt == nil && !updatedTask.ClearDueAt {
+		updatedTask.DueAt = task.DueAt
+	}
+
+	if updatedTask.Reminders == nil {
+		updatedTask.Reminders = task.Reminders
+	}
+
+	if updatedTask.DueAt == nil && (task.Recurring() || len(updatedTask.Reminders) > 0) {
+		return models.ErrDueAtIsEmpty
+	}
+
+	if updatedTask.Labels == nil {
+		updatedTask.Labels = task.Labels
+	}
+
+	updatedTask.RemindedAt = task.RemindedAt
+
 	return s.repo.Update(ctx, updatedTask)
 }
+
+func (s *DefaultTaskService) Skip(ctx context.Context, id string) (models.Task, error) {
+	task, err := s.existing(ctx, id)
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	if err := s.policy.Authorize(ctx, ActionUpdate, &task); err != nil {
+		return models.Task{}, err
+	}
+
+	if !task.Recurring() {
+		return models.Task{}, models.ErrTaskNotRecurring
+	}
+
+	task.Status = models.StatusSkipped
+
+	return s.save(ctx, &task)
+}
+
+func (s *DefaultTaskService) Reschedule(ctx context.Context, id string, dueAt time.Time) (models.Task, error) {
+	task, err := s.existing(ctx, id)
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	if err := s.policy.Authorize(ctx, ActionUpdate, &task); err != nil {
+		return models.Task{}, err
+	}
+
+	dueAt = dueAt.UTC()
+	task.DueAt = &dueAt
+
+	return s.save(ctx, &task)
+}
+
+func (s *DefaultTaskService) save(ctx context.Context, task *models.Task) (models.Task, error) {
+	if err := s.repo.Update(ctx, task); err != nil {
+		return models.Task{}, err
+	}
+
+	return s.repo.Get(ctx, task.ID)
+}
+
+func (s *DefaultTaskService) existing(ctx context.Context, id string) (models.Task, error) {
+	if exists, _ := s.repo.Exists(ctx, id); !exists {
+		return models.Task{}, models.ErrTaskNotFound
+	}
+
+	return s.repo.Get(ctx, id)
+}
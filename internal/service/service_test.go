@@ -3,43 +3,55 @@ package service
 import (
 	"context"
 	"errors"
+	"net/http"
 	"testing"
+	"time"
 
+	"task-tracker/internal/auth"
+	"task-tracker/internal/clock"
 	"task-tracker/internal/models"
 	"task-tracker/internal/repository"
 )
 
 func TestAdd(t *testing.T) {
+	fixedTime := time.Date(2025, time.April, 9, 18, 21, 41, 0, time.UTC)
+
 	tests := map[string]struct {
 		service *DefaultTaskService
 		result  error
 	}{
 		"successfully adds a valid task": {
 			service: &DefaultTaskService{
+				policy: AllowAll{},
 				repo: &repository.MockTaskRepository{
 					ForceRepositoryError: false,
 					IsExist:              false,
 				},
+				clock: clock.NewFixed(fixedTime),
 			},
 			result: nil,
 		},
 
 		"add task fails when task already exists": {
 			service: &DefaultTaskService{
+				policy: AllowAll{},
 				repo: &repository.MockTaskRepository{
 					ForceRepositoryError: false,
 					IsExist:              true,
 				},
+				clock: clock.NewFixed(fixedTime),
 			},
 			result: models.ErrTaskExists,
 		},
 
 		"add task fails due to repository error": {
 			service: &DefaultTaskService{
+				policy: AllowAll{},
 				repo: &repository.MockTaskRepository{
 					ForceRepositoryError: true,
 					IsExist:              false,
 				},
+				clock: clock.NewFixed(fixedTime),
 			},
 			result: repository.ErrAddingTask,
 		},
@@ -49,11 +61,16 @@ func TestAdd(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 
-			err := test.service.Add(context.Background(), &models.Task{})
+			task := &models.Task{ID: "2f1c2f4e-4d0b-4c43-9a55-0ad3f7c1e9a1"}
+			err := test.service.Add(context.Background(), task)
 
 			if !errors.Is(err, test.result) {
 				t.Fatalf("test-case: (%q); returned %v; expected %v", name, err, test.result)
 			}
+
+			if err == nil && (!task.CreatedAt.Equal(fixedTime) || !task.UpdatedAt.Equal(fixedTime)) {
+				t.Fatalf("test-case: (%q); timestamps [%v %v]; expected %v", name, task.CreatedAt, task.UpdatedAt, fixedTime)
+			}
 		})
 	}
 }
@@ -65,6 +82,7 @@ func TestDelete(t *testing.T) {
 	}{
 		"successfully delete a valid task": {
 			service: &DefaultTaskService{
+				policy: AllowAll{},
 				repo: &repository.MockTaskRepository{
 					ForceRepositoryError: false,
 					IsExist:              true,
@@ -75,6 +93,7 @@ func TestDelete(t *testing.T) {
 
 		"delete task fails when task doesn't exist": {
 			service: &DefaultTaskService{
+				policy: AllowAll{},
 				repo: &repository.MockTaskRepository{
 					ForceRepositoryError: false,
 					IsExist:              false,
@@ -85,12 +104,14 @@ func TestDelete(t *testing.T) {
 
 		"delete task fails due to repository error": {
 			service: &DefaultTaskService{
+				policy: AllowAll{},
 				repo: &repository.MockTaskRepository{
 					ForceRepositoryError: true,
 					IsExist:              true,
 				},
 			},
-			result: repository.ErrDeletingTask,
+			// The task is loaded for the ownership check before it's deleted.
+			result: repository.ErrGettingTask,
 		},
 	}
 
@@ -114,6 +135,7 @@ func TestGet(t *testing.T) {
 	}{
 		"successfully get a valid task": {
 			service: &DefaultTaskService{
+				policy: AllowAll{},
 				repo: &repository.MockTaskRepository{
 					ForceRepositoryError: false,
 					IsExist:              true,
@@ -124,6 +146,7 @@ func TestGet(t *testing.T) {
 
 		"get task fails when task doesn't exist": {
 			service: &DefaultTaskService{
+				policy: AllowAll{},
 				repo: &repository.MockTaskRepository{
 					ForceRepositoryError: false,
 					IsExist:              false,
@@ -134,6 +157,7 @@ func TestGet(t *testing.T) {
 
 		"get task fails due to repository error": {
 			service: &DefaultTaskService{
+				policy: AllowAll{},
 				repo: &repository.MockTaskRepository{
 					ForceRepositoryError: true,
 					IsExist:              true,
@@ -163,6 +187,7 @@ func TestGetAll(t *testing.T) {
 	}{
 		"successfully get all tasks": {
 			service: &DefaultTaskService{
+				policy: AllowAll{},
 				repo: &repository.MockTaskRepository{
 					ForceRepositoryError: false,
 				},
@@ -172,6 +197,7 @@ func TestGetAll(t *testing.T) {
 
 		"get all tasks fails due to repository error": {
 			service: &DefaultTaskService{
+				policy: AllowAll{},
 				repo: &repository.MockTaskRepository{
 					ForceRepositoryError: true,
 				},
@@ -200,6 +226,7 @@ func TestUpdate(t *testing.T) {
 	}{
 		"successfully update a valid task": {
 			service: &DefaultTaskService{
+				policy: AllowAll{},
 				repo: &repository.MockTaskRepository{
 					ForceRepositoryError: false,
 					IsExist:              true,
@@ -210,6 +237,7 @@ func TestUpdate(t *testing.T) {
 
 		"update task fails when task doesn't exist": {
 			service: &DefaultTaskService{
+				policy: AllowAll{},
 				repo: &repository.MockTaskRepository{
 					ForceRepositoryError: false,
 					IsExist:              false,
@@ -220,12 +248,14 @@ func TestUpdate(t *testing.T) {
 
 		"update task fails due to repository error": {
 			service: &DefaultTaskService{
+				policy: AllowAll{},
 				repo: &repository.MockTaskRepository{
 					ForceRepositoryError: true,
 					IsExist:              true,
 				},
 			},
-			result: repository.ErrUpdatingTask,
+			// The task is loaded for the ownership check before it's updated.
+			result: repository.ErrGettingTask,
 		},
 	}
 
@@ -241,3 +271,40 @@ func TestUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestRoleEnforcement(t *testing.T) {
+	repo := repository.NewMemoryTaskRepository(clock.New())
+	service := NewDefaultTaskService(repo, clock.New(), RolePolicy{})
+
+	as := func(subject string, role models.Role) context.Context {
+		return auth.WithPrincipal(context.Background(), auth.Principal{Subject: subject, Roles: []string{string(role)}})
+	}
+
+	task := &models.Task{Title: "Title", Description: "Description", Status: "Todo"}
+	if err := service.Add(as("alice", models.RoleMember), task); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if task.CreatedBy != "alice" {
+		t.Fatalf("created by %q; expected %q", task.CreatedBy, "alice")
+	}
+
+	update := &models.Task{ID: task.ID, Title: "New", Description: "Description", Status: "Done"}
+
+	if err := service.Update(as("bob", models.RoleMember), update); !errors.Is(err, models.ErrForbidden) {
+		t.Fatalf("returned %v; expected %v", err, models.ErrForbidden)
+	}
+
+	var modelError models.Error
+	if err := service.Delete(as("alice", models.RoleMember), task.ID); !errors.As(err, &modelError) || modelError.StatusCode != http.StatusForbidden {
+		t.Fatalf("returned %v; expected a 403 error", err)
+	}
+
+	if err := service.Update(as("alice", models.RoleMember), update); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := service.Delete(as("carol", models.RoleMaintainer), task.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
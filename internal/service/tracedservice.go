@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"task-tracker/internal/models"
+)
+
+const taskIDKey = attribute.Key("task.id")
+
+type TracedTaskService struct {
+	next   TaskService
+	tracer trace.Tracer
+}
+
+func NewTracedTaskService(next TaskService, provider trace.TracerProvider) *TracedTaskService {
+	return &TracedTaskService{
+		next:   next,
+		tracer: provider.Tracer("task-tracker/internal/service"),
+	}
+}
+
+func (s *TracedTaskService) Add(ctx context.Context, task *models.Task) (err error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.Add")
+	defer func() { endSpan(span, err, taskIDKey.String(task.ID)) }()
+
+	return s.next.Add(ctx, task)
+}
+
+func (s *TracedTaskService) CheckAdd(ctx context.Context, task *models.Task) (err error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.CheckAdd")
+	defer func() { endSpan(span, err, taskIDKey.String(task.ID)) }()
+
+	return s.next.CheckAdd(ctx, task)
+}
+
+func (s *TracedTaskService) Delete(ctx context.Context, id string) (err error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.Delete", trace.WithAttributes(taskIDKey.String(id)))
+	defer func() { endSpan(span, err) }()
+
+	return s.next.Delete(ctx, id)
+}
+
+func (s *TracedTaskService) Get(ctx context.Context, id string) (_ models.Task, err error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.Get", trace.WithAttributes(taskIDKey.String(id)))
+	defer func() { endSpan(span, err) }()
+
+	return s.next.Get(ctx, id)
+}
+
+func (s *TracedTaskService) GetAll(ctx context.Context) (_ []models.Task, err error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.GetAll")
+	defer func() { endSpan(span, err) }()
+
+	return s.next.GetAll(ctx)
+}
+
+func (s *TracedTaskService) FindByCalendar(ctx context.Context, name, uid string) (_ []models.Task, err error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.FindByCalendar")
+	defer func() { endSpan(span, err) }()
+
+	return s.next.FindByCalendar(ctx, name, uid)
+}
+
+func (s *TracedTaskService) Stream(ctx context.Context, fn func(models.Task) error) (err error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.Stream")
+	defer func() { endSpan(span, err) }()
+
+	return s.next.Stream(ctx, fn)
+}
+
+func (s *TracedTaskService) Update(ctx context.Context, updatedTask *models.Task) (err error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.Update", trace.WithAttributes(taskIDKey.String(updatedTask.ID)))
+	defer func() { endSpan(span, err) }()
+
+	return s.next.Update(ctx, updatedTask)
+}
+
+func (s *TracedTaskService) Skip(ctx context.Context, id string) (_ models.Task, err error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.Skip", trace.WithAttributes(taskIDKey.String(id)))
+	defer func() { endSpan(span, err) }()
+
+	return s.next.Skip(ctx, id)
+}
+
+func (s *TracedTaskService) Reschedule(ctx context.Context, id string, dueAt time.Time) (_ models.Task, err error) {
+	ctx, span := s.tracer.Start(ctx, "TaskService.Reschedule", trace.WithAttributes(taskIDKey.String(id)))
+	defer func() { endSpan(span, err) }()
+
+	return s.next.Reschedule(ctx, id, dueAt)
+}
+
+func endSpan(span trace.Span, err error, attributes ...attribute.KeyValue) {
+	span.SetAttributes(attributes...)
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"task-tracker/internal/auth"
+	"task-tracker/internal/clock"
+	"task-tracker/internal/models"
+	"task-tracker/internal/repository"
+	"task-tracker/internal/tenant"
+)
+
+// Everyone is a member of the default workspace with their global role, admins of every
+// workspace, and other workspaces need a membership whose role replaces the global one.
+type WorkspaceService struct {
+	repo   repository.WorkspaceRepository
+	clock  clock.Clock
+	policy Policy
+}
+
+func NewWorkspaceService(repo repository.WorkspaceRepository, clock clock.Clock, policy Policy) *WorkspaceService {
+	return &WorkspaceService{
+		repo:   repo,
+		clock:  clock,
+		policy: policy,
+	}
+}
+
+func (s *WorkspaceService) Enter(ctx context.Context, id string) (context.Context, error) {
+	if id == "" {
+		id = tenant.DefaultWorkspace
+	}
+
+	// The default workspace can't be removed, so only others need a lookup.
+	if id != tenant.DefaultWorkspace {
+		if _, err := s.repo.Get(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx = tenant.WithWorkspace(ctx, id)
+
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok || principal.Role() == models.RoleAdmin {
+		return ctx, nil
+	}
+
+	member, err := s.repo.Member(ctx, id, principal.Subject)
+
+	switch {
+	case errors.Is(err, models.ErrMemberNotFound) && id == tenant.DefaultWorkspace:
+		return ctx, nil
+	case errors.Is(err, models.ErrMemberNotFound):
+		return nil, models.ErrNotAMember
+	case err != nil:
+		return nil, err
+	}
+
+	principal.Roles = []string{string(member.Role)}
+
+	return auth.WithPrincipal(ctx, principal), nil
+}
+
+func (s *WorkspaceService) Create(ctx context.Context, request models.CreateWorkspaceRequest) (models.Workspace, error) {
+	if err := s.policy.Authorize(ctx, ActionManage, nil); err != nil {
+		return models.Workspace{}, err
+	}
+
+	if err := request.Validate(); err != nil {
+		return models.Workspace{}, err
+	}
+
+	workspace := models.Workspace{ID: request.ID, Name: request.Name, CreatedAt: s.clock.Now()}
+
+	if err := s.repo.Add(ctx, &workspace); err != nil {
+		return models.Workspace{}, err
+	}
+
+	return workspace, nil
+}
+
+func (s *WorkspaceService) List(ctx context.Context) ([]models.Workspace, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok || principal.Role() == models.RoleAdmin {
+		return s.repo.GetAll(ctx)
+	}
+
+	workspaces, err := s.repo.GetAllForSubject(ctx, principal.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, workspace := range workspaces {
+		if workspace.ID == tenant.DefaultWorkspace {
+			return workspaces, nil
+		}
+	}
+
+	defaultWorkspace, err := s.repo.Get(ctx, tenant.DefaultWorkspace)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]models.Workspace{defaultWorkspace}, workspaces...), nil
+}
+
+func (s *WorkspaceService) Get(ctx context.Context) (models.Workspace, error) {
+	if err := s.policy.Authorize(ctx, ActionRead, nil); err != nil {
+		return models.Workspace{}, err
+	}
+
+	return s.repo.Get(ctx, tenant.Workspace(ctx))
+}
+
+func (s *WorkspaceService) Members(ctx context.Context) ([]models.WorkspaceMember, error) {
+	if err := s.policy.Authorize(ctx, ActionRead, nil); err != nil {
+		return nil, err
+	}
+
+	return s.repo.Members(ctx, tenant.Workspace(ctx))
+}
+
+func (s *WorkspaceService) SetMember(ctx context.Context, subject string, request models.SetMemberRequest) (models.WorkspaceMember, error) {
+	if err := s.policy.Authorize(ctx, ActionManage, nil); err != nil {
+		return models.WorkspaceMember{}, err
+	}
+
+	if err := request.Validate(); err != nil {
+		return models.WorkspaceMember{}, err
+	}
+
+	member := models.WorkspaceMember{WorkspaceID: tenant.Workspace(ctx), Subject: subject, Role: request.Role}
+
+	if err := s.repo.SetMember(ctx, &member); err != nil {
+		return models.WorkspaceMember{}, err
+	}
+
+	return member, nil
+}
+
+func (s *WorkspaceService) RemoveMember(ctx context.Context, subject string) error {
+	if err := s.policy.Authorize(ctx, ActionManage, nil); err != nil {
+		return err
+	}
+
+	return s.repo.RemoveMember(ctx, tenant.Workspace(ctx), subject)
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"task-tracker/internal/auth"
+	"task-tracker/internal/clock"
+	"task-tracker/internal/models"
+	"task-tracker/internal/repository"
+	"task-tracker/internal/tenant"
+)
+
+func TestWorkspaceService_Enter(t *testing.T) {
+	repo := repository.NewMemoryWorkspaceRepository(clock.New())
+	service := NewWorkspaceService(repo, clock.New(), RolePolicy{})
+
+	as := func(subject string, role models.Role) context.Context {
+		return auth.WithPrincipal(context.Background(), auth.Principal{Subject: subject, Roles: []string{string(role)}})
+	}
+
+	if _, err := service.Create(as("root", models.RoleAdmin), models.CreateWorkspaceRequest{ID: "team-a", Name: "Team A"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := repo.SetMember(context.Background(), &models.WorkspaceMember{WorkspaceID: "team-a", Subject: "alice", Role: models.RoleMaintainer}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := map[string]struct {
+		ctx          context.Context
+		workspace    string
+		expectedRole models.Role
+		err          error
+	}{
+		"member gets the workspace role":  {as("alice", models.RoleViewer), "team-a", models.RoleMaintainer, nil},
+		"outsider is rejected":            {as("bob", models.RoleMaintainer), "team-a", "", models.ErrNotAMember},
+		"admin enters every workspace":    {as("root", models.RoleAdmin), "team-a", models.RoleAdmin, nil},
+		"everyone is in the default":      {as("bob", models.RoleMember), "", models.RoleMember, nil},
+		"unknown workspace":               {as("root", models.RoleAdmin), "team-z", "", models.ErrWorkspaceNotFound},
+		"no caller when auth is disabled": {context.Background(), "team-a", "", nil},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, err := service.Enter(test.ctx, test.workspace)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("test-case: (%q); returned %v; expected %v", name, err, test.err)
+			}
+
+			if err != nil {
+				return
+			}
+
+			expectedWorkspace := test.workspace
+			if expectedWorkspace == "" {
+				expectedWorkspace = tenant.DefaultWorkspace
+			}
+
+			if tenant.Workspace(ctx) != expectedWorkspace {
+				t.Fatalf("test-case: (%q); workspace %q; expected %q", name, tenant.Workspace(ctx), expectedWorkspace)
+			}
+
+			if principal, ok := auth.PrincipalFromContext(ctx); ok && principal.Role() != test.expectedRole {
+				t.Fatalf("test-case: (%q); role %q; expected %q", name, principal.Role(), test.expectedRole)
+			}
+		})
+	}
+}
+
+func TestWorkspaceService_Manage(t *testing.T) {
+	repo := repository.NewMemoryWorkspaceRepository(clock.New())
+	service := NewWorkspaceService(repo, clock.New(), RolePolicy{})
+
+	member := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "alice", Roles: []string{string(models.RoleMaintainer)}})
+
+	if _, err := service.Create(member, models.CreateWorkspaceRequest{ID: "team-a", Name: "Team A"}); !errors.Is(err, models.ErrForbidden) {
+		t.Fatalf("returned %v; expected %v", err, models.ErrForbidden)
+	}
+
+	admin := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "root", Roles: []string{string(models.RoleAdmin)}})
+
+	if _, err := service.Create(admin, models.CreateWorkspaceRequest{ID: "Team A", Name: "Team A"}); !errors.Is(err, models.ErrInvalidWorkspaceID) {
+		t.Fatalf("returned %v; expected %v", err, models.ErrInvalidWorkspaceID)
+	}
+
+	if _, err := service.Create(admin, models.CreateWorkspaceRequest{ID: tenant.DefaultWorkspace, Name: "Again"}); !errors.Is(err, models.ErrWorkspaceExists) {
+		t.Fatalf("returned %v; expected %v", err, models.ErrWorkspaceExists)
+	}
+
+	ctx, err := service.Enter(admin, tenant.DefaultWorkspace)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := service.SetMember(ctx, "alice", models.SetMemberRequest{Role: "owner"}); !errors.Is(err, models.ErrInvalidRole) {
+		t.Fatalf("returned %v; expected %v", err, models.ErrInvalidRole)
+	}
+
+	if _, err := service.SetMember(ctx, "alice", models.SetMemberRequest{Role: models.RoleViewer}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	workspaces, err := service.List(member)
+	if err != nil || len(workspaces) != 1 || workspaces[0].ID != tenant.DefaultWorkspace {
+		t.Fatalf("returned %v, %v; expected only the default workspace", workspaces, err)
+	}
+}
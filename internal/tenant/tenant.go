@@ -0,0 +1,22 @@
+package tenant
+
+import "context"
+
+// DefaultWorkspace holds tasks created through the unscoped /tasks routes and every
+// task that existed before workspaces were introduced.
+const DefaultWorkspace = "default"
+
+type workspaceKey struct{}
+
+func WithWorkspace(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, workspaceKey{}, id)
+}
+
+// Workspace is DefaultWorkspace if ctx carries none.
+func Workspace(ctx context.Context) string {
+	if id, ok := ctx.Value(workspaceKey{}).(string); ok && id != "" {
+		return id
+	}
+
+	return DefaultWorkspace
+}
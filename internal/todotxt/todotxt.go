@@ -0,0 +1,238 @@
+package todotxt
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"task-tracker/internal/models"
+)
+
+const dateLayout = "2006-01-02"
+
+// priorityLabel prefixes the label holding a task's priority. todo.txt clients move
+// the priority of completed tasks to a pri: extra, which the label is named after.
+const priorityLabel = "pri:"
+
+var ErrInvalidLine = errors.New("invalid todo.txt line")
+
+// Format maps a task to a todo.txt line, without a line break, as follows:
+//   - done tasks are completed ("x"), with their update date as completion date;
+//     canceled and skipped ones too, along with their status;
+//   - the creation date is the task's;
+//   - labels are +projects, except labels starting with "@", which are @contexts,
+//     and "pri:A" to "pri:Z", which are the priority;
+//   - the due date, assignee, other statuses and the ID are due:, assignee:,
+//     status: and id: extras.
+//
+// Other extras and words stay in the title. Title words that would read as one of
+// the above, such as a leading "x" or date, a +project or a due: extra, are escaped
+// with a backslash, and so are words starting with one. Spaces in labels and
+// assignees become underscores. Descriptions, recurrence rules and reminders have
+// no todo.txt equivalent and are left out.
+func Format(task models.Task) string {
+	var words []string
+
+	done := models.TerminalStatus(task.Status)
+	priority, labels := splitPriority(task.Labels)
+
+	if done {
+		words = append(words, "x")
+
+		if !task.UpdatedAt.IsZero() {
+			words = append(words, task.UpdatedAt.UTC().Format(dateLayout))
+		}
+	} else if priority != "" {
+		words = append(words, "("+priority+")")
+	}
+
+	if !task.CreatedAt.IsZero() && (!done || !task.UpdatedAt.IsZero()) {
+		words = append(words, task.CreatedAt.UTC().Format(dateLayout))
+	}
+
+	for i, word := range strings.Fields(task.Title) {
+		words = append(words, escape(word, i == 0))
+	}
+
+	for _, label := range labels {
+		label = strings.Join(strings.Fields(label), "_")
+
+		if strings.HasPrefix(label, "@") {
+			words = append(words, label)
+		} else {
+			words = append(words, "+"+label)
+		}
+	}
+
+	if done && priority != "" {
+		words = append(words, priorityLabel+priority)
+	}
+
+	if task.DueAt != nil {
+		words = append(words, "due:"+formatDue(*task.DueAt))
+	}
+
+	if task.Assignee != "" {
+		words = append(words, "assignee:"+strings.Join(strings.Fields(task.Assignee), "_"))
+	}
+
+	if task.Status != models.StatusTodo && task.Status != models.StatusDone {
+		words = append(words, "status:"+task.Status)
+	}
+
+	if task.ID != "" {
+		words = append(words, "id:"+task.ID)
+	}
+
+	return strings.Join(words, " ")
+}
+
+// The completion date, if any, becomes the task's UpdatedAt.
+func Parse(line string) (models.Task, error) {
+	words := strings.Fields(line)
+	task := models.Task{Status: models.StatusTodo, Labels: []string{}}
+
+	if len(words) > 0 && words[0] == "x" {
+		task.Status = models.StatusDone
+		words = words[1:]
+
+		if at, ok := parseDate(words); ok {
+			task.UpdatedAt = at
+			words = words[1:]
+		}
+	} else if len(words) > 0 && isPriority(words[0]) {
+		task.Labels = append(task.Labels, priorityLabel+words[0][1:2])
+		words = words[1:]
+	}
+
+	if at, ok := parseDate(words); ok {
+		task.CreatedAt = at
+		words = words[1:]
+	}
+
+	var title []string
+
+	for _, word := range words {
+		key, value, extra := strings.Cut(word, ":")
+		extra = extra && key != "" && value != ""
+
+		switch {
+		case len(word) > 1 && (word[0] == '+' || word[0] == '@'):
+			label := strings.TrimPrefix(word, "+")
+			if !slices.Contains(task.Labels, label) {
+				task.Labels = append(task.Labels, label)
+			}
+		case extra && key == "due":
+			due, err := parseDue(value)
+			if err != nil {
+				return models.Task{}, err
+			}
+
+			task.DueAt = &due
+		case extra && key == "id" && uuid.Validate(value) == nil:
+			task.ID = value
+		case extra && key == "assignee":
+			task.Assignee = value
+		case extra && key == "status":
+			task.Status = value
+		case extra && key == "pri" && isPriority("("+value+")"):
+			if !slices.Contains(task.Labels, word) {
+				task.Labels = append(task.Labels, word)
+			}
+		default:
+			title = append(title, strings.TrimPrefix(word, escapePrefix))
+		}
+	}
+
+	task.Title = strings.Join(title, " ")
+
+	if task.Title == "" {
+		return models.Task{}, fmt.Errorf("%w: no title", ErrInvalidLine)
+	}
+
+	return task, nil
+}
+
+// escapePrefix marks title words Parse would otherwise take for a field.
+const escapePrefix = `\`
+
+// Only the first word can be taken for a completion mark, priority or date.
+func escape(word string, first bool) string {
+	key, value, extra := strings.Cut(word, ":")
+
+	switch {
+	case strings.HasPrefix(word, escapePrefix),
+		len(word) > 1 && (word[0] == '+' || word[0] == '@'),
+		extra && value != "" && slices.Contains(extraKeys, key),
+		first && (word == "x" || isPriority(word)),
+		first && isDate(word):
+		return escapePrefix + word
+	default:
+		return word
+	}
+}
+
+var extraKeys = []string{"due", "id", "assignee", "status", "pri"}
+
+func splitPriority(labels []string) (string, []string) {
+	var (
+		priority string
+		others   []string
+	)
+
+	for _, label := range labels {
+		if value, ok := strings.CutPrefix(label, priorityLabel); ok && priority == "" && isPriority("("+value+")") {
+			priority = value
+			continue
+		}
+
+		others = append(others, label)
+	}
+
+	return priority, others
+}
+
+func isPriority(word string) bool {
+	return len(word) == 3 && word[0] == '(' && word[1] >= 'A' && word[1] <= 'Z' && word[2] == ')'
+}
+
+func isDate(word string) bool {
+	_, ok := parseDate([]string{word})
+	return ok
+}
+
+func parseDate(words []string) (time.Time, bool) {
+	if len(words) == 0 {
+		return time.Time{}, false
+	}
+
+	at, err := time.Parse(dateLayout, words[0])
+
+	return at, err == nil
+}
+
+// formatDue writes due dates at midnight UTC as dates, the todo.txt convention, and
+// others with their time.
+func formatDue(due time.Time) string {
+	due = due.UTC()
+
+	if due.Equal(due.Truncate(24 * time.Hour)) {
+		return due.Format(dateLayout)
+	}
+
+	return due.Format(time.RFC3339)
+}
+
+func parseDue(value string) (time.Time, error) {
+	for _, layout := range []string{dateLayout, time.RFC3339} {
+		if due, err := time.Parse(layout, value); err == nil {
+			return due.UTC(), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("%w: due:%s is not a date", ErrInvalidLine, value)
+}
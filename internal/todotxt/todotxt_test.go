@@ -0,0 +1,143 @@
+package todotxt
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"task-tracker/internal/models"
+)
+
+func TestRoundTrip(t *testing.T) {
+	created := time.Date(2025, time.April, 9, 18, 21, 41, 0, time.UTC)
+	updated := time.Date(2025, time.April, 12, 9, 0, 0, 0, time.UTC)
+	dueDate := time.Date(2025, time.April, 14, 0, 0, 0, 0, time.UTC)
+	dueTime := time.Date(2025, time.April, 14, 7, 30, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		task     models.Task
+		expected string
+	}{
+		"open with priority": {
+			task: models.Task{
+				ID: "0f8e3a6c-8d2b-4b55-9c7e-2b1f0a6d4e11", Title: "Call the bank about 10:30", Status: models.StatusTodo,
+				Assignee: "alice", DueAt: &dueDate, CreatedAt: created,
+				Labels: []string{"pri:A", "finance", "@phone"},
+			},
+			expected: "(A) 2025-04-09 Call the bank about 10:30 +finance @phone due:2025-04-14 assignee:alice id:0f8e3a6c-8d2b-4b55-9c7e-2b1f0a6d4e11",
+		},
+		"done with priority": {
+			task: models.Task{
+				Title: "Ship v1.2", Status: models.StatusDone, CreatedAt: created, UpdatedAt: updated,
+				Labels: []string{"pri:B"}, DueAt: &dueTime,
+			},
+			expected: "x 2025-04-12 2025-04-09 Ship v1.2 pri:B due:2025-04-14T07:30:00Z",
+		},
+		"canceled": {
+			task:     models.Task{Title: "Old idea", Status: models.StatusCanceled, UpdatedAt: updated, Labels: []string{}},
+			expected: "x 2025-04-12 Old idea status:canceled",
+		},
+		"in progress": {
+			task:     models.Task{Title: "Review", Status: "in_progress", Labels: []string{"pri:C", "pri:D", "+odd"}},
+			expected: "(C) Review +pri:D ++odd status:in_progress",
+		},
+		"title read as a completion mark": {
+			task:     models.Task{Title: "x marks the spot", Status: models.StatusTodo, Labels: []string{}},
+			expected: `\x marks the spot`,
+		},
+		"title read as a priority": {
+			task:     models.Task{Title: "(A) first", Status: models.StatusTodo, Labels: []string{}},
+			expected: `\(A) first`,
+		},
+		"title read as a date": {
+			task:     models.Task{Title: "2025-04-01 retro", Status: models.StatusDone, UpdatedAt: updated, Labels: []string{}},
+			expected: `x 2025-04-12 \2025-04-01 retro`,
+		},
+		"title with tokens": {
+			task: models.Task{
+				Title: `Email @bob about +1 due:friday status:blocked and \escapes`, Status: models.StatusTodo,
+				CreatedAt: created, Labels: []string{"mail"},
+			},
+			expected: `2025-04-09 Email \@bob about \+1 \due:friday \status:blocked and \\escapes +mail`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			line := Format(test.task)
+			if line != test.expected {
+				t.Fatalf("test-case: (%q); returned %q; expected %q", name, line, test.expected)
+			}
+
+			task, err := Parse(line)
+			if err != nil {
+				t.Fatalf("test-case: (%q); returned %v; expected no error", name, err)
+			}
+
+			// Only dates of the creation and completion times are kept.
+			expected := test.task
+			expected.CreatedAt = expected.CreatedAt.Truncate(24 * time.Hour)
+			expected.UpdatedAt = expected.UpdatedAt.Truncate(24 * time.Hour)
+
+			if !reflect.DeepEqual(task, expected) {
+				t.Fatalf("test-case: (%q); returned %+v; expected %+v", name, task, expected)
+			}
+
+			if again := Format(task); again != line {
+				t.Fatalf("test-case: (%q); returned %q; expected %q", name, again, line)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	due := time.Date(2025, time.April, 14, 0, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		line     string
+		expected models.Task
+		err      error
+	}{
+		"plain": {
+			line:     "Buy milk",
+			expected: models.Task{Title: "Buy milk", Status: models.StatusTodo, Labels: []string{}},
+		},
+		"words in any order": {
+			line: "@store Buy +groceries milk due:2025-04-14 rec:1w  t:2025-04-10 id:42",
+			expected: models.Task{
+				Title: "Buy milk rec:1w t:2025-04-10 id:42", Status: models.StatusTodo, DueAt: &due,
+				Labels: []string{"@store", "groceries"},
+			},
+		},
+		"priority only at the start": {
+			line:     "Plan (B) sprint",
+			expected: models.Task{Title: "Plan (B) sprint", Status: models.StatusTodo, Labels: []string{}},
+		},
+		"completed without dates": {
+			line:     "x Water plants",
+			expected: models.Task{Title: "Water plants", Status: models.StatusDone, Labels: []string{}},
+		},
+		"lowercase x in the title": {
+			line:     "xylophone lessons",
+			expected: models.Task{Title: "xylophone lessons", Status: models.StatusTodo, Labels: []string{}},
+		},
+		"no title":    {line: "(A) +home @phone", err: ErrInvalidLine},
+		"invalid due": {line: "Pay rent due:tomorrow", err: ErrInvalidLine},
+		"only a date": {line: "2025-04-09", err: ErrInvalidLine},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			task, err := Parse(test.line)
+
+			if !errors.Is(err, test.err) {
+				t.Fatalf("test-case: (%q); returned %v; expected %v", name, err, test.err)
+			}
+
+			if err == nil && !reflect.DeepEqual(task, test.expected) {
+				t.Fatalf("test-case: (%q); returned %+v; expected %+v", name, task, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package trackers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// githubIssue reads issues both as the REST API returns them and as `gh issue list
+// --json` prints them, which spells a few fields differently.
+type githubIssue struct {
+	Number        int             `json:"number"`
+	Title         string          `json:"title"`
+	Body          string          `json:"body"`
+	State         string          `json:"state"`
+	RepositoryURL string          `json:"repository_url"`
+	PullRequest   json.RawMessage `json:"pull_request"`
+	Labels        []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	Assignees []struct {
+		Login string `json:"login"`
+	} `json:"assignees"`
+	Milestone *struct {
+		DueOn   *time.Time `json:"due_on"`
+		GHDueOn *time.Time `json:"dueOn"`
+	} `json:"milestone"`
+	// Comments is a count in REST responses and a list with gh.
+	Comments json.RawMessage `json:"comments"`
+}
+
+type githubComment struct {
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"createdAt"`
+	Author    struct {
+		Login string `json:"login"`
+	} `json:"author"`
+}
+
+// Arrays back to back, as written by `gh api --paginate`, are read as one. Pull requests
+// are skipped and issues are due when their milestone is.
+func readGitHub(r io.Reader) ([]Item, error) {
+	decoder := json.NewDecoder(r)
+
+	var items []Item
+
+	for {
+		var page []githubIssue
+
+		err := decoder.Decode(&page)
+		if errors.Is(err, io.EOF) {
+			return items, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, issue := range page {
+			if len(issue.PullRequest) > 0 && string(issue.PullRequest) != "null" {
+				continue
+			}
+
+			item, err := issue.item()
+			if err != nil {
+				return nil, err
+			}
+
+			items = append(items, item)
+		}
+	}
+}
+
+func (issue githubIssue) item() (Item, error) {
+	item := Item{
+		Key:         "#" + strconv.Itoa(issue.Number),
+		Title:       issue.Title,
+		Description: issue.Body,
+		Status:      strings.ToLower(issue.State),
+	}
+
+	// REST issues name their repository, so issues of several repositories don't mix.
+	if repo, ok := strings.CutPrefix(issue.RepositoryURL, "https://api.github.com/repos/"); ok {
+		item.Key = repo + item.Key
+	}
+
+	for _, label := range issue.Labels {
+		item.Labels = append(item.Labels, label.Name)
+	}
+
+	for _, assignee := range issue.Assignees {
+		item.Assignees = append(item.Assignees, assignee.Login)
+	}
+
+	if issue.Milestone != nil {
+		item.DueAt = issue.Milestone.DueOn
+		if item.DueAt == nil {
+			item.DueAt = issue.Milestone.GHDueOn
+		}
+	}
+
+	if len(issue.Comments) > 0 && issue.Comments[0] == '[' {
+		var comments []githubComment
+
+		if err := json.Unmarshal(issue.Comments, &comments); err != nil {
+			return Item{}, err
+		}
+
+		for _, comment := range comments {
+			item.Comments = append(item.Comments, Comment{Author: comment.Author.Login, Body: comment.Body, At: comment.CreatedAt})
+		}
+	}
+
+	return item, nil
+}
@@ -0,0 +1,140 @@
+package trackers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// jiraTimeLayouts are the layouts Jira CSV exports write dates in, depending on the
+// instance's settings. Times without a zone are taken as UTC.
+var jiraTimeLayouts = []string{
+	"02/Jan/06 3:04 PM",
+	"02/Jan/06",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	time.RFC3339,
+}
+
+// Jira CSV exports repeat the Labels and Comment columns, one per value.
+func readJira(r io.Reader) ([]Item, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string][]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		columns[name] = append(columns[name], i)
+	}
+
+	for _, required := range []string{"summary", "issue key"} {
+		if len(columns[required]) == 0 {
+			return nil, fmt.Errorf("missing column %q", required)
+		}
+	}
+
+	var items []Item
+
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return items, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		values := func(name string) []string {
+			var values []string
+
+			for _, i := range columns[name] {
+				if i < len(record) && strings.TrimSpace(record[i]) != "" {
+					values = append(values, record[i])
+				}
+			}
+
+			return values
+		}
+
+		value := func(name string) string {
+			if values := values(name); len(values) > 0 {
+				return values[0]
+			}
+
+			return ""
+		}
+
+		item := Item{
+			Key:         strings.TrimSpace(value("issue key")),
+			Title:       value("summary"),
+			Description: value("description"),
+			Status:      value("status"),
+			Labels:      values("labels"),
+		}
+
+		if assignee := strings.TrimSpace(value("assignee")); assignee != "" {
+			item.Assignees = []string{assignee}
+		}
+
+		due := value("due date")
+		if due == "" {
+			due = value("due")
+		}
+
+		if due != "" {
+			at, err := parseJiraTime(due)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: due date: %w", line, err)
+			}
+
+			item.DueAt = &at
+		}
+
+		for _, value := range values("comment") {
+			comment, err := parseJiraComment(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: comment: %w", line, err)
+			}
+
+			item.Comments = append(item.Comments, comment)
+		}
+
+		items = append(items, item)
+	}
+}
+
+func parseJiraTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+
+	for _, layout := range jiraTimeLayouts {
+		if at, err := time.Parse(layout, value); err == nil {
+			return at, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unknown date format %q", value)
+}
+
+// Comment cells are "<date>;<author>;<body>", the body may contain semicolons itself.
+func parseJiraComment(value string) (Comment, error) {
+	parts := strings.SplitN(value, ";", 3)
+	if len(parts) != 3 {
+		return Comment{}, errors.New(`expected "date;author;body"`)
+	}
+
+	at, err := parseJiraTime(parts[0])
+	if err != nil {
+		return Comment{}, err
+	}
+
+	return Comment{Author: strings.TrimSpace(parts[1]), Body: parts[2], At: at}, nil
+}
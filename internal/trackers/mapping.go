@@ -0,0 +1,183 @@
+package trackers
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+
+	"task-tracker/internal/models"
+)
+
+// idSpace derives task IDs from item keys, so importing an item again updates the
+// task it was imported as.
+var idSpace = uuid.MustParse("5c6f2a0e-1d4b-4f8e-9a3c-7b2e8d1f0c64")
+
+// Mapping files are YAML:
+//
+//	statuses:             # item status to task status, matched case-insensitively
+//	  backlog: todo
+//	  "code review": in_progress
+//	default_status: todo  # for other statuses, which are otherwise snake_cased
+//	labels:               # item label to task label, an empty one drops the label
+//	  bug: defect
+//	  wontfix: ""
+//	assignees:            # tracker user to assignee, others are kept as they are
+//	  octocat: alice
+//	comments: false       # whether comments are appended to descriptions
+//	namespace: acme-web   # tells apart items of several boards with the same keys
+type Mapping struct {
+	// Statuses are keyed by lowercase item status.
+	Statuses      map[string]string
+	DefaultStatus string
+	Labels        map[string]string
+	Assignees     map[string]string
+	Comments      bool
+	Namespace     string
+}
+
+func DefaultMapping(source Source) Mapping {
+	mapping := Mapping{Comments: true}
+
+	switch source {
+	case Trello:
+		mapping.Statuses = map[string]string{"to do": models.StatusTodo, "doing": "in_progress", "done": models.StatusDone}
+	case Jira:
+		mapping.Statuses = map[string]string{"to do": models.StatusTodo, "open": models.StatusTodo, "done": models.StatusDone,
+			"closed": models.StatusDone, "resolved": models.StatusDone, "won't do": models.StatusCanceled}
+	case GitHub:
+		mapping.Statuses = map[string]string{"open": models.StatusTodo, "closed": models.StatusDone}
+	}
+
+	return mapping
+}
+
+func LoadMapping(path string, source Source) (Mapping, error) {
+	mapping := DefaultMapping(source)
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Mapping{}, err
+	}
+
+	// Comments is a pointer to tell an omitted setting from a false one.
+	var file struct {
+		Statuses      map[string]string `yaml:"statuses"`
+		DefaultStatus string            `yaml:"default_status"`
+		Labels        map[string]string `yaml:"labels"`
+		Assignees     map[string]string `yaml:"assignees"`
+		Comments      *bool             `yaml:"comments"`
+		Namespace     string            `yaml:"namespace"`
+	}
+
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return Mapping{}, fmt.Errorf("mapping %s: %w", path, err)
+	}
+
+	if mapping.Statuses == nil {
+		mapping.Statuses = make(map[string]string, len(file.Statuses))
+	}
+
+	for from, to := range file.Statuses {
+		mapping.Statuses[strings.ToLower(from)] = to
+	}
+
+	if file.Comments != nil {
+		mapping.Comments = *file.Comments
+	}
+
+	mapping.DefaultStatus = file.DefaultStatus
+	mapping.Labels = file.Labels
+	mapping.Assignees = file.Assignees
+	mapping.Namespace = file.Namespace
+
+	return mapping, nil
+}
+
+// Task IDs are unique across workspaces, so the same export gets other IDs in each.
+func (m Mapping) ID(source Source, workspace string, item Item) string {
+	return uuid.NewSHA1(idSpace, []byte(string(source)+":"+workspace+":"+m.Namespace+":"+item.Key)).String()
+}
+
+// Descriptions end with a reference to the item, so none are empty.
+func (m Mapping) Row(source Source, workspace string, item Item) models.ImportTaskRequest {
+	row := models.ImportTaskRequest{
+		ID: m.ID(source, workspace, item),
+		CreateTaskRequest: models.CreateTaskRequest{
+			Title:       strings.TrimSpace(item.Title),
+			Description: m.description(source, item),
+			Status:      m.status(item.Status),
+			DueAt:       item.DueAt,
+			Labels:      []string{},
+		},
+	}
+
+	if len(item.Assignees) > 0 {
+		row.Assignee = item.Assignees[0]
+
+		if assignee, ok := m.Assignees[row.Assignee]; ok {
+			row.Assignee = assignee
+		}
+	}
+
+	for _, label := range item.Labels {
+		label = strings.TrimSpace(label)
+
+		if mapped, ok := m.Labels[label]; ok {
+			label = mapped
+		}
+
+		if label != "" && !slices.Contains(row.Labels, label) {
+			row.Labels = append(row.Labels, label)
+		}
+	}
+
+	return row
+}
+
+func (m Mapping) status(status string) string {
+	if mapped, ok := m.Statuses[strings.ToLower(strings.TrimSpace(status))]; ok {
+		return mapped
+	}
+
+	if m.DefaultStatus != "" {
+		return m.DefaultStatus
+	}
+
+	return strings.Join(strings.Fields(strings.ToLower(status)), "_")
+}
+
+func (m Mapping) description(source Source, item Item) string {
+	var b strings.Builder
+
+	if description := strings.TrimSpace(item.Description); description != "" {
+		b.WriteString(description)
+		b.WriteString("\n\n")
+	}
+
+	if m.Comments {
+		for _, comment := range item.Comments {
+			fmt.Fprintf(&b, "%s on %s:\n%s\n\n", comment.Author, comment.At.UTC().Format("2006-01-02 15:04"), strings.TrimSpace(comment.Body))
+		}
+	}
+
+	fmt.Fprintf(&b, "Imported from %s %s.", source.title(), item.Key)
+
+	return b.String()
+}
+
+func (s Source) title() string {
+	switch s {
+	case Trello:
+		return "Trello card"
+	case Jira:
+		return "Jira issue"
+	case GitHub:
+		return "GitHub issue"
+	default:
+		return string(s)
+	}
+}
@@ -0,0 +1,102 @@
+package trackers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"task-tracker/internal/models"
+)
+
+func TestMapping_Row(t *testing.T) {
+	loaded, err := LoadMapping("testdata/mapping.yaml", GitHub)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	item := Item{
+		Key:         "acme/web#7",
+		Title:       " Crash on empty config ",
+		Description: "Starting without a config file panics.",
+		Status:      "In Progress",
+		Assignees:   []string{"octocat", "hubot"},
+		Labels:      []string{"bug", "wontfix", "defect", "p1"},
+		DueAt:       date("2025-05-01T07:00:00Z"),
+		Comments:    []Comment{{Author: "hubot", Body: "Confirmed.\n", At: time.Date(2025, time.April, 9, 18, 21, 0, 0, time.UTC)}},
+	}
+
+	tests := map[string]struct {
+		mapping  Mapping
+		item     Item
+		expected models.CreateTaskRequest
+	}{
+		"default mapping": {
+			mapping: DefaultMapping(GitHub),
+			item:    item,
+			expected: models.CreateTaskRequest{
+				Title:       "Crash on empty config",
+				Description: "Starting without a config file panics.\n\nhubot on 2025-04-09 18:21:\nConfirmed.\n\nImported from GitHub issue acme/web#7.",
+				Status:      "in_progress",
+				Assignee:    "octocat",
+				DueAt:       item.DueAt,
+				Labels:      []string{"bug", "wontfix", "defect", "p1"},
+			},
+		},
+		"mapping file": {
+			mapping: loaded,
+			item:    item,
+			expected: models.CreateTaskRequest{
+				Title:       "Crash on empty config",
+				Description: "Starting without a config file panics.\n\nImported from GitHub issue acme/web#7.",
+				Status:      "doing",
+				Assignee:    "alice",
+				DueAt:       item.DueAt,
+				Labels:      []string{"defect", "p1"},
+			},
+		},
+		"default status": {
+			mapping: Mapping{DefaultStatus: "todo"},
+			item:    Item{Key: "#1", Title: "t", Status: "Blocked"},
+			expected: models.CreateTaskRequest{
+				Title: "t", Description: "Imported from GitHub issue #1.", Status: "todo", Labels: []string{},
+			},
+		},
+		"kept status": {
+			mapping: DefaultMapping(GitHub),
+			item:    Item{Key: "#1", Title: "t", Status: "closed"},
+			expected: models.CreateTaskRequest{
+				Title: "t", Description: "Imported from GitHub issue #1.", Status: "done", Labels: []string{},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			row := test.mapping.Row(GitHub, "default", test.item)
+
+			if !reflect.DeepEqual(row.CreateTaskRequest, test.expected) {
+				t.Fatalf("test-case: (%q); returned %+v; expected %+v", name, row.CreateTaskRequest, test.expected)
+			}
+
+			if row.ID != test.mapping.ID(GitHub, "default", test.item) {
+				t.Fatalf("test-case: (%q); returned %v; expected a stable id", name, row.ID)
+			}
+		})
+	}
+}
+
+func TestMapping_ID(t *testing.T) {
+	item := Item{Key: "#7"}
+	id := DefaultMapping(GitHub).ID(GitHub, "default", item)
+
+	for name, other := range map[string]string{
+		"other source":    DefaultMapping(Jira).ID(Jira, "default", item),
+		"other workspace": DefaultMapping(GitHub).ID(GitHub, "team-a", item),
+		"other namespace": Mapping{Namespace: "acme"}.ID(GitHub, "default", item),
+		"other key":       DefaultMapping(GitHub).ID(GitHub, "default", Item{Key: "#8"}),
+	} {
+		if other == id {
+			t.Fatalf("test-case: (%q); returned %v; expected another id", name, other)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package trackers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+type Source string
+
+const (
+	Trello Source = "trello"
+	Jira   Source = "jira"
+	GitHub Source = "github"
+)
+
+func ParseSource(name string) (Source, error) {
+	switch Source(name) {
+	case Trello, Jira, GitHub:
+		return Source(name), nil
+	default:
+		return "", fmt.Errorf("unknown source %q, expected trello, jira or github", name)
+	}
+}
+
+var ErrMalformed = errors.New("malformed export")
+
+type Item struct {
+	// Key stays the same across exports.
+	Key         string
+	Title       string
+	Description string
+	// Status is the Trello list, Jira status or GitHub state of the item.
+	Status    string
+	Assignees []string
+	Labels    []string
+	DueAt     *time.Time
+	Comments  []Comment
+}
+
+type Comment struct {
+	Author string
+	Body   string
+	At     time.Time
+}
+
+func Read(r io.Reader, source Source) ([]Item, error) {
+	var (
+		items []Item
+		err   error
+	)
+
+	switch source {
+	case Trello:
+		items, err = readTrello(r)
+	case Jira:
+		items, err = readJira(r)
+	case GitHub:
+		items, err = readGitHub(r)
+	default:
+		return nil, fmt.Errorf("unknown source %q", source)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrMalformed, source, err)
+	}
+
+	return items, nil
+}
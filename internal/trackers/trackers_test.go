@@ -0,0 +1,115 @@
+package trackers
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func date(value string) *time.Time {
+	t, _ := time.Parse(time.RFC3339, value)
+	return &t
+}
+
+func TestRead(t *testing.T) {
+	tests := map[string]struct {
+		source   Source
+		file     string
+		expected []Item
+	}{
+		"trello board": {
+			source: Trello,
+			file:   "testdata/trello.json",
+			expected: []Item{
+				{
+					Key: "c1", Title: "Write the announcement", Description: "Blog post and newsletter.", Status: "To Do",
+					Assignees: []string{"bob", "alice"}, Labels: []string{"marketing", "red"}, DueAt: date("2025-04-14T07:00:00Z"),
+					Comments: []Comment{
+						{Author: "alice", Body: "Who takes this?", At: *date("2025-04-09T08:00:00Z")},
+						{Author: "bob", Body: "Draft is ready.", At: *date("2025-04-10T09:00:00Z")},
+					},
+				},
+				{Key: "c2", Title: "Review the pricing page", Status: "Code Review"},
+			},
+		},
+		"jira csv": {
+			source: Jira,
+			file:   "testdata/jira.csv",
+			expected: []Item{
+				{
+					Key: "WEB-12", Title: "Fix login redirect", Description: "Users land on /home\ninstead of the page they asked for.",
+					Status: "In Progress", Assignees: []string{"Alice Smith"}, Labels: []string{"auth", "regression"},
+					DueAt: date("2025-04-14T00:00:00Z"),
+					Comments: []Comment{
+						{Author: "5b10ac", Body: "Reproduced on staging; happens with SSO too.", At: *date("2025-04-02T10:00:00Z")},
+					},
+				},
+				{Key: "WEB-13", Title: "Update dependencies", Status: "Done"},
+			},
+		},
+		"github rest pages": {
+			source: GitHub,
+			file:   "testdata/github.json",
+			expected: []Item{
+				{
+					Key: "acme/web#7", Title: "Crash on empty config", Description: "Starting without a config file panics.",
+					Status: "open", Assignees: []string{"octocat"}, Labels: []string{"bug", "wontfix"}, DueAt: date("2025-05-01T07:00:00Z"),
+				},
+				{Key: "acme/web#9", Title: "Document the import command", Status: "closed", Labels: []string{"docs"}},
+			},
+		},
+		"github cli": {
+			source: GitHub,
+			file:   "testdata/gh.json",
+			expected: []Item{
+				{
+					Key: "#3", Title: "Flaky test", Description: "TestLogin fails now and then.", Status: "open",
+					Assignees: []string{"hubot"}, Labels: []string{"ci"}, DueAt: date("2025-04-18T00:00:00Z"),
+					Comments: []Comment{{Author: "octocat", Body: "Seen it twice today.", At: *date("2025-04-11T16:45:00Z")}},
+				},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			file, err := os.Open(test.file)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer file.Close()
+
+			items, err := Read(file, test.source)
+			if err != nil {
+				t.Fatalf("test-case: (%q); returned %v; expected no error", name, err)
+			}
+
+			if !reflect.DeepEqual(items, test.expected) {
+				t.Fatalf("test-case: (%q); returned %+v; expected %+v", name, items, test.expected)
+			}
+		})
+	}
+}
+
+func TestRead_Malformed(t *testing.T) {
+	tests := map[string]struct {
+		source Source
+		input  string
+	}{
+		"trello not json":      {Trello, "<html>"},
+		"jira without summary": {Jira, "Issue key,Status\nWEB-1,Done\n"},
+		"jira bad due date":    {Jira, "Summary,Issue key,Due Date\nFix,WEB-1,next week\n"},
+		"jira bad comment":     {Jira, "Summary,Issue key,Comment\nFix,WEB-1,just text\n"},
+		"github not an array":  {GitHub, `{"number": 1}`},
+		"github bad comments":  {GitHub, `[{"number": 1, "comments": [1]}]`},
+	}
+
+	for name, test := range tests {
+		if _, err := Read(strings.NewReader(test.input), test.source); !errors.Is(err, ErrMalformed) {
+			t.Fatalf("test-case: (%q); returned %v; expected %v", name, err, ErrMalformed)
+		}
+	}
+}
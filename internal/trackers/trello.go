@@ -0,0 +1,109 @@
+package trackers
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// Trello exports boards from the board menu's "Print, export and share" > "Export as JSON".
+type trelloBoard struct {
+	Lists []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"lists"`
+	Cards []struct {
+		ID        string     `json:"id"`
+		Name      string     `json:"name"`
+		Desc      string     `json:"desc"`
+		IDList    string     `json:"idList"`
+		Due       *time.Time `json:"due"`
+		IDMembers []string   `json:"idMembers"`
+		Labels    []struct {
+			Name  string `json:"name"`
+			Color string `json:"color"`
+		} `json:"labels"`
+	} `json:"cards"`
+	Members []struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+	} `json:"members"`
+	Actions []struct {
+		Type string    `json:"type"`
+		Date time.Time `json:"date"`
+		Data struct {
+			Text string `json:"text"`
+			Card struct {
+				ID string `json:"id"`
+			} `json:"card"`
+		} `json:"data"`
+		MemberCreator struct {
+			Username string `json:"username"`
+		} `json:"memberCreator"`
+	} `json:"actions"`
+}
+
+// Labels without a name go by their color.
+func readTrello(r io.Reader) ([]Item, error) {
+	var board trelloBoard
+
+	if err := json.NewDecoder(r).Decode(&board); err != nil {
+		return nil, err
+	}
+
+	lists := make(map[string]string, len(board.Lists))
+	for _, list := range board.Lists {
+		lists[list.ID] = list.Name
+	}
+
+	members := make(map[string]string, len(board.Members))
+	for _, member := range board.Members {
+		members[member.ID] = member.Username
+	}
+
+	// Exports list actions newest first.
+	comments := make(map[string][]Comment)
+	for i := len(board.Actions) - 1; i >= 0; i-- {
+		action := board.Actions[i]
+
+		if action.Type == "commentCard" {
+			comments[action.Data.Card.ID] = append(comments[action.Data.Card.ID], Comment{
+				Author: action.MemberCreator.Username,
+				Body:   action.Data.Text,
+				At:     action.Date,
+			})
+		}
+	}
+
+	items := make([]Item, 0, len(board.Cards))
+
+	for _, card := range board.Cards {
+		item := Item{
+			Key:         card.ID,
+			Title:       card.Name,
+			Description: card.Desc,
+			Status:      lists[card.IDList],
+			DueAt:       card.Due,
+			Comments:    comments[card.ID],
+		}
+
+		for _, id := range card.IDMembers {
+			if username, ok := members[id]; ok {
+				item.Assignees = append(item.Assignees, username)
+			}
+		}
+
+		for _, label := range card.Labels {
+			name := label.Name
+			if name == "" {
+				name = label.Color
+			}
+
+			item.Labels = append(item.Labels, name)
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
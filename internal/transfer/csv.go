@@ -0,0 +1,200 @@
+package transfer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"task-tracker/internal/models"
+)
+
+// Imports match columns by name in any order and ignore read-only and unknown ones.
+var Columns = []string{
+	"id", "title", "description", "status", "assignee", "due_at", "rrule", "time_zone",
+	"reminders", "labels", "created_by", "created_at", "updated_at",
+}
+
+// listSeparator joins reminders and labels in a cell. Labels can't contain it.
+const listSeparator = ","
+
+type csvEncoder struct {
+	w       *csv.Writer
+	started bool
+}
+
+func newCSVEncoder(w io.Writer) *csvEncoder {
+	return &csvEncoder{w: csv.NewWriter(w)}
+}
+
+func (e *csvEncoder) Encode(task models.Task) error {
+	if err := e.header(); err != nil {
+		return err
+	}
+
+	reminders := make([]string, len(task.Reminders))
+	for i, before := range task.Reminders {
+		reminders[i] = time.Duration(before).String()
+	}
+
+	return e.w.Write([]string{
+		task.ID,
+		task.Title,
+		task.Description,
+		task.Status,
+		task.Assignee,
+		formatTime(task.DueAt),
+		task.RRule,
+		task.TimeZone,
+		strings.Join(reminders, listSeparator),
+		strings.Join(task.Labels, listSeparator),
+		task.CreatedBy,
+		formatTime(&task.CreatedAt),
+		formatTime(&task.UpdatedAt),
+	})
+}
+
+func (e *csvEncoder) Close() error {
+	if err := e.header(); err != nil {
+		return err
+	}
+
+	e.w.Flush()
+
+	return e.w.Error()
+}
+
+func (e *csvEncoder) header() error {
+	if e.started {
+		return nil
+	}
+
+	e.started = true
+
+	return e.w.Write(Columns)
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// Rows with malformed quotes or values are invalid rows, a header without a title
+// column ends the input.
+type csvDecoder struct {
+	r       *csv.Reader
+	columns map[string]int
+	started bool
+}
+
+func newCSVDecoder(r io.Reader) *csvDecoder {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	return &csvDecoder{r: reader}
+}
+
+func (d *csvDecoder) Next() (models.ImportTaskRequest, error) {
+	if !d.started {
+		d.started = true
+
+		header, err := d.r.Read()
+		if err != nil {
+			return models.ImportTaskRequest{}, err
+		}
+
+		d.columns = make(map[string]int, len(header))
+		for i, name := range header {
+			d.columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+		}
+
+		if _, ok := d.columns["title"]; !ok {
+			d.columns = nil
+			return models.ImportTaskRequest{}, errors.New("csv imports need a header with a title column")
+		}
+	}
+
+	if d.columns == nil {
+		return models.ImportTaskRequest{}, io.EOF
+	}
+
+	record, err := d.r.Read()
+	if err != nil {
+		var parseError *csv.ParseError
+		if errors.As(err, &parseError) {
+			return models.ImportTaskRequest{}, fmt.Errorf("%w: %v", ErrInvalidRow, err)
+		}
+
+		return models.ImportTaskRequest{}, err
+	}
+
+	return d.row(record)
+}
+
+func (d *csvDecoder) row(record []string) (models.ImportTaskRequest, error) {
+	cell := func(name string) string {
+		if i, ok := d.columns[name]; ok && i < len(record) {
+			return record[i]
+		}
+
+		return ""
+	}
+
+	row := models.ImportTaskRequest{
+		ID: strings.TrimSpace(cell("id")),
+		CreateTaskRequest: models.CreateTaskRequest{
+			Title:       cell("title"),
+			Description: cell("description"),
+			Status:      cell("status"),
+			Assignee:    cell("assignee"),
+			RRule:       cell("rrule"),
+			TimeZone:    cell("time_zone"),
+		},
+	}
+
+	// Present list columns replace the current lists on update, even when empty.
+	if _, ok := d.columns["labels"]; ok {
+		row.Labels = append([]string{}, splitList(cell("labels"))...)
+	}
+
+	if value := strings.TrimSpace(cell("due_at")); value != "" {
+		due, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return models.ImportTaskRequest{}, fmt.Errorf("%w: due_at must be an RFC 3339 time", ErrInvalidRow)
+		}
+
+		row.DueAt = &due
+	}
+
+	if _, ok := d.columns["reminders"]; ok {
+		row.Reminders = []models.Duration{}
+	}
+
+	for _, value := range splitList(cell("reminders")) {
+		before, err := time.ParseDuration(value)
+		if err != nil {
+			return models.ImportTaskRequest{}, fmt.Errorf("%w: reminders must be Go durations", ErrInvalidRow)
+		}
+
+		row.Reminders = append(row.Reminders, models.Duration(before))
+	}
+
+	return row, nil
+}
+
+func splitList(value string) []string {
+	var values []string
+
+	for _, item := range strings.Split(value, listSeparator) {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+
+	return values
+}
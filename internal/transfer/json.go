@@ -0,0 +1,161 @@
+package transfer
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"task-tracker/internal/models"
+)
+
+// maxLineSize bounds NDJSON rows, which are read a line at a time.
+const maxLineSize = 1 << 20
+
+type jsonEncoder struct {
+	w       io.Writer
+	started bool
+}
+
+func newJSONEncoder(w io.Writer) *jsonEncoder {
+	return &jsonEncoder{w: w}
+}
+
+func (e *jsonEncoder) Encode(task models.Task) error {
+	data, err := json.Marshal(task)
+	if err != nil {
+		return err
+	}
+
+	separator := ","
+	if !e.started {
+		separator = "["
+		e.started = true
+	}
+
+	_, err = fmt.Fprintf(e.w, "%s\n%s", separator, data)
+
+	return err
+}
+
+func (e *jsonEncoder) Close() error {
+	closing := "\n]\n"
+	if !e.started {
+		closing = "[]\n"
+	}
+
+	_, err := io.WriteString(e.w, closing)
+
+	return err
+}
+
+type ndjsonEncoder struct {
+	enc *json.Encoder
+}
+
+func newNDJSONEncoder(w io.Writer) *ndjsonEncoder {
+	return &ndjsonEncoder{enc: json.NewEncoder(w)}
+}
+
+func (e *ndjsonEncoder) Encode(task models.Task) error {
+	return e.enc.Encode(task)
+}
+
+func (e *ndjsonEncoder) Close() error {
+	return nil
+}
+
+// Elements that aren't valid tasks are invalid rows, malformed JSON ends the input.
+type jsonDecoder struct {
+	dec     *json.Decoder
+	started bool
+	done    bool
+}
+
+func newJSONDecoder(r io.Reader) *jsonDecoder {
+	return &jsonDecoder{dec: json.NewDecoder(r)}
+}
+
+func (d *jsonDecoder) Next() (models.ImportTaskRequest, error) {
+	if d.done {
+		return models.ImportTaskRequest{}, io.EOF
+	}
+
+	if !d.started {
+		d.started = true
+
+		if token, err := d.dec.Token(); err != nil || token != json.Delim('[') {
+			d.done = true
+			return models.ImportTaskRequest{}, errors.New("json imports must be an array of tasks")
+		}
+	}
+
+	if !d.dec.More() {
+		d.done = true
+
+		if _, err := d.dec.Token(); err != nil {
+			return models.ImportTaskRequest{}, err
+		}
+
+		return models.ImportTaskRequest{}, io.EOF
+	}
+
+	var row models.ImportTaskRequest
+
+	if err := d.dec.Decode(&row); err != nil {
+		var syntaxError *json.SyntaxError
+		if errors.As(err, &syntaxError) || errors.Is(err, io.ErrUnexpectedEOF) {
+			d.done = true
+			return models.ImportTaskRequest{}, err
+		}
+
+		return models.ImportTaskRequest{}, fmt.Errorf("%w: %v", ErrInvalidRow, err)
+	}
+
+	return row, nil
+}
+
+type ndjsonDecoder struct {
+	scanner *bufio.Scanner
+}
+
+func newNDJSONDecoder(r io.Reader) *ndjsonDecoder {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	return &ndjsonDecoder{scanner: scanner}
+}
+
+func (d *ndjsonDecoder) Next() (models.ImportTaskRequest, error) {
+	for d.scanner.Scan() {
+		line := d.scanner.Bytes()
+		if len(line) == 0 || isBlank(line) {
+			continue
+		}
+
+		var row models.ImportTaskRequest
+
+		if err := json.Unmarshal(line, &row); err != nil {
+			return models.ImportTaskRequest{}, fmt.Errorf("%w: %v", ErrInvalidRow, err)
+		}
+
+		return row, nil
+	}
+
+	if err := d.scanner.Err(); err != nil {
+		return models.ImportTaskRequest{}, err
+	}
+
+	return models.ImportTaskRequest{}, io.EOF
+}
+
+func isBlank(line []byte) bool {
+	for _, b := range line {
+		if b != ' ' && b != '\t' && b != '\r' {
+			return false
+		}
+	}
+
+	return true
+}
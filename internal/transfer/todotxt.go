@@ -0,0 +1,70 @@
+package transfer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"task-tracker/internal/models"
+	"task-tracker/internal/todotxt"
+)
+
+type todoTxtEncoder struct {
+	w io.Writer
+}
+
+func newTodoTxtEncoder(w io.Writer) *todoTxtEncoder {
+	return &todoTxtEncoder{w: w}
+}
+
+func (e *todoTxtEncoder) Encode(task models.Task) error {
+	_, err := io.WriteString(e.w, todotxt.Format(task)+"\n")
+	return err
+}
+
+func (e *todoTxtEncoder) Close() error {
+	return nil
+}
+
+// Lines have no description, so rows come without one.
+type todoTxtDecoder struct {
+	scanner *bufio.Scanner
+}
+
+func newTodoTxtDecoder(r io.Reader) *todoTxtDecoder {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	return &todoTxtDecoder{scanner: scanner}
+}
+
+func (d *todoTxtDecoder) Next() (models.ImportTaskRequest, error) {
+	for d.scanner.Scan() {
+		line := d.scanner.Text()
+		if isBlank([]byte(line)) {
+			continue
+		}
+
+		task, err := todotxt.Parse(line)
+		if err != nil {
+			return models.ImportTaskRequest{}, fmt.Errorf("%w: %v", ErrInvalidRow, err)
+		}
+
+		return models.ImportTaskRequest{
+			ID: task.ID,
+			CreateTaskRequest: models.CreateTaskRequest{
+				Title:    task.Title,
+				Status:   task.Status,
+				Assignee: task.Assignee,
+				DueAt:    task.DueAt,
+				Labels:   task.Labels,
+			},
+		}, nil
+	}
+
+	if err := d.scanner.Err(); err != nil {
+		return models.ImportTaskRequest{}, err
+	}
+
+	return models.ImportTaskRequest{}, io.EOF
+}
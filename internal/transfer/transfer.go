@@ -0,0 +1,108 @@
+package transfer
+
+import (
+	"errors"
+	"io"
+	"mime"
+
+	"task-tracker/internal/models"
+)
+
+type Format string
+
+const (
+	CSV    Format = "csv"
+	JSON   Format = "json"
+	NDJSON Format = "ndjson"
+	// TodoTxt rows have no description, see todotxt.Format.
+	TodoTxt Format = "todotxt"
+)
+
+// ErrInvalidRow is wrapped by decoding errors that only concern one row, after which
+// the next row can be read.
+var ErrInvalidRow = errors.New("invalid row")
+
+// An empty format name is JSON.
+func ParseFormat(name string) (Format, error) {
+	switch Format(name) {
+	case "", JSON:
+		return JSON, nil
+	case CSV, NDJSON, TodoTxt:
+		return Format(name), nil
+	default:
+		return "", models.ErrInvalidFormat
+	}
+}
+
+// Unknown media types are read as JSON. text/plain isn't taken for todo.txt, as any text
+// could be sent with it, todo.txt is asked for by name.
+func FormatForContentType(contentType string) Format {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	switch mediaType {
+	case "text/csv":
+		return CSV
+	case "application/x-ndjson", "application/jsonl":
+		return NDJSON
+	default:
+		return JSON
+	}
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case CSV:
+		return "text/csv; charset=utf-8"
+	case NDJSON:
+		return "application/x-ndjson"
+	case TodoTxt:
+		return "text/plain; charset=utf-8"
+	default:
+		return "application/json"
+	}
+}
+
+func (f Format) Extension() string {
+	if f == TodoTxt {
+		return "txt"
+	}
+
+	return string(f)
+}
+
+type Encoder interface {
+	Encode(task models.Task) error
+	// An export without tasks is complete once closed.
+	Close() error
+}
+
+func NewEncoder(w io.Writer, format Format) Encoder {
+	switch format {
+	case CSV:
+		return newCSVEncoder(w)
+	case NDJSON:
+		return newNDJSONEncoder(w)
+	case TodoTxt:
+		return newTodoTxtEncoder(w)
+	default:
+		return newJSONEncoder(w)
+	}
+}
+
+type Decoder interface {
+	// After errors wrapping ErrInvalidRow reading can go on, other errors end the input.
+	Next() (models.ImportTaskRequest, error)
+}
+
+func NewDecoder(r io.Reader, format Format) Decoder {
+	switch format {
+	case CSV:
+		return newCSVDecoder(r)
+	case NDJSON:
+		return newNDJSONDecoder(r)
+	case TodoTxt:
+		return newTodoTxtDecoder(r)
+	default:
+		return newJSONDecoder(r)
+	}
+}
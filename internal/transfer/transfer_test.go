@@ -0,0 +1,180 @@
+package transfer
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"task-tracker/internal/models"
+)
+
+func TestRoundTrip(t *testing.T) {
+	due := time.Date(2025, time.April, 14, 7, 0, 0, 0, time.UTC)
+
+	tasks := []models.Task{
+		{
+			ID:          "0f8e3a6c-8d2b-4b55-9c7e-2b1f0a6d4e11",
+			Title:       "deploy, then \"verify\"",
+			Description: "two\nlines",
+			Status:      "todo",
+			Assignee:    "alice",
+			DueAt:       &due,
+			Reminders:   []models.Duration{models.Duration(time.Hour)},
+			Labels:      []string{"ops", "urgent"},
+		},
+		{ID: "6b1d2c3e-4f5a-4b6c-8d7e-9f0a1b2c3d4e", Title: "review", Description: "d", Status: "done"},
+	}
+
+	expected := []models.ImportTaskRequest{
+		{
+			ID: tasks[0].ID,
+			CreateTaskRequest: models.CreateTaskRequest{
+				Title: tasks[0].Title, Description: tasks[0].Description, Status: "todo", Assignee: "alice",
+				DueAt: &due, Reminders: tasks[0].Reminders, Labels: tasks[0].Labels,
+			},
+		},
+		{ID: tasks[1].ID, CreateTaskRequest: models.CreateTaskRequest{Title: "review", Description: "d", Status: "done"}},
+	}
+
+	for _, format := range []Format{CSV, JSON, NDJSON} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+
+			encoder := NewEncoder(&buf, format)
+			for _, task := range tasks {
+				if err := encoder.Encode(task); err != nil {
+					t.Fatalf("test-case: (%q); returned %v; expected no error", format, err)
+				}
+			}
+
+			if err := encoder.Close(); err != nil {
+				t.Fatalf("test-case: (%q); returned %v; expected no error", format, err)
+			}
+
+			rows, err := readAll(NewDecoder(&buf, format))
+			if err != nil {
+				t.Fatalf("test-case: (%q); returned %v; expected no error", format, err)
+			}
+
+			// Empty list cells of CSV rows decode as empty lists.
+			if format == CSV {
+				expected[1].Reminders, expected[1].Labels = []models.Duration{}, []string{}
+			} else {
+				expected[1].Reminders, expected[1].Labels = nil, nil
+			}
+
+			if !reflect.DeepEqual(rows, expected) {
+				t.Fatalf("test-case: (%q); returned %+v; expected %+v", format, rows, expected)
+			}
+		})
+	}
+}
+
+func TestEmptyExport(t *testing.T) {
+	tests := map[Format]string{
+		CSV:     strings.Join(Columns, ",") + "\n",
+		JSON:    "[]\n",
+		NDJSON:  "",
+		TodoTxt: "",
+	}
+
+	for format, expected := range tests {
+		var buf bytes.Buffer
+
+		if err := NewEncoder(&buf, format).Close(); err != nil || buf.String() != expected {
+			t.Fatalf("test-case: (%q); returned %q, %v; expected %q", format, buf.String(), err, expected)
+		}
+	}
+}
+
+func TestDecoderErrors(t *testing.T) {
+	tests := map[string]struct {
+		format   Format
+		input    string
+		expected []string
+	}{
+		"json invalid row": {
+			format:   JSON,
+			input:    `[{"title":"a"}, {"title":1}, {"title":"c"}]`,
+			expected: []string{"ok", "invalid", "ok"},
+		},
+		"json malformed": {
+			format:   JSON,
+			input:    `[{"title":"a"}, {"title":`,
+			expected: []string{"ok", "fatal"},
+		},
+		"json not an array": {
+			format:   JSON,
+			input:    `{"title":"a"}`,
+			expected: []string{"fatal"},
+		},
+		"ndjson invalid row": {
+			format:   NDJSON,
+			input:    "{\"title\":\"a\"}\n\n{\"title\"\n{\"title\":\"c\"}\n",
+			expected: []string{"ok", "invalid", "ok"},
+		},
+		"csv invalid cells": {
+			format:   CSV,
+			input:    "title,due_at,reminders\na,,\nb,tomorrow,\nc,,soon\nd,,1h\n",
+			expected: []string{"ok", "invalid", "invalid", "ok"},
+		},
+		"csv without title": {
+			format:   CSV,
+			input:    "name\na\n",
+			expected: []string{"fatal"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			decoder := NewDecoder(strings.NewReader(test.input), test.format)
+
+			var results []string
+
+			for {
+				_, err := decoder.Next()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+
+				switch {
+				case err == nil:
+					results = append(results, "ok")
+				case errors.Is(err, ErrInvalidRow):
+					results = append(results, "invalid")
+				default:
+					results = append(results, "fatal")
+				}
+
+				if results[len(results)-1] == "fatal" {
+					break
+				}
+			}
+
+			if !reflect.DeepEqual(results, test.expected) {
+				t.Fatalf("test-case: (%q); returned %v; expected %v", name, results, test.expected)
+			}
+		})
+	}
+}
+
+func readAll(decoder Decoder) ([]models.ImportTaskRequest, error) {
+	var rows []models.ImportTaskRequest
+
+	for {
+		row, err := decoder.Next()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		rows = append(rows, row)
+	}
+}
@@ -0,0 +1,5 @@
+ALTER TABLE tasks
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at::TIMESTAMPTZ,
+    ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at::TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS tasks_created_at_idx ON tasks (created_at);
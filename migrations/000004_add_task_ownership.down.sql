@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS tasks_assignee_idx;
+
+ALTER TABLE tasks
+    DROP COLUMN IF EXISTS assignee,
+    DROP COLUMN IF EXISTS created_by;
@@ -0,0 +1,5 @@
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS created_by TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS assignee TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS tasks_assignee_idx ON tasks (assignee);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS tasks_workspace_created_at_idx;
+CREATE INDEX IF NOT EXISTS tasks_created_at_idx ON tasks (created_at);
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS workspace_id;
+
+DROP TABLE IF EXISTS workspace_members;
+DROP TABLE IF EXISTS workspaces;
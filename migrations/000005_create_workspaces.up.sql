@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS workspaces (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+INSERT INTO workspaces (id, name) VALUES ('default', 'Default') ON CONFLICT (id) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS workspace_members (
+    workspace_id TEXT NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+    subject TEXT NOT NULL,
+    role TEXT NOT NULL,
+    PRIMARY KEY (workspace_id, subject)
+);
+
+CREATE INDEX IF NOT EXISTS workspace_members_subject_idx ON workspace_members (subject);
+
+-- Existing tasks move into the default workspace; new ones must name theirs.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS workspace_id TEXT NOT NULL DEFAULT 'default' REFERENCES workspaces (id);
+ALTER TABLE tasks ALTER COLUMN workspace_id DROP DEFAULT;
+
+DROP INDEX IF EXISTS tasks_created_at_idx;
+CREATE INDEX IF NOT EXISTS tasks_workspace_created_at_idx ON tasks (workspace_id, created_at);
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS tasks_series_occurrence_idx;
+DROP INDEX IF EXISTS tasks_series_heads_idx;
+
+ALTER TABLE tasks
+    DROP COLUMN IF EXISTS advanced,
+    DROP COLUMN IF EXISTS occurrence_at,
+    DROP COLUMN IF EXISTS series_id,
+    DROP COLUMN IF EXISTS time_zone,
+    DROP COLUMN IF EXISTS rrule,
+    DROP COLUMN IF EXISTS due_at;
@@ -0,0 +1,13 @@
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS due_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS rrule TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS time_zone TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS series_id TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS occurrence_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS advanced BOOLEAN NOT NULL DEFAULT false;
+
+-- The scheduler polls the latest occurrence of every series.
+CREATE INDEX IF NOT EXISTS tasks_series_heads_idx ON tasks (series_id) WHERE series_id <> '' AND NOT advanced;
+
+-- Stops two instances from adding the same occurrence twice.
+CREATE UNIQUE INDEX IF NOT EXISTS tasks_series_occurrence_idx ON tasks (series_id, occurrence_at) WHERE series_id <> '';
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS notification_digests;
+
+DROP INDEX IF EXISTS tasks_due_at_idx;
+DROP INDEX IF EXISTS tasks_next_reminder_at_idx;
+
+ALTER TABLE tasks
+    DROP COLUMN IF EXISTS next_reminder_at,
+    DROP COLUMN IF EXISTS reminded_at,
+    DROP COLUMN IF EXISTS reminders;
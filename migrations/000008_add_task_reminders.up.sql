@@ -0,0 +1,12 @@
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS reminders BIGINT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS reminded_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS next_reminder_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS tasks_next_reminder_at_idx ON tasks (next_reminder_at) WHERE next_reminder_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS tasks_due_at_idx ON tasks (due_at) WHERE due_at IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS notification_digests (
+    day DATE PRIMARY KEY,
+    sent_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS feed_tokens (
+    id TEXT PRIMARY KEY,
+    subject TEXT NOT NULL,
+    hash TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    last_used_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS feed_tokens_subject_idx ON feed_tokens (subject);
@@ -0,0 +1,3 @@
+ALTER TABLE tasks
+    DROP COLUMN IF EXISTS calendar_name,
+    DROP COLUMN IF EXISTS calendar_uid;
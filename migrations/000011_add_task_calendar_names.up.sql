@@ -0,0 +1,3 @@
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS calendar_uid TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS calendar_name TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS tasks_calendar_uid_idx;
+DROP INDEX IF EXISTS tasks_calendar_name_idx;
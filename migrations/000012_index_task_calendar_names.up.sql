@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS tasks_calendar_name_idx ON tasks (workspace_id, calendar_name) WHERE calendar_name <> '';
+CREATE INDEX IF NOT EXISTS tasks_calendar_uid_idx ON tasks (workspace_id, calendar_uid) WHERE calendar_uid <> '';
@@ -0,0 +1,40 @@
+package httptests
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"task-tracker/tests/testutils"
+)
+
+func TestAuthentication(t *testing.T) {
+	t.Run("happy path - test issuer token grants access", func(t *testing.T) {
+		t.Parallel()
+
+		env := testutils.SetupAuthIntegrationTest(t)
+
+		body := strings.NewReader(`{"title":"Test Task","description":"Some task to test","status":"Todo"}`)
+		resp, err := env.Server.Handle(http.MethodPost, "/tasks", body, env.Token(t, "alice", "member"))
+		require.NoErrorf(t, err, "failed to send post request: %v", err)
+
+		defer resp.Body.Close()
+
+		require.Equalf(t, http.StatusCreated, resp.StatusCode, "expected status %d, got %d", http.StatusCreated, resp.StatusCode)
+	})
+
+	t.Run("unhappy path - missing token", func(t *testing.T) {
+		t.Parallel()
+
+		env := testutils.SetupAuthIntegrationTest(t)
+
+		resp, err := env.Server.Handle(http.MethodGet, "/tasks", http.NoBody, nil)
+		require.NoErrorf(t, err, "failed to send get request: %v", err)
+
+		defer resp.Body.Close()
+
+		require.Equalf(t, http.StatusUnauthorized, resp.StatusCode, "expected status %d, got %d", http.StatusUnauthorized, resp.StatusCode)
+	})
+}
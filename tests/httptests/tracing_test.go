@@ -0,0 +1,62 @@
+package httptests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"task-tracker/internal/models"
+	"task-tracker/internal/server"
+	"task-tracker/internal/tracing"
+	"task-tracker/tests/testutils"
+)
+
+func TestCreateTaskTracing(t *testing.T) {
+	t.Run("happy path - create task produces span tree", func(t *testing.T) {
+		t.Parallel()
+
+		exporter := tracetest.NewInMemoryExporter()
+		provider := tracing.NewSDKProvider(trace.WithSyncer(exporter))
+
+		env := testutils.SetupIntegrationTest(t, server.WithTracerProvider(provider))
+
+		task := models.CreateTaskRequest{
+			Title:       "Traced Task",
+			Description: "Some task to trace",
+			Status:      "todo",
+		}
+
+		body, err := json.Marshal(task)
+		require.NoErrorf(t, err, "failed to marshal task request: %v", err)
+
+		headers := map[string]string{
+			"Content-Type": "application/json",
+			"traceparent":  "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		}
+		resp, err := env.Server.Handle(http.MethodPost, "/tasks", bytes.NewReader(body), headers)
+		require.NoErrorf(t, err, "failed to send post request: %v", err)
+
+		defer resp.Body.Close()
+
+		require.Equalf(t, http.StatusCreated, resp.StatusCode, "expected status %d, got %d", http.StatusCreated, resp.StatusCode)
+
+		spans := exporter.GetSpans()
+		children := make(map[string][]string)
+		spanIDs := make(map[string]string)
+
+		for _, span := range spans {
+			spanIDs[span.Name] = span.SpanContext.SpanID().String()
+			children[span.Parent.SpanID().String()] = append(children[span.Parent.SpanID().String()], span.Name)
+		}
+
+		require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[len(spans)-1].SpanContext.TraceID().String())
+		require.Equal(t, []string{"POST /tasks"}, children["00f067aa0ba902b7"])
+		require.Equal(t, []string{"TaskService.Add"}, children[spanIDs["POST /tasks"]])
+		require.Equal(t, []string{"SELECT", "BEGIN", "INSERT", "SELECT", "COMMIT"}, children[spanIDs["TaskService.Add"]])
+	})
+}